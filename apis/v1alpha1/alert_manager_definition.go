@@ -24,10 +24,26 @@ import (
 type AlertManagerDefinitionSpec struct {
 
 	// The ID of the workspace in which to create the alert manager definition.
-	// +kubebuilder:validation:Required
-	WorkspaceID *string `json:"workspaceID"`
-	// +kubebuilder:validation:Required
-	Configuration *string `json:"configuration"`
+	WorkspaceID  *string                   `json:"workspaceID,omitempty"`
+	WorkspaceRef *ResourceReferenceWrapper `json:"workspaceRef,omitempty"`
+	// The alert manager definition in the AMP format. Exactly one of
+	// Configuration and ConfigurationSecretRef must be set.
+	Configuration *string `json:"configuration,omitempty"`
+	// A reference to a Secret key holding the alert manager definition. The
+	// namespace of the Secret defaults to the namespace of the resource. The
+	// definition is never copied into the resource, only its hash is recorded
+	// in Status.ConfigurationHash. Exactly one of Configuration and
+	// ConfigurationSecretRef must be set.
+	ConfigurationSecretRef *ackv1alpha1.SecretKeyReference `json:"configurationSecretRef,omitempty"`
+	// Selects the prometheus-operator AlertmanagerConfig objects whose routes,
+	// receivers and inhibit rules are merged into the alert manager
+	// definition, the way prometheus-operator merges them into the
+	// configuration of an Alertmanager. No AlertmanagerConfig is merged when
+	// unset.
+	AlertmanagerConfigSelector *metav1.LabelSelector `json:"alertmanagerConfigSelector,omitempty"`
+	// Selects the namespaces of the AlertmanagerConfig objects to merge. Only
+	// the namespace of the resource is selected when unset.
+	AlertmanagerConfigNamespaceSelector *metav1.LabelSelector `json:"alertmanagerConfigNamespaceSelector,omitempty"`
 }
 
 // AlertManagerDefinitionStatus defines the observed state of AlertManagerDefinition
@@ -43,12 +59,28 @@ type AlertManagerDefinitionStatus struct {
 	// resource
 	// +kubebuilder:validation:Optional
 	Conditions []*ackv1alpha1.Condition `json:"conditions"`
+	// The time at which the resource entered its current CREATING, UPDATING
+	// or DELETING status. It is unset in other statuses.
+	// +kubebuilder:validation:Optional
+	OperationStartTime *metav1.Time `json:"operationStartTime,omitempty"`
 	// Status code of this definition.
 	// +kubebuilder:validation:Optional
 	StatusCode *string `json:"statusCode,omitempty"`
 	// The reason for failure if any.
 	// +kubebuilder:validation:Optional
 	StatusReason *string `json:"statusReason,omitempty"`
+	// The SHA-256 hash of the alert manager definition last pushed to AMP
+	// from the Secret referenced by Spec.ConfigurationSecretRef.
+	// +kubebuilder:validation:Optional
+	ConfigurationHash *string `json:"configurationHash,omitempty"`
+	// The AlertmanagerConfig objects, as namespace/name, merged into the alert
+	// manager definition last pushed to AMP.
+	// +kubebuilder:validation:Optional
+	ImportedAlertmanagerConfigs []*string `json:"importedAlertmanagerConfigs,omitempty"`
+	// The selected AlertmanagerConfig objects that could not be merged into
+	// the alert manager definition.
+	// +kubebuilder:validation:Optional
+	AlertmanagerConfigConflicts []*AlertmanagerConfigConflict `json:"alertmanagerConfigConflicts,omitempty"`
 }
 
 // AlertManagerDefinition is the Schema for the AlertManagerDefinitions API
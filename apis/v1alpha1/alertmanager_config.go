@@ -0,0 +1,26 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// AlertmanagerConfigConflict describes a prometheus-operator
+// AlertmanagerConfig object that could not be merged into an alert manager
+// definition.
+type AlertmanagerConfigConflict struct {
+	// The namespace of the AlertmanagerConfig.
+	Namespace *string `json:"namespace,omitempty"`
+	// The name of the AlertmanagerConfig.
+	Name *string `json:"name,omitempty"`
+	// Why the AlertmanagerConfig could not be merged.
+	Message *string `json:"message,omitempty"`
+}
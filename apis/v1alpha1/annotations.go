@@ -0,0 +1,31 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+const (
+	// AnnotationCascadeDelete, when set to "true" on a Workspace, makes the
+	// deletion of the Workspace delete the RuleGroupsNamespace,
+	// AlertManagerDefinition and LoggingConfiguration resources created in
+	// it. Without it, the deletion of the Workspace waits for them to be
+	// deleted.
+	AnnotationCascadeDelete = "prometheusservice.services.k8s.aws/cascade-delete"
+
+	// AnnotationWorkspaceOwnerReference, when set to "false" on a
+	// RuleGroupsNamespace or AlertManagerDefinition, prevents the controller
+	// from adding an owner reference to the Workspace it is created in.
+	// Resources with such an owner reference are deleted along with their
+	// Workspace. Resources created in a Workspace with the retain deletion
+	// policy never get one.
+	AnnotationWorkspaceOwnerReference = "prometheusservice.services.k8s.aws/workspace-owner-reference"
+)
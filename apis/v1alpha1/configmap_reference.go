@@ -0,0 +1,28 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// ConfigMapKeyReference selects a key of a ConfigMap in the namespace of the
+// referencing resource.
+type ConfigMapKeyReference struct {
+	// The name of the ConfigMap.
+	// +kubebuilder:validation:Required
+	Name *string `json:"name"`
+	// The key of the ConfigMap to select.
+	// +kubebuilder:validation:Required
+	Key *string `json:"key"`
+	// Whether the ConfigMap or its key may be missing. A missing optional
+	// reference is skipped.
+	Optional *bool `json:"optional,omitempty"`
+}
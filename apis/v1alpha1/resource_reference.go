@@ -0,0 +1,31 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// ResourceReferenceWrapper provides all the values necessary to reference
+// another Kubernetes resource for finding its identifier. It has the same
+// shape as the ACK runtime's AWSResourceReferenceWrapper, but the referenced
+// resource may live in a different namespace than the referencing one.
+type ResourceReferenceWrapper struct {
+	From *ResourceReference `json:"from,omitempty"`
+}
+
+// ResourceReference identifies a Kubernetes resource by name and, optionally,
+// namespace.
+type ResourceReference struct {
+	Name *string `json:"name,omitempty"`
+	// The namespace of the referenced resource. Defaults to the namespace of
+	// the referencing resource.
+	Namespace *string `json:"namespace,omitempty"`
+}
@@ -0,0 +1,45 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// RuleGroup is a named list of recording and alerting rules that are
+// evaluated at a regular interval.
+type RuleGroup struct {
+	// The name of the rule group. Must be unique within the namespace.
+	// +kubebuilder:validation:Required
+	Name *string `json:"name"`
+	// How often the rules in the group are evaluated, for example "1m".
+	Interval *string `json:"interval,omitempty"`
+	// +kubebuilder:validation:Required
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule is a single Prometheus recording or alerting rule. Exactly one of
+// Record and Alert must be set.
+type Rule struct {
+	// The name of the time series to output to. Only for recording rules.
+	Record *string `json:"record,omitempty"`
+	// The name of the alert. Only for alerting rules.
+	Alert *string `json:"alert,omitempty"`
+	// The PromQL expression to evaluate.
+	// +kubebuilder:validation:Required
+	Expr *string `json:"expr"`
+	// How long the expression must be true before the alert fires. Only for
+	// alerting rules.
+	For *string `json:"for,omitempty"`
+	// Labels to add or overwrite on the resulting time series or alert.
+	Labels map[string]*string `json:"labels,omitempty"`
+	// Annotations to add to the alert. Only for alerting rules.
+	Annotations map[string]*string `json:"annotations,omitempty"`
+}
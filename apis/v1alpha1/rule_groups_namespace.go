@@ -29,10 +29,20 @@ type RuleGroupsNamespaceSpec struct {
 	// Optional, user-provided tags for this rule groups namespace.
 	Tags map[string]*string `json:"tags,omitempty"`
 	// The ID of the workspace in which to create the rule group namespace.
-	// +kubebuilder:validation:Required
-	WorkspaceID *string `json:"workspaceID"`
-	// +kubebuilder:validation:Required
-	Configuration *string `json:"configuration"`
+	WorkspaceID  *string                   `json:"workspaceID,omitempty"`
+	WorkspaceRef *ResourceReferenceWrapper `json:"workspaceRef,omitempty"`
+	// The rule groups configuration in the AMP (Prometheus rules file) YAML
+	// format. Exactly one of Configuration, Groups and ConfigurationFrom must
+	// be set.
+	Configuration *string `json:"configuration,omitempty"`
+	// The rule groups of the namespace. The controller renders them to the
+	// AMP YAML format. Exactly one of Configuration, Groups and
+	// ConfigurationFrom must be set.
+	Groups []*RuleGroup `json:"groups,omitempty"`
+	// ConfigMap keys holding rule groups configurations in the AMP YAML
+	// format. The rule groups of all keys are concatenated, in order. Exactly
+	// one of Configuration, Groups and ConfigurationFrom must be set.
+	ConfigurationFrom []*ConfigMapKeyReference `json:"configurationFrom,omitempty"`
 }
 
 // RuleGroupsNamespaceStatus defines the observed state of RuleGroupsNamespace
@@ -48,6 +58,10 @@ type RuleGroupsNamespaceStatus struct {
 	// resource
 	// +kubebuilder:validation:Optional
 	Conditions []*ackv1alpha1.Condition `json:"conditions"`
+	// The time at which the resource entered its current CREATING, UPDATING
+	// or DELETING status. It is unset in other statuses.
+	// +kubebuilder:validation:Optional
+	OperationStartTime *metav1.Time `json:"operationStartTime,omitempty"`
 	// The status of rule groups namespace.
 	// +kubebuilder:validation:Optional
 	Status *RuleGroupsNamespaceStatus_SDK `json:"status,omitempty"`
@@ -28,6 +28,10 @@ type WorkspaceSpec struct {
 	Alias *string `json:"alias,omitempty"`
 	// Optional, user-provided tags for this workspace.
 	Tags map[string]*string `json:"tags,omitempty"`
+	// Prevents the deletion of the workspace, and of the metrics ingested
+	// into it, while set to true. Deleting the resource is held back until
+	// the protection is removed.
+	DeletionProtection *bool `json:"deletionProtection,omitempty"`
 }
 
 // WorkspaceStatus defines the observed state of Workspace
@@ -43,6 +47,17 @@ type WorkspaceStatus struct {
 	// resource
 	// +kubebuilder:validation:Optional
 	Conditions []*ackv1alpha1.Condition `json:"conditions"`
+	// The time at which the resource entered its current CREATING, UPDATING
+	// or DELETING status. It is unset in other statuses.
+	// +kubebuilder:validation:Optional
+	OperationStartTime *metav1.Time `json:"operationStartTime,omitempty"`
+	// Prometheus endpoint URI.
+	// +kubebuilder:validation:Optional
+	PrometheusEndpoint *string `json:"prometheusEndpoint,omitempty"`
+	// +kubebuilder:validation:Optional
+	QueryURL *string `json:"queryURL,omitempty"`
+	// +kubebuilder:validation:Optional
+	RemoteWriteURL *string `json:"remoteWriteURL,omitempty"`
 	// The status of the workspace that was just created (usually CREATING).
 	// +kubebuilder:validation:Optional
 	Status *WorkspaceStatus_SDK `json:"status,omitempty"`
@@ -55,6 +70,7 @@ type WorkspaceStatus struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="ALIAS",type=string,priority=0,JSONPath=`.spec.alias`
+// +kubebuilder:printcolumn:name="ENDPOINT",type=string,priority=1,JSONPath=`.status.prometheusEndpoint`
 type Workspace struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
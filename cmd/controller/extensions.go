@@ -0,0 +1,99 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/prometheusrule"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspaceowner"
+)
+
+// This file is maintained by hand. It wires the parts of the controller that
+// ack-generate does not know about into main.go, which is generated from
+// templates/cmd/controller/main.go.tpl.
+
+// extensions contains the configuration of the parts of the controller that
+// are not generated.
+type extensions struct {
+	prometheusRuleCfg prometheusrule.Config
+	statusCfg         ampstatus.Config
+	faults            faultInjection
+
+	// stalledTimeouts are the stalled timeouts configured by statusCfg.
+	stalledTimeouts ampstatus.StalledTimeouts
+}
+
+// bindFlags defines the command line flags of the extensions.
+func (e *extensions) bindFlags() {
+	e.prometheusRuleCfg.BindFlags()
+	e.statusCfg.BindFlags()
+	e.faults.bindFlags()
+}
+
+// configure validates the command line flags of the extensions and applies
+// them, along with the supplied ACK runtime configuration. It must be called
+// before the controller manager is created.
+func (e *extensions) configure(ackCfg ackcfg.Config) error {
+	if err := e.prometheusRuleCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid --prometheus-rule-* flags: %w", err)
+	}
+	stalledTimeouts, err := e.statusCfg.StalledTimeouts()
+	if err != nil {
+		return fmt.Errorf("invalid --stalled-timeout-seconds flag: %w", err)
+	}
+	e.stalledTimeouts = stalledTimeouts
+	if err := e.faults.validate(); err != nil {
+		return err
+	}
+	workspaceowner.DefaultDeletionPolicy = ackCfg.DeletionPolicy
+	return nil
+}
+
+// managerFactories returns the supplied resource manager factories, wrapped
+// so that their resource managers can read the objects of the cluster
+// through the cached client of the supplied manager and use the configured
+// stalled timeouts, and as configured by the command line flags of the
+// extensions.
+func (e *extensions) managerFactories(
+	mgr ctrlrt.Manager,
+	factories []acktypes.AWSResourceManagerFactory,
+) ([]acktypes.AWSResourceManagerFactory, error) {
+	factories = svcresource.WithManagerOptions(factories, svcresource.ManagerOptions{
+		KubeClient:      mgr.GetClient(),
+		StalledTimeouts: e.stalledTimeouts,
+	})
+	return e.faults.wrapManagerFactories(factories)
+}
+
+// setupWithManager sets up the controllers of the extensions. It must be
+// called after the supplied service controller is bound to the supplied
+// manager.
+func (e *extensions) setupWithManager(mgr ctrlrt.Manager, sc acktypes.ServiceController) error {
+	if err := svcresource.SetupControllers(mgr, sc); err != nil {
+		return fmt.Errorf("unable to set up the additional controllers: %w", err)
+	}
+	if e.prometheusRuleCfg.Enabled {
+		if err := prometheusrule.SetupWithManager(mgr, e.prometheusRuleCfg); err != nil {
+			return fmt.Errorf("unable to set up the PrometheusRule sync: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build faults
+
+package main
+
+import (
+	"fmt"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+
+	ampfaults "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/faults"
+)
+
+// This file is only built with the faults build tag, for debugging:
+//
+//	go build -tags faults ./cmd/controller
+//
+// The controller images are built without it, and do not accept the
+// --debug-inject-faults flag.
+
+// faultInjection injects the faults configured with --debug-inject-faults in
+// the calls of the resource managers to the AMP API.
+type faultInjection struct {
+	cfg ampfaults.Config
+}
+
+// bindFlags defines the command line flags of the fault injection.
+func (f *faultInjection) bindFlags() {
+	f.cfg.BindFlags()
+}
+
+// validate ensures the command line flags of the fault injection are valid.
+func (f *faultInjection) validate() error {
+	if err := f.cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid --debug-inject-faults flag: %w", err)
+	}
+	return nil
+}
+
+// wrapManagerFactories returns the supplied resource manager factories,
+// wrapped to inject the configured faults.
+func (f *faultInjection) wrapManagerFactories(
+	factories []acktypes.AWSResourceManagerFactory,
+) ([]acktypes.AWSResourceManagerFactory, error) {
+	factories, err := f.cfg.WrapManagerFactories(factories)
+	if err != nil {
+		return nil, fmt.Errorf("unable to inject faults: %w", err)
+	}
+	if f.cfg.Enabled() {
+		setupLog.Info(
+			"injecting faults in the calls to the AWS API",
+			"aws.service", awsServiceAlias,
+			"faults", f.cfg.Faults,
+		)
+	}
+	return factories, nil
+}
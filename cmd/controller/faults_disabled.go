@@ -0,0 +1,36 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !faults
+
+package main
+
+import (
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+)
+
+// faultInjection does not inject faults: the fault injection is only built
+// with the faults build tag. See faults.go.
+type faultInjection struct{}
+
+func (*faultInjection) bindFlags() {}
+
+func (*faultInjection) validate() error {
+	return nil
+}
+
+func (*faultInjection) wrapManagerFactories(
+	factories []acktypes.AWSResourceManagerFactory,
+) ([]acktypes.AWSResourceManagerFactory, error) {
+	return factories, nil
+}
@@ -35,6 +35,7 @@ import (
 	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
 
 	_ "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource/alert_manager_definition"
+	_ "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource/logging_configuration"
 	_ "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource/rule_groups_namespace"
 	_ "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource/workspace"
 
@@ -58,7 +59,9 @@ func init() {
 
 func main() {
 	var ackCfg ackcfg.Config
+	var ext extensions
 	ackCfg.BindFlags()
+	ext.bindFlags()
 	flag.Parse()
 	ackCfg.SetupLogger()
 
@@ -69,6 +72,13 @@ func main() {
 		)
 		os.Exit(1)
 	}
+	if err := ext.configure(ackCfg); err != nil {
+		setupLog.Error(
+			err, "Unable to configure the controller",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
 
 	host, port, err := ackrtutil.GetHostPort(ackCfg.WebhookServerAddr)
 	if err != nil {
@@ -96,6 +106,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	managerFactories, err := ext.managerFactories(mgr, svcresource.GetManagerFactories())
+	if err != nil {
+		setupLog.Error(
+			err, "unable to create the resource manager factories",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
 	stopChan := ctrlrt.SetupSignalHandler()
 
 	setupLog.Info(
@@ -112,7 +131,7 @@ func main() {
 	).WithLogger(
 		ctrlrt.Log,
 	).WithResourceManagerFactories(
-		svcresource.GetManagerFactories(),
+		managerFactories,
 	).WithPrometheusRegistry(
 		ctrlrtmetrics.Registry,
 	)
@@ -138,6 +157,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = ext.setupWithManager(mgr, sc); err != nil {
+		setupLog.Error(
+			err, "unable to set up the controller extensions",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
 	setupLog.Info(
 		"starting manager",
 		"aws.service", awsServiceAlias,
@@ -0,0 +1,190 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+// alertManagerDefinition is a fake alert manager definition.
+type alertManagerDefinition struct {
+	lifecycle
+	// data is the last valid data, returned by the API.
+	data []byte
+}
+
+func (amd *alertManagerDefinition) status() *svcsdk.AlertManagerDefinitionStatus {
+	status := &svcsdk.AlertManagerDefinitionStatus{StatusCode: aws.String(amd.statusCode)}
+	if amd.statusReason != "" {
+		status.StatusReason = aws.String(amd.statusReason)
+	}
+	return status
+}
+
+// alertManagerDefinition returns the alert manager definition of the
+// supplied workspace and the workspace, or a ResourceNotFoundException if
+// either does not exist. It must be called with the mutex held.
+func (a *API) alertManagerDefinition(
+	workspaceID *string,
+) (*workspace, *alertManagerDefinition, error) {
+	ws, ok := a.workspaces[aws.StringValue(workspaceID)]
+	if !ok {
+		return nil, nil, notFoundError("Workspace not found: %s", aws.StringValue(workspaceID))
+	}
+	if ws.alertManagerDefinition == nil {
+		return nil, nil, notFoundError("Alert manager definition not found in workspace %s", ws.id)
+	}
+	return ws, ws.alertManagerDefinition, nil
+}
+
+// putAlertManagerDefinitionData starts the creation or update of the
+// supplied alert manager definition with the supplied data, moving it to the
+// supplied status. It must be called with the mutex held.
+func (a *API) putAlertManagerDefinitionData(
+	amd *alertManagerDefinition,
+	statusCode string,
+	failedStatusCode string,
+	data []byte,
+) {
+	data = copyBytes(data)
+	amd.start(statusCode, a.Now(), func() {
+		var err error
+		if a.ValidateAlertManagerDefinition != nil {
+			err = a.ValidateAlertManagerDefinition(data)
+		}
+		if err == nil {
+			amd.data = data
+		}
+		amd.finish(svcsdk.AlertManagerDefinitionStatusCodeActive, failedStatusCode, err)
+	})
+}
+
+func (a *API) CreateAlertManagerDefinitionWithContext(
+	_ aws.Context,
+	input *svcsdk.CreateAlertManagerDefinitionInput,
+	_ ...request.Option,
+) (*svcsdk.CreateAlertManagerDefinitionOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("CreateAlertManagerDefinition")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, err := a.activeWorkspace(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if ws.alertManagerDefinition != nil {
+		return nil, conflictError("Alert manager definition already exists in workspace %s", ws.id)
+	}
+	amd := &alertManagerDefinition{}
+	amd.createdAt = a.Now()
+	a.putAlertManagerDefinitionData(
+		amd,
+		svcsdk.AlertManagerDefinitionStatusCodeCreating,
+		svcsdk.AlertManagerDefinitionStatusCodeCreationFailed,
+		input.Data,
+	)
+	ws.alertManagerDefinition = amd
+
+	return &svcsdk.CreateAlertManagerDefinitionOutput{Status: amd.status()}, nil
+}
+
+func (a *API) DescribeAlertManagerDefinitionWithContext(
+	_ aws.Context,
+	input *svcsdk.DescribeAlertManagerDefinitionInput,
+	_ ...request.Option,
+) (*svcsdk.DescribeAlertManagerDefinitionOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DescribeAlertManagerDefinition")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	_, amd, err := a.alertManagerDefinition(input.WorkspaceId)
+	if err == nil {
+		a.observe(&amd.lifecycle)
+		_, amd, err = a.alertManagerDefinition(input.WorkspaceId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &svcsdk.DescribeAlertManagerDefinitionOutput{
+		AlertManagerDefinition: &svcsdk.AlertManagerDefinitionDescription{
+			CreatedAt:  timePtr(amd.createdAt),
+			Data:       copyBytes(amd.data),
+			ModifiedAt: timePtr(amd.modifiedAt),
+			Status:     amd.status(),
+		},
+	}, nil
+}
+
+func (a *API) PutAlertManagerDefinitionWithContext(
+	_ aws.Context,
+	input *svcsdk.PutAlertManagerDefinitionInput,
+	_ ...request.Option,
+) (*svcsdk.PutAlertManagerDefinitionOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("PutAlertManagerDefinition")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	_, amd, err := a.alertManagerDefinition(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if amd.busy() {
+		return nil, conflictError("Alert manager definition is in %s state", amd.statusCode)
+	}
+	a.putAlertManagerDefinitionData(
+		amd,
+		svcsdk.AlertManagerDefinitionStatusCodeUpdating,
+		svcsdk.AlertManagerDefinitionStatusCodeUpdateFailed,
+		input.Data,
+	)
+
+	return &svcsdk.PutAlertManagerDefinitionOutput{Status: amd.status()}, nil
+}
+
+func (a *API) DeleteAlertManagerDefinitionWithContext(
+	_ aws.Context,
+	input *svcsdk.DeleteAlertManagerDefinitionInput,
+	_ ...request.Option,
+) (*svcsdk.DeleteAlertManagerDefinitionOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DeleteAlertManagerDefinition")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, amd, err := a.alertManagerDefinition(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if amd.busy() {
+		return nil, conflictError("Alert manager definition is in %s state", amd.statusCode)
+	}
+	amd.start(svcsdk.AlertManagerDefinitionStatusCodeDeleting, a.Now(), func() {
+		ws.alertManagerDefinition = nil
+	})
+	return &svcsdk.DeleteAlertManagerDefinitionOutput{}, nil
+}
@@ -0,0 +1,249 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package fake provides an in-memory fake of the AMP API, for the tests of
+// the resource managers and of the controller. It models the workspaces and
+// the resources created in them, their tags, the asynchronous completion of
+// their creation, update and deletion, and the errors the API returns.
+package fake
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+)
+
+// API is an in-memory fake of the AMP API. It is safe for concurrent use.
+//
+// Like AMP, the API returns resources in the CREATING, UPDATING or DELETING
+// status right after their creation, update or deletion is requested. The
+// operation completes, possibly failing, once the resource was described
+// Transitions times in this status, or when Settle is called.
+//
+// The operations the resource managers don't use are not implemented and
+// panic.
+type API struct {
+	svcsdkapi.PrometheusServiceAPI
+
+	// Transitions is the number of times a resource is described in the
+	// status of an ongoing operation before the operation completes. The
+	// operations complete at the first description if it is zero.
+	Transitions int
+
+	// ValidateWorkspace validates the alias of a workspace when its
+	// creation completes. The creation fails with the message of the error
+	// as status reason if an error is returned.
+	ValidateWorkspace func(alias *string) error
+	// ValidateRuleGroupsNamespace validates the data of a rule groups
+	// namespace when its creation or update completes. The operation fails
+	// with the message of the error as status reason if an error is
+	// returned.
+	ValidateRuleGroupsNamespace func(data []byte) error
+	// ValidateAlertManagerDefinition validates the data of an alert manager
+	// definition when its creation or update completes. The operation fails
+	// with the message of the error as status reason if an error is
+	// returned.
+	ValidateAlertManagerDefinition func(data []byte) error
+	// ValidateLogGroup validates the log group of a logging configuration
+	// when its creation or update completes. The operation fails with the
+	// message of the error as status reason if an error is returned.
+	ValidateLogGroup func(logGroupARN string) error
+
+	// Now returns the current time, used for the creation and modification
+	// times of the resources.
+	Now func() time.Time
+
+	accountID string
+	region    string
+
+	mu         sync.Mutex
+	workspaces map[string]*workspace
+	calls      []string
+}
+
+// New returns an empty fake of the AMP API of the supplied AWS account and
+// region.
+func New(accountID string, region string) *API {
+	return &API{
+		Now:        time.Now,
+		accountID:  accountID,
+		region:     region,
+		workspaces: map[string]*workspace{},
+	}
+}
+
+// Calls returns the names of the operations called so far, in order.
+func (a *API) Calls() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string{}, a.calls...)
+}
+
+// Settle completes all the ongoing operations.
+func (a *API) Settle() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, ws := range a.workspaces {
+		for _, rgn := range ws.ruleGroupsNamespaces {
+			rgn.complete()
+		}
+		if ws.alertManagerDefinition != nil {
+			ws.alertManagerDefinition.complete()
+		}
+		if ws.loggingConfiguration != nil {
+			ws.loggingConfiguration.complete()
+		}
+		ws.complete()
+	}
+}
+
+// call records a call to the supplied operation. It must be called with the
+// mutex held.
+func (a *API) call(operation string) {
+	a.calls = append(a.calls, operation)
+}
+
+// operation is an ongoing creation, update or deletion.
+type operation struct {
+	// described is the number of times the resource was described since
+	// the operation started.
+	described int
+	// done completes the operation.
+	done func()
+}
+
+// lifecycle is the status of a resource and its ongoing operation, if any.
+type lifecycle struct {
+	statusCode   string
+	statusReason string
+	createdAt    time.Time
+	modifiedAt   time.Time
+	op           *operation
+}
+
+// busy returns true if an operation is ongoing.
+func (l *lifecycle) busy() bool {
+	return l.op != nil
+}
+
+// start moves the resource to the supplied status of an ongoing operation.
+// The supplied function completes the operation.
+func (l *lifecycle) start(statusCode string, now time.Time, done func()) {
+	l.statusCode = statusCode
+	l.statusReason = ""
+	l.modifiedAt = now
+	l.op = &operation{done: done}
+}
+
+// finish moves the resource to the supplied status once its operation
+// completed, and to the supplied failed status with the message of the
+// supplied error as reason if the error is not nil.
+func (l *lifecycle) finish(statusCode string, failedStatusCode string, err error) {
+	l.statusCode = statusCode
+	l.statusReason = ""
+	if err != nil {
+		l.statusCode = failedStatusCode
+		l.statusReason = err.Error()
+	}
+}
+
+// complete completes the ongoing operation, if any.
+func (l *lifecycle) complete() {
+	if op := l.op; op != nil {
+		l.op = nil
+		op.done()
+	}
+}
+
+// observe records that the resource was described, completing its ongoing
+// operation if it was described Transitions times.
+func (a *API) observe(l *lifecycle) {
+	if l.op == nil {
+		return
+	}
+	if l.op.described < a.Transitions {
+		l.op.described++
+		return
+	}
+	l.complete()
+}
+
+// newError returns an error with the supplied error code and HTTP status
+// code, as returned by the AWS SDK.
+func newError(code string, statusCode int, format string, args ...interface{}) error {
+	return awserr.NewRequestFailure(
+		awserr.New(code, fmt.Sprintf(format, args...), nil),
+		statusCode, "",
+	)
+}
+
+// validationError returns the ValidationException returned for invalid input
+// parameters.
+func validationError(err error) error {
+	return newError(svcsdk.ErrCodeValidationException, http.StatusBadRequest, "%s", err.Error())
+}
+
+// conflictError returns the ConflictException returned for resources that
+// cannot be modified in their current status, or that already exist.
+func conflictError(format string, args ...interface{}) error {
+	return newError(svcsdk.ErrCodeConflictException, http.StatusConflict, format, args...)
+}
+
+// notFoundError returns the ResourceNotFoundException returned for resources
+// that do not exist.
+func notFoundError(format string, args ...interface{}) error {
+	return newError(svcsdk.ErrCodeResourceNotFoundException, http.StatusNotFound, format, args...)
+}
+
+// newWorkspaceID returns a new random workspace ID. IDs are random rather
+// than sequential because the status of workspaces is cached by ID across
+// resource managers.
+func newWorkspaceID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("ws-%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// copyTags returns a copy of the supplied tags, or nil if there are none.
+func copyTags(tags map[string]*string) map[string]*string {
+	if len(tags) == 0 {
+		return nil
+	}
+	copied := make(map[string]*string, len(tags))
+	for k, v := range tags {
+		value := *v
+		copied[k] = &value
+	}
+	return copied
+}
+
+// copyBytes returns a copy of the supplied bytes, or nil if there are none.
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// timePtr returns a pointer to a copy of the supplied time.
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,420 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+// errorCode returns the AWS error code of the supplied error, or an empty
+// string if it is not an AWS error.
+func errorCode(err error) string {
+	if awsErr, ok := ackerr.AWSError(err); ok {
+		return awsErr.Code()
+	}
+	return ""
+}
+
+// newActiveWorkspace creates an ACTIVE workspace and returns its ID.
+func newActiveWorkspace(t *testing.T, api *API) string {
+	t.Helper()
+	resp, err := api.CreateWorkspaceWithContext(context.TODO(), &svcsdk.CreateWorkspaceInput{
+		Tags: map[string]*string{"team": aws.String("a")},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+	api.Settle()
+	return *resp.WorkspaceId
+}
+
+func TestWorkspaceLifecycle(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	api.Transitions = 1
+
+	created, err := api.CreateWorkspaceWithContext(context.TODO(), &svcsdk.CreateWorkspaceInput{
+		Alias: aws.String("alias"),
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+	if *created.Status.StatusCode != svcsdk.WorkspaceStatusCodeCreating {
+		t.Errorf("CreateWorkspace() status = %s, want CREATING", *created.Status.StatusCode)
+	}
+	wantARN := "arn:aws:aps:us-west-2:111111111111:workspace/" + *created.WorkspaceId
+	if *created.Arn != wantARN {
+		t.Errorf("CreateWorkspace() ARN = %s, want %s", *created.Arn, wantARN)
+	}
+
+	describe := func() (*svcsdk.WorkspaceDescription, error) {
+		resp, err := api.DescribeWorkspaceWithContext(context.TODO(), &svcsdk.DescribeWorkspaceInput{
+			WorkspaceId: created.WorkspaceId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Workspace, nil
+	}
+	for _, want := range []string{
+		svcsdk.WorkspaceStatusCodeCreating,
+		svcsdk.WorkspaceStatusCodeActive,
+	} {
+		ws, err := describe()
+		if err != nil {
+			t.Fatalf("DescribeWorkspace() error = %v", err)
+		}
+		if *ws.Status.StatusCode != want {
+			t.Errorf("DescribeWorkspace() status = %s, want %s", *ws.Status.StatusCode, want)
+		}
+	}
+
+	_, err = api.UpdateWorkspaceAliasWithContext(context.TODO(), &svcsdk.UpdateWorkspaceAliasInput{
+		WorkspaceId: created.WorkspaceId,
+		Alias:       aws.String("new-alias"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateWorkspaceAlias() error = %v", err)
+	}
+	// The workspace cannot be modified while it is UPDATING.
+	_, err = api.UpdateWorkspaceAliasWithContext(context.TODO(), &svcsdk.UpdateWorkspaceAliasInput{
+		WorkspaceId: created.WorkspaceId,
+	})
+	if code := errorCode(err); code != svcsdk.ErrCodeConflictException {
+		t.Errorf("UpdateWorkspaceAlias() error = %v, want a ConflictException", err)
+	}
+	api.Settle()
+	ws, err := describe()
+	if err != nil {
+		t.Fatalf("DescribeWorkspace() error = %v", err)
+	}
+	if *ws.Alias != "new-alias" || *ws.Status.StatusCode != svcsdk.WorkspaceStatusCodeActive {
+		t.Errorf("DescribeWorkspace() = %s %s, want new-alias ACTIVE", *ws.Alias, *ws.Status.StatusCode)
+	}
+
+	if _, err = api.DeleteWorkspaceWithContext(context.TODO(), &svcsdk.DeleteWorkspaceInput{
+		WorkspaceId: created.WorkspaceId,
+	}); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	if ws, err = describe(); err != nil || *ws.Status.StatusCode != svcsdk.WorkspaceStatusCodeDeleting {
+		t.Errorf("DescribeWorkspace() = %v, %v, want a DELETING workspace", ws, err)
+	}
+	if _, err = describe(); errorCode(err) != svcsdk.ErrCodeResourceNotFoundException {
+		t.Errorf("DescribeWorkspace() error = %v, want a ResourceNotFoundException", err)
+	}
+}
+
+func TestWorkspaceCreationFailed(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	api.ValidateWorkspace = func(*string) error { return errors.New("quota exceeded") }
+	id := newActiveWorkspace(t, api)
+
+	resp, err := api.DescribeWorkspaceWithContext(context.TODO(), &svcsdk.DescribeWorkspaceInput{
+		WorkspaceId: &id,
+	})
+	if err != nil {
+		t.Fatalf("DescribeWorkspace() error = %v", err)
+	}
+	if *resp.Workspace.Status.StatusCode != svcsdk.WorkspaceStatusCodeCreationFailed {
+		t.Errorf("DescribeWorkspace() status = %s, want CREATION_FAILED", *resp.Workspace.Status.StatusCode)
+	}
+	// A failed workspace cannot be updated, but can be deleted.
+	_, err = api.UpdateWorkspaceAliasWithContext(context.TODO(), &svcsdk.UpdateWorkspaceAliasInput{
+		WorkspaceId: &id,
+	})
+	if errorCode(err) != svcsdk.ErrCodeConflictException {
+		t.Errorf("UpdateWorkspaceAlias() error = %v, want a ConflictException", err)
+	}
+	if _, err = api.DeleteWorkspaceWithContext(context.TODO(), &svcsdk.DeleteWorkspaceInput{
+		WorkspaceId: &id,
+	}); err != nil {
+		t.Errorf("DeleteWorkspace() error = %v", err)
+	}
+}
+
+func TestRuleGroupsNamespaceLifecycle(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	api.ValidateRuleGroupsNamespace = func(data []byte) error {
+		if string(data) == "invalid" {
+			return errors.New("error validating rules")
+		}
+		return nil
+	}
+	id := newActiveWorkspace(t, api)
+
+	describe := func() *svcsdk.RuleGroupsNamespaceDescription {
+		t.Helper()
+		resp, err := api.DescribeRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.DescribeRuleGroupsNamespaceInput{
+			WorkspaceId: &id,
+			Name:        aws.String("rules"),
+		})
+		if err != nil {
+			t.Fatalf("DescribeRuleGroupsNamespace() error = %v", err)
+		}
+		return resp.RuleGroupsNamespace
+	}
+	put := func(data string) error {
+		_, err := api.PutRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.PutRuleGroupsNamespaceInput{
+			WorkspaceId: &id,
+			Name:        aws.String("rules"),
+			Data:        []byte(data),
+		})
+		return err
+	}
+
+	created, err := api.CreateRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.CreateRuleGroupsNamespaceInput{
+		WorkspaceId: &id,
+		Name:        aws.String("rules"),
+		Data:        []byte("invalid"),
+	})
+	if err != nil {
+		t.Fatalf("CreateRuleGroupsNamespace() error = %v", err)
+	}
+	if *created.Status.StatusCode != svcsdk.RuleGroupsNamespaceStatusCodeCreating {
+		t.Errorf("CreateRuleGroupsNamespace() status = %s, want CREATING", *created.Status.StatusCode)
+	}
+	if err = put("valid"); errorCode(err) != svcsdk.ErrCodeConflictException {
+		t.Errorf("PutRuleGroupsNamespace() error = %v, want a ConflictException", err)
+	}
+
+	// The creation failed: no data is returned.
+	rgn := describe()
+	if *rgn.Status.StatusCode != svcsdk.RuleGroupsNamespaceStatusCodeCreationFailed ||
+		*rgn.Status.StatusReason != "error validating rules" || rgn.Data != nil {
+		t.Errorf("DescribeRuleGroupsNamespace() = %v, want a CREATION_FAILED rule groups namespace", rgn)
+	}
+
+	// The rule groups namespace is fixed.
+	if err = put("valid"); err != nil {
+		t.Fatalf("PutRuleGroupsNamespace() error = %v", err)
+	}
+	if rgn = describe(); *rgn.Status.StatusCode != svcsdk.RuleGroupsNamespaceStatusCodeActive || string(rgn.Data) != "valid" {
+		t.Errorf("DescribeRuleGroupsNamespace() = %v, want an ACTIVE rule groups namespace", rgn)
+	}
+
+	// The update failed: the last valid data is returned.
+	if err = put("invalid"); err != nil {
+		t.Fatalf("PutRuleGroupsNamespace() error = %v", err)
+	}
+	if rgn = describe(); *rgn.Status.StatusCode != svcsdk.RuleGroupsNamespaceStatusCodeUpdateFailed || string(rgn.Data) != "valid" {
+		t.Errorf("DescribeRuleGroupsNamespace() = %v, want an UPDATE_FAILED rule groups namespace", rgn)
+	}
+
+	_, err = api.CreateRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.CreateRuleGroupsNamespaceInput{
+		WorkspaceId: &id,
+		Name:        aws.String("rules"),
+		Data:        []byte("valid"),
+	})
+	if errorCode(err) != svcsdk.ErrCodeConflictException {
+		t.Errorf("CreateRuleGroupsNamespace() error = %v, want a ConflictException", err)
+	}
+
+	if _, err = api.DeleteRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.DeleteRuleGroupsNamespaceInput{
+		WorkspaceId: &id,
+		Name:        aws.String("rules"),
+	}); err != nil {
+		t.Fatalf("DeleteRuleGroupsNamespace() error = %v", err)
+	}
+	_, err = api.DescribeRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.DescribeRuleGroupsNamespaceInput{
+		WorkspaceId: &id,
+		Name:        aws.String("rules"),
+	})
+	if errorCode(err) != svcsdk.ErrCodeResourceNotFoundException {
+		t.Errorf("DescribeRuleGroupsNamespace() error = %v, want a ResourceNotFoundException", err)
+	}
+}
+
+func TestAlertManagerDefinitionLifecycle(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	api.Transitions = 1
+	id := newActiveWorkspace(t, api)
+
+	created, err := api.CreateAlertManagerDefinitionWithContext(context.TODO(), &svcsdk.CreateAlertManagerDefinitionInput{
+		WorkspaceId: &id,
+		Data:        []byte("alertmanager_config: ''"),
+	})
+	if err != nil {
+		t.Fatalf("CreateAlertManagerDefinition() error = %v", err)
+	}
+	if *created.Status.StatusCode != svcsdk.AlertManagerDefinitionStatusCodeCreating {
+		t.Errorf("CreateAlertManagerDefinition() status = %s, want CREATING", *created.Status.StatusCode)
+	}
+	_, err = api.DeleteAlertManagerDefinitionWithContext(context.TODO(), &svcsdk.DeleteAlertManagerDefinitionInput{
+		WorkspaceId: &id,
+	})
+	if errorCode(err) != svcsdk.ErrCodeConflictException {
+		t.Errorf("DeleteAlertManagerDefinition() error = %v, want a ConflictException", err)
+	}
+	_, err = api.CreateAlertManagerDefinitionWithContext(context.TODO(), &svcsdk.CreateAlertManagerDefinitionInput{
+		WorkspaceId: &id,
+		Data:        []byte("alertmanager_config: ''"),
+	})
+	if errorCode(err) != svcsdk.ErrCodeConflictException {
+		t.Errorf("CreateAlertManagerDefinition() error = %v, want a ConflictException", err)
+	}
+
+	var statusCodes []string
+	for i := 0; i < 2; i++ {
+		resp, err := api.DescribeAlertManagerDefinitionWithContext(context.TODO(), &svcsdk.DescribeAlertManagerDefinitionInput{
+			WorkspaceId: &id,
+		})
+		if err != nil {
+			t.Fatalf("DescribeAlertManagerDefinition() error = %v", err)
+		}
+		statusCodes = append(statusCodes, *resp.AlertManagerDefinition.Status.StatusCode)
+	}
+	want := []string{
+		svcsdk.AlertManagerDefinitionStatusCodeCreating,
+		svcsdk.AlertManagerDefinitionStatusCodeActive,
+	}
+	if !reflect.DeepEqual(statusCodes, want) {
+		t.Errorf("DescribeAlertManagerDefinition() status codes = %v, want %v", statusCodes, want)
+	}
+}
+
+func TestLoggingConfigurationLifecycle(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	api.ValidateLogGroup = func(arn string) error {
+		if arn != "arn:aws:logs:us-west-2:111111111111:log-group:amp:*" {
+			return errors.New("log group not found")
+		}
+		return nil
+	}
+	id := newActiveWorkspace(t, api)
+
+	if _, err := api.CreateLoggingConfigurationWithContext(context.TODO(), &svcsdk.CreateLoggingConfigurationInput{
+		WorkspaceId: &id,
+		LogGroupArn: aws.String("arn:aws:logs:us-west-2:111111111111:log-group:missing:*"),
+	}); err != nil {
+		t.Fatalf("CreateLoggingConfiguration() error = %v", err)
+	}
+	api.Settle()
+	resp, err := api.DescribeLoggingConfigurationWithContext(context.TODO(), &svcsdk.DescribeLoggingConfigurationInput{
+		WorkspaceId: &id,
+	})
+	if err != nil {
+		t.Fatalf("DescribeLoggingConfiguration() error = %v", err)
+	}
+	// The log group is returned even though the creation failed.
+	lc := resp.LoggingConfiguration
+	if *lc.Status.StatusCode != svcsdk.LoggingConfigurationStatusCodeCreationFailed ||
+		*lc.LogGroupArn != "arn:aws:logs:us-west-2:111111111111:log-group:missing:*" || *lc.Workspace != id {
+		t.Errorf("DescribeLoggingConfiguration() = %v, want a CREATION_FAILED logging configuration", lc)
+	}
+
+	if _, err = api.UpdateLoggingConfigurationWithContext(context.TODO(), &svcsdk.UpdateLoggingConfigurationInput{
+		WorkspaceId: &id,
+		LogGroupArn: aws.String("arn:aws:logs:us-west-2:111111111111:log-group:amp:*"),
+	}); err != nil {
+		t.Fatalf("UpdateLoggingConfiguration() error = %v", err)
+	}
+	api.Settle()
+	resp, err = api.DescribeLoggingConfigurationWithContext(context.TODO(), &svcsdk.DescribeLoggingConfigurationInput{
+		WorkspaceId: &id,
+	})
+	if err != nil || *resp.LoggingConfiguration.Status.StatusCode != svcsdk.LoggingConfigurationStatusCodeActive {
+		t.Errorf("DescribeLoggingConfiguration() = %v, %v, want an ACTIVE logging configuration", resp, err)
+	}
+}
+
+func TestResourcesRequireActiveWorkspace(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	resp, err := api.CreateWorkspaceWithContext(context.TODO(), &svcsdk.CreateWorkspaceInput{})
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+
+	_, err = api.CreateRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.CreateRuleGroupsNamespaceInput{
+		WorkspaceId: resp.WorkspaceId,
+		Name:        aws.String("rules"),
+		Data:        []byte("groups: []"),
+	})
+	if errorCode(err) != svcsdk.ErrCodeConflictException {
+		t.Errorf("CreateRuleGroupsNamespace() error = %v, want a ConflictException", err)
+	}
+	_, err = api.CreateAlertManagerDefinitionWithContext(context.TODO(), &svcsdk.CreateAlertManagerDefinitionInput{
+		WorkspaceId: aws.String("ws-missing"),
+		Data:        []byte("alertmanager_config: ''"),
+	})
+	if errorCode(err) != svcsdk.ErrCodeResourceNotFoundException {
+		t.Errorf("CreateAlertManagerDefinition() error = %v, want a ResourceNotFoundException", err)
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	_, err := api.CreateRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.CreateRuleGroupsNamespaceInput{
+		WorkspaceId: aws.String("ws-1"),
+	})
+	if errorCode(err) != svcsdk.ErrCodeValidationException {
+		t.Errorf("CreateRuleGroupsNamespace() error = %v, want a ValidationException", err)
+	}
+}
+
+func TestTags(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	id := newActiveWorkspace(t, api)
+	resp, err := api.DescribeWorkspaceWithContext(context.TODO(), &svcsdk.DescribeWorkspaceInput{
+		WorkspaceId: &id,
+	})
+	if err != nil {
+		t.Fatalf("DescribeWorkspace() error = %v", err)
+	}
+	arn := resp.Workspace.Arn
+
+	if _, err = api.TagResourceWithContext(context.TODO(), &svcsdk.TagResourceInput{
+		ResourceArn: arn,
+		Tags:        map[string]*string{"team": aws.String("b"), "env": aws.String("test")},
+	}); err != nil {
+		t.Fatalf("TagResource() error = %v", err)
+	}
+	if _, err = api.UntagResourceWithContext(context.TODO(), &svcsdk.UntagResourceInput{
+		ResourceArn: arn,
+		TagKeys:     []*string{aws.String("env")},
+	}); err != nil {
+		t.Fatalf("UntagResource() error = %v", err)
+	}
+	tags, err := api.ListTagsForResourceWithContext(context.TODO(), &svcsdk.ListTagsForResourceInput{
+		ResourceArn: arn,
+	})
+	if err != nil {
+		t.Fatalf("ListTagsForResource() error = %v", err)
+	}
+	if want := map[string]*string{"team": aws.String("b")}; !reflect.DeepEqual(tags.Tags, want) {
+		t.Errorf("ListTagsForResource() = %v, want %v", tags.Tags, want)
+	}
+
+	_, err = api.TagResourceWithContext(context.TODO(), &svcsdk.TagResourceInput{
+		ResourceArn: aws.String("arn:aws:aps:us-west-2:111111111111:workspace/ws-missing"),
+		Tags:        map[string]*string{"team": aws.String("b")},
+	})
+	if errorCode(err) != svcsdk.ErrCodeResourceNotFoundException {
+		t.Errorf("TagResource() error = %v, want a ResourceNotFoundException", err)
+	}
+}
+
+func TestCalls(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	newActiveWorkspace(t, api)
+	if want := []string{"CreateWorkspace"}; !reflect.DeepEqual(api.Calls(), want) {
+		t.Errorf("Calls() = %v, want %v", api.Calls(), want)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+// loggingConfiguration is a fake logging configuration.
+type loggingConfiguration struct {
+	lifecycle
+	logGroupARN string
+}
+
+func (lc *loggingConfiguration) status() *svcsdk.LoggingConfigurationStatus {
+	status := &svcsdk.LoggingConfigurationStatus{StatusCode: aws.String(lc.statusCode)}
+	if lc.statusReason != "" {
+		status.StatusReason = aws.String(lc.statusReason)
+	}
+	return status
+}
+
+// loggingConfiguration returns the logging configuration of the supplied
+// workspace and the workspace, or a ResourceNotFoundException if either does
+// not exist. It must be called with the mutex held.
+func (a *API) loggingConfiguration(
+	workspaceID *string,
+) (*workspace, *loggingConfiguration, error) {
+	ws, ok := a.workspaces[aws.StringValue(workspaceID)]
+	if !ok {
+		return nil, nil, notFoundError("Workspace not found: %s", aws.StringValue(workspaceID))
+	}
+	if ws.loggingConfiguration == nil {
+		return nil, nil, notFoundError("Logging configuration not found in workspace %s", ws.id)
+	}
+	return ws, ws.loggingConfiguration, nil
+}
+
+// putLogGroup starts the creation or update of the supplied logging
+// configuration with the supplied log group, moving it to the supplied
+// status. Unlike the data of rule groups namespaces and alert manager
+// definitions, the log group is returned by the API even if the operation
+// fails. It must be called with the mutex held.
+func (a *API) putLogGroup(
+	lc *loggingConfiguration,
+	statusCode string,
+	failedStatusCode string,
+	logGroupARN string,
+) {
+	lc.logGroupARN = logGroupARN
+	lc.start(statusCode, a.Now(), func() {
+		var err error
+		if a.ValidateLogGroup != nil {
+			err = a.ValidateLogGroup(logGroupARN)
+		}
+		lc.finish(svcsdk.LoggingConfigurationStatusCodeActive, failedStatusCode, err)
+	})
+}
+
+func (a *API) CreateLoggingConfigurationWithContext(
+	_ aws.Context,
+	input *svcsdk.CreateLoggingConfigurationInput,
+	_ ...request.Option,
+) (*svcsdk.CreateLoggingConfigurationOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("CreateLoggingConfiguration")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, err := a.activeWorkspace(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if ws.loggingConfiguration != nil {
+		return nil, conflictError("Logging configuration already exists in workspace %s", ws.id)
+	}
+	lc := &loggingConfiguration{}
+	lc.createdAt = a.Now()
+	a.putLogGroup(
+		lc,
+		svcsdk.LoggingConfigurationStatusCodeCreating,
+		svcsdk.LoggingConfigurationStatusCodeCreationFailed,
+		*input.LogGroupArn,
+	)
+	ws.loggingConfiguration = lc
+
+	return &svcsdk.CreateLoggingConfigurationOutput{Status: lc.status()}, nil
+}
+
+func (a *API) DescribeLoggingConfigurationWithContext(
+	_ aws.Context,
+	input *svcsdk.DescribeLoggingConfigurationInput,
+	_ ...request.Option,
+) (*svcsdk.DescribeLoggingConfigurationOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DescribeLoggingConfiguration")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, lc, err := a.loggingConfiguration(input.WorkspaceId)
+	if err == nil {
+		a.observe(&lc.lifecycle)
+		ws, lc, err = a.loggingConfiguration(input.WorkspaceId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &svcsdk.DescribeLoggingConfigurationOutput{
+		LoggingConfiguration: &svcsdk.LoggingConfigurationMetadata{
+			CreatedAt:   timePtr(lc.createdAt),
+			LogGroupArn: aws.String(lc.logGroupARN),
+			ModifiedAt:  timePtr(lc.modifiedAt),
+			Status:      lc.status(),
+			Workspace:   aws.String(ws.id),
+		},
+	}, nil
+}
+
+func (a *API) UpdateLoggingConfigurationWithContext(
+	_ aws.Context,
+	input *svcsdk.UpdateLoggingConfigurationInput,
+	_ ...request.Option,
+) (*svcsdk.UpdateLoggingConfigurationOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("UpdateLoggingConfiguration")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	_, lc, err := a.loggingConfiguration(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if lc.busy() {
+		return nil, conflictError("Logging configuration is in %s state", lc.statusCode)
+	}
+	a.putLogGroup(
+		lc,
+		svcsdk.LoggingConfigurationStatusCodeUpdating,
+		svcsdk.LoggingConfigurationStatusCodeUpdateFailed,
+		*input.LogGroupArn,
+	)
+
+	return &svcsdk.UpdateLoggingConfigurationOutput{Status: lc.status()}, nil
+}
+
+func (a *API) DeleteLoggingConfigurationWithContext(
+	_ aws.Context,
+	input *svcsdk.DeleteLoggingConfigurationInput,
+	_ ...request.Option,
+) (*svcsdk.DeleteLoggingConfigurationOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DeleteLoggingConfiguration")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, lc, err := a.loggingConfiguration(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if lc.busy() {
+		return nil, conflictError("Logging configuration is in %s state", lc.statusCode)
+	}
+	lc.start(svcsdk.LoggingConfigurationStatusCodeDeleting, a.Now(), func() {
+		ws.loggingConfiguration = nil
+	})
+	return &svcsdk.DeleteLoggingConfigurationOutput{}, nil
+}
@@ -0,0 +1,217 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+// ruleGroupsNamespace is a fake rule groups namespace.
+type ruleGroupsNamespace struct {
+	lifecycle
+	name string
+	arn  string
+	// data is the last valid data, returned by the API.
+	data []byte
+	tags map[string]*string
+}
+
+func (rgn *ruleGroupsNamespace) status() *svcsdk.RuleGroupsNamespaceStatus {
+	status := &svcsdk.RuleGroupsNamespaceStatus{StatusCode: aws.String(rgn.statusCode)}
+	if rgn.statusReason != "" {
+		status.StatusReason = aws.String(rgn.statusReason)
+	}
+	return status
+}
+
+// ruleGroupsNamespace returns the supplied rule groups namespace and its
+// workspace, or a ResourceNotFoundException if either does not exist. It
+// must be called with the mutex held.
+func (a *API) ruleGroupsNamespace(
+	workspaceID *string,
+	name *string,
+) (*workspace, *ruleGroupsNamespace, error) {
+	ws, ok := a.workspaces[aws.StringValue(workspaceID)]
+	if !ok {
+		return nil, nil, notFoundError("Workspace not found: %s", aws.StringValue(workspaceID))
+	}
+	rgn, ok := ws.ruleGroupsNamespaces[aws.StringValue(name)]
+	if !ok {
+		return nil, nil, notFoundError("Rule groups namespace not found: %s", aws.StringValue(name))
+	}
+	return ws, rgn, nil
+}
+
+// putRuleGroupsNamespaceData starts the creation or update of the supplied
+// rule groups namespace with the supplied data, moving it to the supplied
+// status. It must be called with the mutex held.
+func (a *API) putRuleGroupsNamespaceData(
+	rgn *ruleGroupsNamespace,
+	statusCode string,
+	failedStatusCode string,
+	data []byte,
+) {
+	data = copyBytes(data)
+	rgn.start(statusCode, a.Now(), func() {
+		var err error
+		if a.ValidateRuleGroupsNamespace != nil {
+			err = a.ValidateRuleGroupsNamespace(data)
+		}
+		if err == nil {
+			rgn.data = data
+		}
+		rgn.finish(svcsdk.RuleGroupsNamespaceStatusCodeActive, failedStatusCode, err)
+	})
+}
+
+func (a *API) CreateRuleGroupsNamespaceWithContext(
+	_ aws.Context,
+	input *svcsdk.CreateRuleGroupsNamespaceInput,
+	_ ...request.Option,
+) (*svcsdk.CreateRuleGroupsNamespaceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("CreateRuleGroupsNamespace")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, err := a.activeWorkspace(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ws.ruleGroupsNamespaces[*input.Name]; ok {
+		return nil, conflictError("Rule groups namespace %s already exists", *input.Name)
+	}
+	rgn := &ruleGroupsNamespace{
+		name: *input.Name,
+		arn: fmt.Sprintf(
+			"arn:aws:aps:%s:%s:rulegroupsnamespace/%s/%s",
+			a.region, a.accountID, ws.id, *input.Name,
+		),
+		tags: copyTags(input.Tags),
+	}
+	rgn.createdAt = a.Now()
+	a.putRuleGroupsNamespaceData(
+		rgn,
+		svcsdk.RuleGroupsNamespaceStatusCodeCreating,
+		svcsdk.RuleGroupsNamespaceStatusCodeCreationFailed,
+		input.Data,
+	)
+	ws.ruleGroupsNamespaces[rgn.name] = rgn
+
+	return &svcsdk.CreateRuleGroupsNamespaceOutput{
+		Arn:    aws.String(rgn.arn),
+		Name:   aws.String(rgn.name),
+		Status: rgn.status(),
+		Tags:   copyTags(rgn.tags),
+	}, nil
+}
+
+func (a *API) DescribeRuleGroupsNamespaceWithContext(
+	_ aws.Context,
+	input *svcsdk.DescribeRuleGroupsNamespaceInput,
+	_ ...request.Option,
+) (*svcsdk.DescribeRuleGroupsNamespaceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DescribeRuleGroupsNamespace")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	_, rgn, err := a.ruleGroupsNamespace(input.WorkspaceId, input.Name)
+	if err == nil {
+		a.observe(&rgn.lifecycle)
+		_, rgn, err = a.ruleGroupsNamespace(input.WorkspaceId, input.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &svcsdk.DescribeRuleGroupsNamespaceOutput{
+		RuleGroupsNamespace: &svcsdk.RuleGroupsNamespaceDescription{
+			Arn:        aws.String(rgn.arn),
+			CreatedAt:  timePtr(rgn.createdAt),
+			Data:       copyBytes(rgn.data),
+			ModifiedAt: timePtr(rgn.modifiedAt),
+			Name:       aws.String(rgn.name),
+			Status:     rgn.status(),
+			Tags:       copyTags(rgn.tags),
+		},
+	}, nil
+}
+
+func (a *API) PutRuleGroupsNamespaceWithContext(
+	_ aws.Context,
+	input *svcsdk.PutRuleGroupsNamespaceInput,
+	_ ...request.Option,
+) (*svcsdk.PutRuleGroupsNamespaceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("PutRuleGroupsNamespace")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	_, rgn, err := a.ruleGroupsNamespace(input.WorkspaceId, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if rgn.busy() {
+		return nil, conflictError("Rule groups namespace %s is in %s state", rgn.name, rgn.statusCode)
+	}
+	a.putRuleGroupsNamespaceData(
+		rgn,
+		svcsdk.RuleGroupsNamespaceStatusCodeUpdating,
+		svcsdk.RuleGroupsNamespaceStatusCodeUpdateFailed,
+		input.Data,
+	)
+
+	return &svcsdk.PutRuleGroupsNamespaceOutput{
+		Arn:    aws.String(rgn.arn),
+		Name:   aws.String(rgn.name),
+		Status: rgn.status(),
+		Tags:   copyTags(rgn.tags),
+	}, nil
+}
+
+func (a *API) DeleteRuleGroupsNamespaceWithContext(
+	_ aws.Context,
+	input *svcsdk.DeleteRuleGroupsNamespaceInput,
+	_ ...request.Option,
+) (*svcsdk.DeleteRuleGroupsNamespaceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DeleteRuleGroupsNamespace")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, rgn, err := a.ruleGroupsNamespace(input.WorkspaceId, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if rgn.busy() {
+		return nil, conflictError("Rule groups namespace %s is in %s state", rgn.name, rgn.statusCode)
+	}
+	rgn.start(svcsdk.RuleGroupsNamespaceStatusCodeDeleting, a.Now(), func() {
+		delete(ws.ruleGroupsNamespaces, rgn.name)
+	})
+	return &svcsdk.DeleteRuleGroupsNamespaceOutput{}, nil
+}
@@ -0,0 +1,216 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+)
+
+// route maps the HTTP method and path of the requests of the AMP REST API to
+// an operation. The labels of the path are enclosed in braces, as in the
+// AWS SDK.
+type route struct {
+	method    string
+	path      string
+	operation string
+}
+
+// routes are the routes of the operations the fake implements.
+var routes = []route{
+	{http.MethodPost, "/workspaces", "CreateWorkspace"},
+	{http.MethodGet, "/workspaces/{workspaceId}", "DescribeWorkspace"},
+	{http.MethodPost, "/workspaces/{workspaceId}/alias", "UpdateWorkspaceAlias"},
+	{http.MethodDelete, "/workspaces/{workspaceId}", "DeleteWorkspace"},
+	{http.MethodPost, "/workspaces/{workspaceId}/rulegroupsnamespaces", "CreateRuleGroupsNamespace"},
+	{http.MethodGet, "/workspaces/{workspaceId}/rulegroupsnamespaces/{name}", "DescribeRuleGroupsNamespace"},
+	{http.MethodPut, "/workspaces/{workspaceId}/rulegroupsnamespaces/{name}", "PutRuleGroupsNamespace"},
+	{http.MethodDelete, "/workspaces/{workspaceId}/rulegroupsnamespaces/{name}", "DeleteRuleGroupsNamespace"},
+	{http.MethodPost, "/workspaces/{workspaceId}/alertmanager/definition", "CreateAlertManagerDefinition"},
+	{http.MethodGet, "/workspaces/{workspaceId}/alertmanager/definition", "DescribeAlertManagerDefinition"},
+	{http.MethodPut, "/workspaces/{workspaceId}/alertmanager/definition", "PutAlertManagerDefinition"},
+	{http.MethodDelete, "/workspaces/{workspaceId}/alertmanager/definition", "DeleteAlertManagerDefinition"},
+	{http.MethodPost, "/workspaces/{workspaceId}/logging", "CreateLoggingConfiguration"},
+	{http.MethodGet, "/workspaces/{workspaceId}/logging", "DescribeLoggingConfiguration"},
+	{http.MethodPut, "/workspaces/{workspaceId}/logging", "UpdateLoggingConfiguration"},
+	{http.MethodDelete, "/workspaces/{workspaceId}/logging", "DeleteLoggingConfiguration"},
+	{http.MethodGet, "/tags/{resourceArn}", "ListTagsForResource"},
+	{http.MethodPost, "/tags/{resourceArn}", "TagResource"},
+	{http.MethodDelete, "/tags/{resourceArn}", "UntagResource"},
+}
+
+// match returns the labels of the supplied escaped path if the route matches
+// the supplied method and path.
+func (r route) match(method string, escapedPath string) (map[string]string, bool) {
+	if method != r.method {
+		return nil, false
+	}
+	segments := strings.Split(strings.Trim(escapedPath, "/"), "/")
+	routeSegments := strings.Split(strings.Trim(r.path, "/"), "/")
+	if len(segments) != len(routeSegments) {
+		return nil, false
+	}
+	labels := map[string]string{}
+	for i, s := range routeSegments {
+		if strings.HasPrefix(s, "{") {
+			label, err := url.PathUnescape(segments[i])
+			if err != nil || label == "" {
+				return nil, false
+			}
+			labels[strings.Trim(s, "{}")] = label
+		} else if s != segments[i] {
+			return nil, false
+		}
+	}
+	return labels, true
+}
+
+// handler serves the AMP REST API with an AMP API client.
+type handler struct {
+	api       svcsdkapi.PrometheusServiceAPI
+	accountID string
+}
+
+// NewHandler returns an HTTP handler serving the AMP REST API with the
+// supplied AMP API client, typically a fake. The AWS SDK, and so the
+// controller, can use the handler as AMP endpoint.
+//
+// The handler also serves the STS GetCallerIdentity operation, returning the
+// supplied AWS account ID, so that it can be used as identity endpoint too.
+// The requests are not authenticated.
+func NewHandler(api svcsdkapi.PrometheusServiceAPI, accountID string) http.Handler {
+	return &handler{api: api, accountID: accountID}
+}
+
+// ServeHTTP implements http.Handler
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/" {
+		h.getCallerIdentity(w, r)
+		return
+	}
+	for _, rt := range routes {
+		if labels, ok := rt.match(r.Method, r.URL.EscapedPath()); ok {
+			h.serve(w, r, rt.operation, labels)
+			return
+		}
+	}
+	writeError(w, newError("UnknownOperationException", http.StatusNotFound,
+		"no operation for %s %s", r.Method, r.URL.Path))
+}
+
+// serve calls the supplied operation with the input of the supplied request
+// and writes its output.
+func (h *handler) serve(
+	w http.ResponseWriter,
+	r *http.Request,
+	operation string,
+	labels map[string]string,
+) {
+	method := reflect.ValueOf(h.api).MethodByName(operation + "WithContext")
+	input := reflect.New(method.Type().In(1).Elem())
+	if err := jsonutil.UnmarshalJSON(input.Interface(), r.Body); err != nil {
+		writeError(w, newError("SerializationException", http.StatusBadRequest, "%v", err))
+		return
+	}
+	setLocationFields(input.Elem(), labels, r.URL.Query())
+
+	out := method.Call([]reflect.Value{reflect.ValueOf(r.Context()), input})
+	if err, _ := out[1].Interface().(error); err != nil {
+		writeError(w, err)
+		return
+	}
+	body, err := jsonutil.BuildJSON(out[0].Interface())
+	if err != nil {
+		writeError(w, newError("SerializationException", http.StatusInternalServerError, "%v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// setLocationFields sets the fields of the supplied input that are located in
+// the URI or query string of the requests.
+func setLocationFields(input reflect.Value, labels map[string]string, query url.Values) {
+	for i := 0; i < input.NumField(); i++ {
+		field := input.Type().Field(i)
+		name := field.Tag.Get("locationName")
+		var values []string
+		switch field.Tag.Get("location") {
+		case "uri":
+			if label, ok := labels[name]; ok {
+				values = []string{label}
+			}
+		case "querystring":
+			values = query[name]
+		}
+		if len(values) == 0 {
+			continue
+		}
+		switch field.Type {
+		case reflect.TypeOf((*string)(nil)):
+			input.Field(i).Set(reflect.ValueOf(&values[0]))
+		case reflect.TypeOf([]*string{}):
+			list := make([]*string, len(values))
+			for j := range values {
+				list[j] = &values[j]
+			}
+			input.Field(i).Set(reflect.ValueOf(list))
+		}
+	}
+}
+
+// writeError writes the supplied error as the REST JSON protocol does.
+func writeError(w http.ResponseWriter, err error) {
+	code, message, status := "InternalServerException", err.Error(), http.StatusInternalServerError
+	if awsErr, ok := err.(awserr.Error); ok {
+		code, message = awsErr.Code(), awsErr.Message()
+	}
+	if reqErr, ok := err.(awserr.RequestFailure); ok {
+		status = reqErr.StatusCode()
+	}
+	body, _ := jsonutil.BuildJSON(&struct {
+		Message *string `locationName:"message" type:"string"`
+	}{Message: &message})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-Errortype", code)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// getCallerIdentity serves the STS GetCallerIdentity operation.
+func (h *handler) getCallerIdentity(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("Action") != "GetCallerIdentity" {
+		http.Error(w, "unsupported STS operation", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::%[1]s:user/fake</Arn>
+    <UserId>AIDAFAKE</UserId>
+    <Account>%[1]s</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata>
+    <RequestId>00000000-0000-0000-0000-000000000000</RequestId>
+  </ResponseMetadata>
+</GetCallerIdentityResponse>
+`, h.accountID)
+}
@@ -0,0 +1,164 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// newTestServer serves the supplied fake with the handler and returns a
+// session whose clients call the server.
+func newTestServer(t *testing.T, api *API) *session.Session {
+	t.Helper()
+	srv := httptest.NewServer(NewHandler(api, "111111111111"))
+	t.Cleanup(srv.Close)
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Endpoint:    aws.String(srv.URL),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	return sess
+}
+
+func TestHandler(t *testing.T) {
+	api := New("111111111111", "us-west-2")
+	client := svcsdk.New(newTestServer(t, api))
+	ctx := context.TODO()
+
+	ws, err := client.CreateWorkspaceWithContext(ctx, &svcsdk.CreateWorkspaceInput{
+		Alias: aws.String("alias"),
+		Tags:  map[string]*string{"team": aws.String("a")},
+	})
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+	if *ws.Status.StatusCode != svcsdk.WorkspaceStatusCodeCreating {
+		t.Errorf("CreateWorkspace() status = %s, want CREATING", *ws.Status.StatusCode)
+	}
+	described, err := client.DescribeWorkspaceWithContext(ctx, &svcsdk.DescribeWorkspaceInput{
+		WorkspaceId: ws.WorkspaceId,
+	})
+	if err != nil {
+		t.Fatalf("DescribeWorkspace() error = %v", err)
+	}
+	if *described.Workspace.Status.StatusCode != svcsdk.WorkspaceStatusCodeActive ||
+		*described.Workspace.Alias != "alias" || described.Workspace.CreatedAt == nil {
+		t.Errorf("DescribeWorkspace() = %v", described.Workspace)
+	}
+
+	data := []byte("groups: []\n")
+	rgn, err := client.CreateRuleGroupsNamespaceWithContext(ctx, &svcsdk.CreateRuleGroupsNamespaceInput{
+		WorkspaceId: ws.WorkspaceId,
+		Name:        aws.String("rules"),
+		Data:        data,
+	})
+	if err != nil {
+		t.Fatalf("CreateRuleGroupsNamespace() error = %v", err)
+	}
+	api.Settle()
+	describedRGN, err := client.DescribeRuleGroupsNamespaceWithContext(ctx, &svcsdk.DescribeRuleGroupsNamespaceInput{
+		WorkspaceId: ws.WorkspaceId,
+		Name:        aws.String("rules"),
+	})
+	if err != nil {
+		t.Fatalf("DescribeRuleGroupsNamespace() error = %v", err)
+	}
+	if string(describedRGN.RuleGroupsNamespace.Data) != string(data) {
+		t.Errorf("DescribeRuleGroupsNamespace() data = %q, want %q", describedRGN.RuleGroupsNamespace.Data, data)
+	}
+
+	// The ARNs in the path of the tagging operations are escaped.
+	if _, err := client.TagResourceWithContext(ctx, &svcsdk.TagResourceInput{
+		ResourceArn: rgn.Arn,
+		Tags:        map[string]*string{"env": aws.String("prod"), "team": aws.String("b")},
+	}); err != nil {
+		t.Fatalf("TagResource() error = %v", err)
+	}
+	if _, err := client.UntagResourceWithContext(ctx, &svcsdk.UntagResourceInput{
+		ResourceArn: rgn.Arn,
+		TagKeys:     []*string{aws.String("env")},
+	}); err != nil {
+		t.Fatalf("UntagResource() error = %v", err)
+	}
+	tags, err := client.ListTagsForResourceWithContext(ctx, &svcsdk.ListTagsForResourceInput{
+		ResourceArn: rgn.Arn,
+	})
+	if err != nil {
+		t.Fatalf("ListTagsForResource() error = %v", err)
+	}
+	if want := map[string]*string{"team": aws.String("b")}; !reflect.DeepEqual(tags.Tags, want) {
+		t.Errorf("ListTagsForResource() = %v, want %v", aws.StringValueMap(tags.Tags), aws.StringValueMap(want))
+	}
+
+	if _, err := client.DeleteWorkspaceWithContext(ctx, &svcsdk.DeleteWorkspaceInput{
+		WorkspaceId: ws.WorkspaceId,
+		ClientToken: aws.String("token"),
+	}); err != nil {
+		t.Fatalf("DeleteWorkspace() error = %v", err)
+	}
+	api.Settle()
+	_, err = client.DescribeWorkspaceWithContext(ctx, &svcsdk.DescribeWorkspaceInput{
+		WorkspaceId: ws.WorkspaceId,
+	})
+	reqErr, ok := err.(awserr.RequestFailure)
+	if !ok || reqErr.Code() != svcsdk.ErrCodeResourceNotFoundException || reqErr.StatusCode() != http.StatusNotFound {
+		t.Errorf("DescribeWorkspace() error = %v, want a ResourceNotFoundException", err)
+	}
+
+	want := []string{
+		"CreateWorkspace", "DescribeWorkspace",
+		"CreateRuleGroupsNamespace", "DescribeRuleGroupsNamespace",
+		"TagResource", "UntagResource", "ListTagsForResource",
+		"DeleteWorkspace", "DescribeWorkspace",
+	}
+	if got := api.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls() = %v, want %v", got, want)
+	}
+}
+
+func TestHandlerUnknownOperation(t *testing.T) {
+	client := svcsdk.New(newTestServer(t, New("111111111111", "us-west-2")))
+
+	_, err := client.ListWorkspacesWithContext(context.TODO(), &svcsdk.ListWorkspacesInput{})
+	if errorCode(err) != "UnknownOperationException" {
+		t.Errorf("ListWorkspaces() error = %v, want an UnknownOperationException", err)
+	}
+}
+
+func TestHandlerCallerIdentity(t *testing.T) {
+	client := sts.New(newTestServer(t, New("111111111111", "us-west-2")))
+
+	resp, err := client.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		t.Fatalf("GetCallerIdentity() error = %v", err)
+	}
+	if *resp.Account != "111111111111" {
+		t.Errorf("GetCallerIdentity() account = %s, want 111111111111", *resp.Account)
+	}
+}
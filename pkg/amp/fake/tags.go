@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+// taggedResource returns a pointer to the tags of the resource with the
+// supplied ARN, or a ResourceNotFoundException if it does not exist. Only
+// workspaces and rule groups namespaces can be tagged. It must be called
+// with the mutex held.
+func (a *API) taggedResource(arn *string) (*map[string]*string, error) {
+	for _, ws := range a.workspaces {
+		if ws.arn == aws.StringValue(arn) {
+			return &ws.tags, nil
+		}
+		for _, rgn := range ws.ruleGroupsNamespaces {
+			if rgn.arn == aws.StringValue(arn) {
+				return &rgn.tags, nil
+			}
+		}
+	}
+	return nil, notFoundError("Resource not found: %s", aws.StringValue(arn))
+}
+
+func (a *API) ListTagsForResourceWithContext(
+	_ aws.Context,
+	input *svcsdk.ListTagsForResourceInput,
+	_ ...request.Option,
+) (*svcsdk.ListTagsForResourceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("ListTagsForResource")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	tags, err := a.taggedResource(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	return &svcsdk.ListTagsForResourceOutput{Tags: copyTags(*tags)}, nil
+}
+
+func (a *API) TagResourceWithContext(
+	_ aws.Context,
+	input *svcsdk.TagResourceInput,
+	_ ...request.Option,
+) (*svcsdk.TagResourceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("TagResource")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	tags, err := a.taggedResource(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	if *tags == nil {
+		*tags = map[string]*string{}
+	}
+	for k, v := range copyTags(input.Tags) {
+		(*tags)[k] = v
+	}
+	return &svcsdk.TagResourceOutput{}, nil
+}
+
+func (a *API) UntagResourceWithContext(
+	_ aws.Context,
+	input *svcsdk.UntagResourceInput,
+	_ ...request.Option,
+) (*svcsdk.UntagResourceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("UntagResource")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	tags, err := a.taggedResource(input.ResourceArn)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range input.TagKeys {
+		delete(*tags, aws.StringValue(k))
+	}
+	return &svcsdk.UntagResourceOutput{}, nil
+}
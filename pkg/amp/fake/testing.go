@@ -0,0 +1,90 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+const (
+	// TestAccountID is the AWS account of the fake AMP APIs used by the
+	// tests of the resource managers.
+	TestAccountID = "111111111111"
+	// TestRegion is the AWS region of the fake AMP APIs used by the tests
+	// of the resource managers.
+	TestRegion = "us-west-2"
+)
+
+// TestMetrics are the metrics of the resource managers used by the tests.
+var TestMetrics = ackmetrics.NewMetrics("prometheusservice")
+
+// NewWorkspace creates a workspace in the supplied fake AMP API and returns
+// its ID. The creation of the workspace is not completed.
+func NewWorkspace(t testing.TB, api *API) string {
+	t.Helper()
+	resp, err := api.CreateWorkspaceWithContext(context.TODO(), &svcsdk.CreateWorkspaceInput{})
+	if err != nil {
+		t.Fatalf("CreateWorkspace() error = %v", err)
+	}
+	return *resp.WorkspaceId
+}
+
+// NewActiveWorkspace creates a workspace in the supplied fake AMP API,
+// completes its creation and returns its ID.
+func NewActiveWorkspace(t testing.TB, api *API) string {
+	t.Helper()
+	id := NewWorkspace(t, api)
+	api.Settle()
+	return id
+}
+
+// CreateAndSettle creates the supplied resource with the supplied resource
+// manager, completes its creation in the supplied fake AMP API and returns
+// the resource as then observed by the resource manager. The creation can
+// fail asynchronously, in which case the observed resource is returned along
+// with its terminal condition.
+func CreateAndSettle(
+	t testing.TB,
+	api *API,
+	rm acktypes.AWSResourceManager,
+	desired acktypes.AWSResource,
+) acktypes.AWSResource {
+	t.Helper()
+	created, err := rm.Create(context.TODO(), desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	api.Settle()
+	latest, err := rm.ReadOne(context.TODO(), created)
+	if err != nil && err != ackerr.Terminal && !errors.As(err, new(*ackerr.TerminalError)) {
+		t.Fatalf("ReadOne() error = %v", err)
+	}
+	return latest
+}
+
+// ErrorCode returns the AWS error code of the supplied error, or an empty
+// string if it is not an AWS error.
+func ErrorCode(err error) string {
+	if awsErr, ok := ackerr.AWSError(err); ok {
+		return awsErr.Code()
+	}
+	return ""
+}
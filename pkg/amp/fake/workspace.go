@@ -0,0 +1,186 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+// workspace is a fake workspace and the resources created in it.
+type workspace struct {
+	lifecycle
+	id       string
+	arn      string
+	alias    *string
+	endpoint string
+	tags     map[string]*string
+
+	ruleGroupsNamespaces   map[string]*ruleGroupsNamespace
+	alertManagerDefinition *alertManagerDefinition
+	loggingConfiguration   *loggingConfiguration
+}
+
+func (ws *workspace) status() *svcsdk.WorkspaceStatus {
+	return &svcsdk.WorkspaceStatus{StatusCode: aws.String(ws.statusCode)}
+}
+
+// activeWorkspace returns the supplied workspace, a ResourceNotFoundException
+// if it does not exist, or a ConflictException if it is not ACTIVE. It must
+// be called with the mutex held.
+func (a *API) activeWorkspace(workspaceID *string) (*workspace, error) {
+	ws, ok := a.workspaces[aws.StringValue(workspaceID)]
+	if !ok {
+		return nil, notFoundError("Workspace not found: %s", aws.StringValue(workspaceID))
+	}
+	if ws.statusCode != svcsdk.WorkspaceStatusCodeActive {
+		return nil, conflictError("Workspace %s is in %s state", ws.id, ws.statusCode)
+	}
+	return ws, nil
+}
+
+func (a *API) CreateWorkspaceWithContext(
+	_ aws.Context,
+	input *svcsdk.CreateWorkspaceInput,
+	_ ...request.Option,
+) (*svcsdk.CreateWorkspaceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("CreateWorkspace")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	id := newWorkspaceID()
+	ws := &workspace{
+		id:    id,
+		arn:   fmt.Sprintf("arn:aws:aps:%s:%s:workspace/%s", a.region, a.accountID, id),
+		alias: input.Alias,
+		endpoint: fmt.Sprintf(
+			"https://aps-workspaces.%s.amazonaws.com/workspaces/%s/", a.region, id,
+		),
+		tags:                 copyTags(input.Tags),
+		ruleGroupsNamespaces: map[string]*ruleGroupsNamespace{},
+	}
+	now := a.Now()
+	ws.createdAt = now
+	ws.start(svcsdk.WorkspaceStatusCodeCreating, now, func() {
+		var err error
+		if a.ValidateWorkspace != nil {
+			err = a.ValidateWorkspace(ws.alias)
+		}
+		ws.finish(svcsdk.WorkspaceStatusCodeActive, svcsdk.WorkspaceStatusCodeCreationFailed, err)
+	})
+	a.workspaces[id] = ws
+
+	return &svcsdk.CreateWorkspaceOutput{
+		Arn:         aws.String(ws.arn),
+		Status:      ws.status(),
+		Tags:        copyTags(ws.tags),
+		WorkspaceId: aws.String(ws.id),
+	}, nil
+}
+
+func (a *API) DescribeWorkspaceWithContext(
+	_ aws.Context,
+	input *svcsdk.DescribeWorkspaceInput,
+	_ ...request.Option,
+) (*svcsdk.DescribeWorkspaceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DescribeWorkspace")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, ok := a.workspaces[*input.WorkspaceId]
+	if ok {
+		a.observe(&ws.lifecycle)
+		ws, ok = a.workspaces[*input.WorkspaceId]
+	}
+	if !ok {
+		return nil, notFoundError("Workspace not found: %s", *input.WorkspaceId)
+	}
+
+	var alias *string
+	if ws.alias != nil {
+		alias = aws.String(*ws.alias)
+	}
+	return &svcsdk.DescribeWorkspaceOutput{
+		Workspace: &svcsdk.WorkspaceDescription{
+			Alias:              alias,
+			Arn:                aws.String(ws.arn),
+			CreatedAt:          timePtr(ws.createdAt),
+			PrometheusEndpoint: aws.String(ws.endpoint),
+			Status:             ws.status(),
+			Tags:               copyTags(ws.tags),
+			WorkspaceId:        aws.String(ws.id),
+		},
+	}, nil
+}
+
+func (a *API) UpdateWorkspaceAliasWithContext(
+	_ aws.Context,
+	input *svcsdk.UpdateWorkspaceAliasInput,
+	_ ...request.Option,
+) (*svcsdk.UpdateWorkspaceAliasOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("UpdateWorkspaceAlias")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, err := a.activeWorkspace(input.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	ws.alias = nil
+	if input.Alias != nil {
+		ws.alias = aws.String(*input.Alias)
+	}
+	ws.start(svcsdk.WorkspaceStatusCodeUpdating, a.Now(), func() {
+		ws.finish(svcsdk.WorkspaceStatusCodeActive, "", nil)
+	})
+	return &svcsdk.UpdateWorkspaceAliasOutput{}, nil
+}
+
+func (a *API) DeleteWorkspaceWithContext(
+	_ aws.Context,
+	input *svcsdk.DeleteWorkspaceInput,
+	_ ...request.Option,
+) (*svcsdk.DeleteWorkspaceOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.call("DeleteWorkspace")
+	if err := input.Validate(); err != nil {
+		return nil, validationError(err)
+	}
+
+	ws, ok := a.workspaces[*input.WorkspaceId]
+	if !ok {
+		return nil, notFoundError("Workspace not found: %s", *input.WorkspaceId)
+	}
+	if ws.busy() {
+		return nil, conflictError("Workspace %s is in %s state", ws.id, ws.statusCode)
+	}
+	// The resources created in the workspace are deleted along with it.
+	ws.start(svcsdk.WorkspaceStatusCodeDeleting, a.Now(), func() {
+		delete(a.workspaces, ws.id)
+	})
+	return &svcsdk.DeleteWorkspaceOutput{}, nil
+}
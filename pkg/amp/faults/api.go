@@ -0,0 +1,249 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package faults
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+)
+
+// API wraps an AMP API client and injects faults in its calls. It is safe for
+// concurrent use.
+type API struct {
+	svcsdkapi.PrometheusServiceAPI
+	*injector
+}
+
+// New returns a client injecting the supplied faults in the calls to the
+// supplied AMP API client.
+func New(api svcsdkapi.PrometheusServiceAPI, faults ...Fault) *API {
+	return &API{
+		PrometheusServiceAPI: api,
+		injector:             newInjector(faults),
+	}
+}
+
+// The operations called by the resource managers inject the faults before
+// calling the wrapped client. The other operations call it directly.
+
+func (a *API) CreateWorkspaceWithContext(
+	ctx aws.Context,
+	input *svcsdk.CreateWorkspaceInput,
+	opts ...request.Option,
+) (*svcsdk.CreateWorkspaceOutput, error) {
+	if err := a.inject(ctx, "CreateWorkspace"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.CreateWorkspaceWithContext(ctx, input, opts...)
+}
+
+func (a *API) DescribeWorkspaceWithContext(
+	ctx aws.Context,
+	input *svcsdk.DescribeWorkspaceInput,
+	opts ...request.Option,
+) (*svcsdk.DescribeWorkspaceOutput, error) {
+	if err := a.inject(ctx, "DescribeWorkspace"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DescribeWorkspaceWithContext(ctx, input, opts...)
+}
+
+func (a *API) UpdateWorkspaceAliasWithContext(
+	ctx aws.Context,
+	input *svcsdk.UpdateWorkspaceAliasInput,
+	opts ...request.Option,
+) (*svcsdk.UpdateWorkspaceAliasOutput, error) {
+	if err := a.inject(ctx, "UpdateWorkspaceAlias"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.UpdateWorkspaceAliasWithContext(ctx, input, opts...)
+}
+
+func (a *API) DeleteWorkspaceWithContext(
+	ctx aws.Context,
+	input *svcsdk.DeleteWorkspaceInput,
+	opts ...request.Option,
+) (*svcsdk.DeleteWorkspaceOutput, error) {
+	if err := a.inject(ctx, "DeleteWorkspace"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DeleteWorkspaceWithContext(ctx, input, opts...)
+}
+
+func (a *API) CreateRuleGroupsNamespaceWithContext(
+	ctx aws.Context,
+	input *svcsdk.CreateRuleGroupsNamespaceInput,
+	opts ...request.Option,
+) (*svcsdk.CreateRuleGroupsNamespaceOutput, error) {
+	if err := a.inject(ctx, "CreateRuleGroupsNamespace"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.CreateRuleGroupsNamespaceWithContext(ctx, input, opts...)
+}
+
+func (a *API) DescribeRuleGroupsNamespaceWithContext(
+	ctx aws.Context,
+	input *svcsdk.DescribeRuleGroupsNamespaceInput,
+	opts ...request.Option,
+) (*svcsdk.DescribeRuleGroupsNamespaceOutput, error) {
+	if err := a.inject(ctx, "DescribeRuleGroupsNamespace"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DescribeRuleGroupsNamespaceWithContext(ctx, input, opts...)
+}
+
+func (a *API) PutRuleGroupsNamespaceWithContext(
+	ctx aws.Context,
+	input *svcsdk.PutRuleGroupsNamespaceInput,
+	opts ...request.Option,
+) (*svcsdk.PutRuleGroupsNamespaceOutput, error) {
+	if err := a.inject(ctx, "PutRuleGroupsNamespace"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.PutRuleGroupsNamespaceWithContext(ctx, input, opts...)
+}
+
+func (a *API) DeleteRuleGroupsNamespaceWithContext(
+	ctx aws.Context,
+	input *svcsdk.DeleteRuleGroupsNamespaceInput,
+	opts ...request.Option,
+) (*svcsdk.DeleteRuleGroupsNamespaceOutput, error) {
+	if err := a.inject(ctx, "DeleteRuleGroupsNamespace"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DeleteRuleGroupsNamespaceWithContext(ctx, input, opts...)
+}
+
+func (a *API) CreateAlertManagerDefinitionWithContext(
+	ctx aws.Context,
+	input *svcsdk.CreateAlertManagerDefinitionInput,
+	opts ...request.Option,
+) (*svcsdk.CreateAlertManagerDefinitionOutput, error) {
+	if err := a.inject(ctx, "CreateAlertManagerDefinition"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.CreateAlertManagerDefinitionWithContext(ctx, input, opts...)
+}
+
+func (a *API) DescribeAlertManagerDefinitionWithContext(
+	ctx aws.Context,
+	input *svcsdk.DescribeAlertManagerDefinitionInput,
+	opts ...request.Option,
+) (*svcsdk.DescribeAlertManagerDefinitionOutput, error) {
+	if err := a.inject(ctx, "DescribeAlertManagerDefinition"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DescribeAlertManagerDefinitionWithContext(ctx, input, opts...)
+}
+
+func (a *API) PutAlertManagerDefinitionWithContext(
+	ctx aws.Context,
+	input *svcsdk.PutAlertManagerDefinitionInput,
+	opts ...request.Option,
+) (*svcsdk.PutAlertManagerDefinitionOutput, error) {
+	if err := a.inject(ctx, "PutAlertManagerDefinition"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.PutAlertManagerDefinitionWithContext(ctx, input, opts...)
+}
+
+func (a *API) DeleteAlertManagerDefinitionWithContext(
+	ctx aws.Context,
+	input *svcsdk.DeleteAlertManagerDefinitionInput,
+	opts ...request.Option,
+) (*svcsdk.DeleteAlertManagerDefinitionOutput, error) {
+	if err := a.inject(ctx, "DeleteAlertManagerDefinition"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DeleteAlertManagerDefinitionWithContext(ctx, input, opts...)
+}
+
+func (a *API) CreateLoggingConfigurationWithContext(
+	ctx aws.Context,
+	input *svcsdk.CreateLoggingConfigurationInput,
+	opts ...request.Option,
+) (*svcsdk.CreateLoggingConfigurationOutput, error) {
+	if err := a.inject(ctx, "CreateLoggingConfiguration"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.CreateLoggingConfigurationWithContext(ctx, input, opts...)
+}
+
+func (a *API) DescribeLoggingConfigurationWithContext(
+	ctx aws.Context,
+	input *svcsdk.DescribeLoggingConfigurationInput,
+	opts ...request.Option,
+) (*svcsdk.DescribeLoggingConfigurationOutput, error) {
+	if err := a.inject(ctx, "DescribeLoggingConfiguration"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DescribeLoggingConfigurationWithContext(ctx, input, opts...)
+}
+
+func (a *API) UpdateLoggingConfigurationWithContext(
+	ctx aws.Context,
+	input *svcsdk.UpdateLoggingConfigurationInput,
+	opts ...request.Option,
+) (*svcsdk.UpdateLoggingConfigurationOutput, error) {
+	if err := a.inject(ctx, "UpdateLoggingConfiguration"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.UpdateLoggingConfigurationWithContext(ctx, input, opts...)
+}
+
+func (a *API) DeleteLoggingConfigurationWithContext(
+	ctx aws.Context,
+	input *svcsdk.DeleteLoggingConfigurationInput,
+	opts ...request.Option,
+) (*svcsdk.DeleteLoggingConfigurationOutput, error) {
+	if err := a.inject(ctx, "DeleteLoggingConfiguration"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.DeleteLoggingConfigurationWithContext(ctx, input, opts...)
+}
+
+func (a *API) ListTagsForResourceWithContext(
+	ctx aws.Context,
+	input *svcsdk.ListTagsForResourceInput,
+	opts ...request.Option,
+) (*svcsdk.ListTagsForResourceOutput, error) {
+	if err := a.inject(ctx, "ListTagsForResource"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.ListTagsForResourceWithContext(ctx, input, opts...)
+}
+
+func (a *API) TagResourceWithContext(
+	ctx aws.Context,
+	input *svcsdk.TagResourceInput,
+	opts ...request.Option,
+) (*svcsdk.TagResourceOutput, error) {
+	if err := a.inject(ctx, "TagResource"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.TagResourceWithContext(ctx, input, opts...)
+}
+
+func (a *API) UntagResourceWithContext(
+	ctx aws.Context,
+	input *svcsdk.UntagResourceInput,
+	opts ...request.Option,
+) (*svcsdk.UntagResourceOutput, error) {
+	if err := a.inject(ctx, "UntagResource"); err != nil {
+		return nil, err
+	}
+	return a.PrometheusServiceAPI.UntagResourceWithContext(ctx, input, opts...)
+}
@@ -0,0 +1,127 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package faults
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	flagInjectFaults = "debug-inject-faults"
+)
+
+// errorCodeRegexp matches the AWS error codes, such as "ThrottlingException".
+var errorCodeRegexp = regexp.MustCompile(`^[A-Z][A-Za-z]*$`)
+
+// Config contains the configuration of the fault injection.
+type Config struct {
+	// Faults are the injected faults, as "operation=fault[@probability]".
+	Faults []string
+}
+
+// BindFlags defines the command line flags of the fault injection. The flags
+// are hidden, as they are only meant for debugging.
+func (cfg *Config) BindFlags() {
+	flag.StringArrayVar(
+		&cfg.Faults, flagInjectFaults,
+		[]string{},
+		"A list of faults to inject in the calls to the AMP API, as 'operation=fault[@probability]', "+
+			"where the operation is the name of an AMP API operation or '*' for all of them, "+
+			"and the fault is an AWS error code or a latency, e.g. 'PutRuleGroupsNamespace=ThrottlingException@0.5' "+
+			"or '*=2s@0.1'. The probability defaults to 1. For debugging only.",
+	)
+	_ = flag.CommandLine.MarkHidden(flagInjectFaults)
+}
+
+// Validate ensures the options are valid
+func (cfg *Config) Validate() error {
+	if _, err := cfg.faults(); err != nil {
+		return fmt.Errorf("invalid value for flag '%s': %v", flagInjectFaults, err)
+	}
+	return nil
+}
+
+// Enabled returns true if faults are injected.
+func (cfg *Config) Enabled() bool {
+	return len(cfg.Faults) > 0
+}
+
+// WrapManagerFactories returns the supplied resource manager factories,
+// wrapped to inject the configured faults in the calls of their resource
+// managers to the AMP API. The factories are returned as is if no faults are
+// injected.
+func (cfg *Config) WrapManagerFactories(
+	factories []acktypes.AWSResourceManagerFactory,
+) ([]acktypes.AWSResourceManagerFactory, error) {
+	faults, err := cfg.faults()
+	if err != nil {
+		return nil, err
+	}
+	if len(faults) == 0 {
+		return factories, nil
+	}
+	return wrapManagerFactories(factories, faults), nil
+}
+
+// faults parses the configured faults.
+func (cfg *Config) faults() ([]Fault, error) {
+	faults := make([]Fault, 0, len(cfg.Faults))
+	for _, arg := range cfg.Faults {
+		f, err := parseFault(arg)
+		if err != nil {
+			return nil, err
+		}
+		faults = append(faults, f)
+	}
+	return faults, nil
+}
+
+// parseFault parses a fault formatted as "operation=fault[@probability]".
+func parseFault(arg string) (Fault, error) {
+	elements := strings.Split(arg, "=")
+	if len(elements) != 2 || elements[0] == "" || elements[1] == "" {
+		return Fault{}, fmt.Errorf("error parsing flag argument '%s'. Expected format: operation=fault[@probability]", arg)
+	}
+	f := Fault{Operation: elements[0], Probability: 1}
+	fault := elements[1]
+	if i := strings.LastIndex(fault, "@"); i >= 0 {
+		probability, err := strconv.ParseFloat(fault[i+1:], 64)
+		if err != nil || probability < 0 || probability > 1 {
+			return Fault{}, fmt.Errorf("expected a probability between 0 and 1 for fault '%s', got '%s'", arg, fault[i+1:])
+		}
+		f.Probability = probability
+		fault = fault[:i]
+	}
+	if fault == "" {
+		return Fault{}, fmt.Errorf("missing fault in '%s'", arg)
+	}
+	if latency, err := time.ParseDuration(fault); err == nil {
+		if latency <= 0 {
+			return Fault{}, fmt.Errorf("expected a positive latency for fault '%s', got '%s'", arg, fault)
+		}
+		f.Latency = latency
+	} else if errorCodeRegexp.MatchString(fault) {
+		f.ErrorCode = fault
+	} else {
+		return Fault{}, fmt.Errorf("expected an error code or a latency for fault '%s', got '%s'", arg, fault)
+	}
+	return f, nil
+}
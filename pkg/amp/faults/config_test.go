@@ -0,0 +1,159 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	"github.com/go-logr/logr"
+)
+
+func TestParseFault(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    Fault
+		wantErr bool
+	}{
+		{
+			arg:  "PutRuleGroupsNamespace=ThrottlingException",
+			want: Fault{Operation: "PutRuleGroupsNamespace", Probability: 1, ErrorCode: "ThrottlingException"},
+		},
+		{
+			arg:  "PutRuleGroupsNamespace=ThrottlingException@0.5",
+			want: Fault{Operation: "PutRuleGroupsNamespace", Probability: 0.5, ErrorCode: "ThrottlingException"},
+		},
+		{
+			arg:  "*=2s@0.1",
+			want: Fault{Operation: "*", Probability: 0.1, Latency: 2 * time.Second},
+		},
+		{
+			arg:  "DescribeWorkspace=500ms",
+			want: Fault{Operation: "DescribeWorkspace", Probability: 1, Latency: 500 * time.Millisecond},
+		},
+		{arg: "ThrottlingException", wantErr: true},
+		{arg: "=ThrottlingException", wantErr: true},
+		{arg: "CreateWorkspace=", wantErr: true},
+		{arg: "CreateWorkspace=a=b", wantErr: true},
+		{arg: "CreateWorkspace=@0.5", wantErr: true},
+		{arg: "CreateWorkspace=ThrottlingException@2", wantErr: true},
+		{arg: "CreateWorkspace=ThrottlingException@often", wantErr: true},
+		{arg: "CreateWorkspace=0s", wantErr: true},
+		{arg: "CreateWorkspace=2sec", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parseFault(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFault() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseFault() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		faults  []string
+		wantErr bool
+	}{
+		{name: "no faults"},
+		{name: "valid faults", faults: []string{"*=1s@0.1", "TagResource=AccessDeniedException"}},
+		{name: "invalid fault", faults: []string{"*=1s@0.1", "TagResource"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Faults: tt.faults}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeManagerFactory is a resource manager factory recording the session
+// its resource managers are created with.
+type fakeManagerFactory struct {
+	acktypes.AWSResourceManagerFactory
+
+	sess *session.Session
+}
+
+func (f *fakeManagerFactory) ManagerFor(
+	cfg ackcfg.Config,
+	log logr.Logger,
+	metrics *ackmetrics.Metrics,
+	rr acktypes.Reconciler,
+	sess *session.Session,
+	id ackv1alpha1.AWSAccountID,
+	region ackv1alpha1.AWSRegion,
+) (acktypes.AWSResourceManager, error) {
+	f.sess = sess
+	return nil, nil
+}
+
+func TestConfigWrapManagerFactories(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	factory := &fakeManagerFactory{}
+	factories := []acktypes.AWSResourceManagerFactory{factory}
+
+	cfg := &Config{}
+	got, err := cfg.WrapManagerFactories(factories)
+	if err != nil {
+		t.Fatalf("WrapManagerFactories() error = %v", err)
+	}
+	if got[0] != factory {
+		t.Errorf("WrapManagerFactories() = %T, want the factory without faults", got[0])
+	}
+
+	cfg = &Config{Faults: []string{"CreateWorkspace=ConflictException"}}
+	got, err = cfg.WrapManagerFactories(factories)
+	if err != nil {
+		t.Fatalf("WrapManagerFactories() error = %v", err)
+	}
+	if _, err := got[0].ManagerFor(ackcfg.Config{}, logr.Discard(), nil, nil, sess, "", ""); err != nil {
+		t.Fatalf("ManagerFor() error = %v", err)
+	}
+	if factory.sess == sess {
+		t.Fatalf("ManagerFor() session = the supplied session, want a copy")
+	}
+	if sess.Handlers.Validate.Len() == factory.sess.Handlers.Validate.Len() {
+		t.Errorf("ManagerFor() added the fault injection to the supplied session")
+	}
+
+	// The faulty requests fail before they are sent.
+	api := svcsdk.New(factory.sess)
+	_, err = api.CreateWorkspaceWithContext(context.TODO(), &svcsdk.CreateWorkspaceInput{})
+	if code := errorCode(err); code != svcsdk.ErrCodeConflictException {
+		t.Errorf("CreateWorkspace() error = %v, want an injected ConflictException", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package faults injects latency and errors in the calls of the controller to
+// the AMP API, to observe how the resources behave when AMP throttles
+// requests, denies access, times out or fails some of the calls needed to
+// create or update a resource. It is meant for tests and debugging only.
+package faults
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+const (
+	// AnyOperation matches the calls to all the operations.
+	AnyOperation = "*"
+)
+
+// httpStatusCodes are the HTTP status codes AMP returns with its errors.
+// Errors with other codes are returned with an internal server error.
+var httpStatusCodes = map[string]int{
+	svcsdk.ErrCodeAccessDeniedException:         http.StatusForbidden,
+	svcsdk.ErrCodeConflictException:             http.StatusConflict,
+	svcsdk.ErrCodeResourceNotFoundException:     http.StatusNotFound,
+	svcsdk.ErrCodeServiceQuotaExceededException: http.StatusPaymentRequired,
+	svcsdk.ErrCodeValidationException:           http.StatusBadRequest,
+	svcsdk.ErrCodeThrottlingException:           http.StatusTooManyRequests,
+	svcsdk.ErrCodeInternalServerException:       http.StatusInternalServerError,
+}
+
+// Fault is a fault injected in the calls to an operation.
+type Fault struct {
+	// Operation is the name of the operation, such as
+	// "PutRuleGroupsNamespace", or AnyOperation.
+	Operation string
+	// Probability is the probability, between 0 and 1, that a call is
+	// faulty.
+	Probability float64
+	// Latency delays the faulty calls. A call whose context is done before
+	// the delay elapsed fails with a RequestCanceled error, like a call to
+	// AMP timing out.
+	Latency time.Duration
+	// ErrorCode is the code of the AWS error the faulty calls fail with,
+	// without calling the API. The calls are only delayed if it is empty.
+	ErrorCode string
+}
+
+// matches returns true if the fault is injected in the calls to the supplied
+// operation.
+func (f Fault) matches(operation string) bool {
+	return f.Operation == AnyOperation || f.Operation == operation
+}
+
+// injector injects faults in calls to the AMP API. It is safe for concurrent
+// use.
+type injector struct {
+	faults []Fault
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// newInjector returns an injector injecting the supplied faults. The faults
+// are drawn independently for each call: the latencies of the drawn faults
+// add up and the call fails with the error of the first drawn fault with an
+// error code.
+func newInjector(faults []Fault) *injector {
+	return &injector{
+		faults: faults,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// draw returns true with the supplied probability.
+func (i *injector) draw(probability float64) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < probability
+}
+
+// inject injects the faults of a call to the supplied operation. It returns
+// the error the call fails with, if any.
+func (i *injector) inject(ctx context.Context, operation string) error {
+	var latency time.Duration
+	var err error
+	for _, f := range i.faults {
+		if !f.matches(operation) || !i.draw(f.Probability) {
+			continue
+		}
+		latency += f.Latency
+		if err == nil && f.ErrorCode != "" {
+			err = newError(f.ErrorCode, operation)
+		}
+	}
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return awserr.New(request.CanceledErrorCode, "request context canceled", ctx.Err())
+		}
+	}
+	return err
+}
+
+// newError returns an AWS error with the supplied code, as returned by AMP.
+func newError(code string, operation string) error {
+	status, ok := httpStatusCodes[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	return awserr.NewRequestFailure(
+		awserr.New(code, "injected fault in "+operation, nil),
+		status, "",
+	)
+}
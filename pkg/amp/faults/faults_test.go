@@ -0,0 +1,163 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package faults
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+)
+
+// errorCode returns the AWS error code of the supplied error, or an empty
+// string if it is not an AWS error.
+func errorCode(err error) string {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code()
+	}
+	return ""
+}
+
+func TestInject(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults []Fault
+		// timeout is the timeout of the call, if any.
+		timeout     time.Duration
+		wantErrCode string
+		wantStatus  int
+		wantCalled  bool
+	}{
+		{
+			name:       "no faults",
+			wantCalled: true,
+		},
+		{
+			name: "other operation",
+			faults: []Fault{
+				{Operation: "DeleteWorkspace", Probability: 1, ErrorCode: svcsdk.ErrCodeThrottlingException},
+			},
+			wantCalled: true,
+		},
+		{
+			name: "never drawn",
+			faults: []Fault{
+				{Operation: "CreateWorkspace", Probability: 0, ErrorCode: svcsdk.ErrCodeThrottlingException},
+			},
+			wantCalled: true,
+		},
+		{
+			name: "error",
+			faults: []Fault{
+				{Operation: "CreateWorkspace", Probability: 1, ErrorCode: svcsdk.ErrCodeThrottlingException},
+			},
+			wantErrCode: svcsdk.ErrCodeThrottlingException,
+			wantStatus:  http.StatusTooManyRequests,
+		},
+		{
+			name: "any operation",
+			faults: []Fault{
+				{Operation: AnyOperation, Probability: 1, ErrorCode: svcsdk.ErrCodeAccessDeniedException},
+			},
+			wantErrCode: svcsdk.ErrCodeAccessDeniedException,
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name: "unknown error code",
+			faults: []Fault{
+				{Operation: "CreateWorkspace", Probability: 1, ErrorCode: "ServiceUnavailable"},
+			},
+			wantErrCode: "ServiceUnavailable",
+			wantStatus:  http.StatusInternalServerError,
+		},
+		{
+			name: "first error",
+			faults: []Fault{
+				{Operation: "CreateWorkspace", Probability: 0, ErrorCode: svcsdk.ErrCodeValidationException},
+				{Operation: "CreateWorkspace", Probability: 1, ErrorCode: svcsdk.ErrCodeConflictException},
+				{Operation: AnyOperation, Probability: 1, ErrorCode: svcsdk.ErrCodeThrottlingException},
+			},
+			wantErrCode: svcsdk.ErrCodeConflictException,
+			wantStatus:  http.StatusConflict,
+		},
+		{
+			name: "latency",
+			faults: []Fault{
+				{Operation: "CreateWorkspace", Probability: 1, Latency: time.Millisecond},
+			},
+			timeout:    time.Minute,
+			wantCalled: true,
+		},
+		{
+			name: "timeout",
+			faults: []Fault{
+				{Operation: "CreateWorkspace", Probability: 1, Latency: time.Hour},
+			},
+			timeout:     10 * time.Millisecond,
+			wantErrCode: request.CanceledErrorCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ampfake.New("111111111111", "us-west-2")
+			api := New(fake, tt.faults...)
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			_, err := api.CreateWorkspaceWithContext(ctx, &svcsdk.CreateWorkspaceInput{})
+			if code := errorCode(err); code != tt.wantErrCode || (tt.wantErrCode == "" && err != nil) {
+				t.Errorf("CreateWorkspace() error = %v, want %q", err, tt.wantErrCode)
+			}
+			if tt.wantStatus != 0 {
+				if reqErr, ok := err.(awserr.RequestFailure); !ok || reqErr.StatusCode() != tt.wantStatus {
+					t.Errorf("CreateWorkspace() error = %v, want a %d status code", err, tt.wantStatus)
+				}
+			}
+			if called := len(fake.Calls()) > 0; called != tt.wantCalled {
+				t.Errorf("CreateWorkspace() called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestInjectProbability(t *testing.T) {
+	api := New(ampfake.New("111111111111", "us-west-2"), Fault{
+		Operation:   "DescribeWorkspace",
+		Probability: 0.25,
+		ErrorCode:   svcsdk.ErrCodeThrottlingException,
+	})
+	api.rand = rand.New(rand.NewSource(1))
+
+	const calls = 10000
+	faulty := 0
+	for i := 0; i < calls; i++ {
+		if err := api.inject(context.TODO(), "DescribeWorkspace"); err != nil {
+			faulty++
+		}
+	}
+	if faulty < calls/5 || faulty > calls*3/10 {
+		t.Errorf("%d faulty calls out of %d, want about a quarter", faulty, calls)
+	}
+}
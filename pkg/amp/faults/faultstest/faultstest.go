@@ -0,0 +1,195 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package faultstest provides helpers for the tests injecting faults in the
+// calls of the resource managers to the AMP API.
+package faultstest
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+	ampfaults "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/faults"
+)
+
+// Case is a test case injecting faults in a method of a resource manager.
+type Case struct {
+	Name   string
+	Faults []ampfaults.Fault
+	// Timeout is the timeout of the calls to the API, if any.
+	Timeout time.Duration
+	// Operation is the resource manager method the faults are injected in:
+	// Create, Update or Delete.
+	Operation     string
+	WantErrCode   string
+	WantCondition ackv1alpha1.ConditionType
+}
+
+// Kind describes the resources of a kind to the test cases.
+type Kind struct {
+	// NewAPI returns the fake AMP API of a test case.
+	NewAPI func() *ampfake.API
+	// NewResourceManager returns a resource manager calling the supplied AMP
+	// API.
+	NewResourceManager func(api svcsdkapi.PrometheusServiceAPI) acktypes.AWSResourceManager
+	// Descriptor is the resource descriptor of the kind.
+	Descriptor acktypes.AWSResourceDescriptor
+	// NewResource returns the resource created by the test cases, in the
+	// supplied fake AMP API.
+	NewResource func(t testing.TB, api *ampfake.API) acktypes.AWSResource
+	// Update changes the spec of the supplied resource, for the test cases
+	// of the Update operation.
+	Update func(desired acktypes.AWSResource)
+	// StatusCode returns the AMP status code of the supplied resource.
+	StatusCode func(res acktypes.AWSResource) *string
+}
+
+// Run runs the supplied test cases. Each test case calls the resource
+// manager method of the case with the faults of the case injected, and
+// checks that the returned resource has the expected condition. Unless the
+// condition is Terminal, the method is then called again without faults, and
+// the resource must converge: it becomes ACTIVE, or is deleted, and its spec
+// is the desired one.
+func Run(t *testing.T, kind Kind, tests []Case) {
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			api := kind.NewAPI()
+			rm := kind.NewResourceManager(api)
+			faulty := kind.NewResourceManager(ampfaults.New(api, tt.Faults...))
+			desired := kind.NewResource(t, api)
+			ctx := context.Background()
+			if tt.Timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.Timeout)
+				defer cancel()
+			}
+
+			// reconcile calls the resource manager method of the test with
+			// the supplied resource manager.
+			var latest acktypes.AWSResource
+			reconcile := func(rm acktypes.AWSResourceManager, ctx context.Context) (acktypes.AWSResource, error) {
+				switch tt.Operation {
+				case "Update":
+					return rm.Update(ctx, desired, latest, kind.Descriptor.Delta(desired, latest))
+				case "Delete":
+					return rm.Delete(ctx, latest)
+				default:
+					return rm.Create(ctx, desired)
+				}
+			}
+			if tt.Operation != "Create" {
+				latest = ampfake.CreateAndSettle(t, api, rm, desired)
+			}
+			if tt.Operation == "Update" {
+				desired = latest.DeepCopy()
+				kind.Update(desired)
+			}
+
+			res, err := reconcile(faulty, ctx)
+			if err == nil {
+				t.Fatalf("%s() error = nil, want a %s", tt.Operation, tt.WantErrCode)
+			}
+			AssertCondition(t, res, tt.WantCondition, tt.WantErrCode)
+			if tt.WantCondition == ackv1alpha1.ConditionTypeTerminal {
+				return
+			}
+
+			// The resource converges once the faults are gone.
+			if tt.Operation == "Update" {
+				if latest, err = rm.ReadOne(context.TODO(), latest); err != nil {
+					t.Fatalf("ReadOne() error = %v", err)
+				}
+			}
+			res, err = reconcile(rm, context.TODO())
+			if err != nil {
+				t.Fatalf("%s() error = %v", tt.Operation, err)
+			}
+			if tt.Operation == "Create" {
+				latest = res
+			}
+			api.Settle()
+			latest, err = rm.ReadOne(context.TODO(), latest)
+			if tt.Operation == "Delete" {
+				if err != ackerr.NotFound {
+					t.Errorf("ReadOne() error = %v, want NotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadOne() error = %v", err)
+			}
+			if got := aws.StringValue(kind.StatusCode(latest)); got != "ACTIVE" {
+				t.Errorf("status = %s, want ACTIVE", got)
+			}
+			if got, want := spec(t, latest), spec(t, desired); !reflect.DeepEqual(got, want) {
+				t.Errorf("spec = %v, want %v", got, want)
+			}
+			if c := ackcondition.FirstOfType(latest, ackv1alpha1.ConditionTypeRecoverable); c != nil && c.Status == corev1.ConditionTrue {
+				t.Errorf("Recoverable condition = %s, want not True", *c.Message)
+			}
+		})
+	}
+}
+
+// spec returns the spec of the supplied resource.
+func spec(t testing.TB, res acktypes.AWSResource) interface{} {
+	t.Helper()
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(res.RuntimeObject())
+	if err != nil {
+		t.Fatalf("ToUnstructured() error = %v", err)
+	}
+	return obj["spec"]
+}
+
+// AssertCondition checks that the supplied resource has a condition of the
+// supplied type with the True status and a message containing the supplied
+// error code, and no other True Terminal or Recoverable condition.
+func AssertCondition(
+	t testing.TB,
+	res acktypes.AWSResource,
+	conditionType ackv1alpha1.ConditionType,
+	errCode string,
+) {
+	t.Helper()
+	for _, ct := range []ackv1alpha1.ConditionType{
+		ackv1alpha1.ConditionTypeTerminal,
+		ackv1alpha1.ConditionTypeRecoverable,
+	} {
+		c := ackcondition.FirstOfType(res, ct)
+		isTrue := c != nil && c.Status == corev1.ConditionTrue
+		if ct != conditionType {
+			if isTrue {
+				t.Errorf("%s condition = %s, want not True", ct, *c.Message)
+			}
+			continue
+		}
+		if !isTrue {
+			t.Errorf("%s condition = %v, want True", ct, c)
+		} else if c.Message == nil || !strings.Contains(*c.Message, errCode) {
+			t.Errorf("%s condition message = %v, want %s", ct, c.Message, errCode)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package faults
+
+import (
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-logr/logr"
+)
+
+// handlerName is the name of the request handler injecting the faults.
+const handlerName = "faults.Inject"
+
+// handler returns a request handler injecting the faults of the supplied
+// injector in the requests it is run for. It is run with the Validate
+// handlers, so the faulty requests fail before they are sent and are not
+// retried, like the calls to API.
+func handler(i *injector) request.NamedHandler {
+	return request.NamedHandler{
+		Name: handlerName,
+		Fn: func(r *request.Request) {
+			if err := i.inject(r.Context(), r.Operation.Name); err != nil {
+				r.Error = err
+			}
+		},
+	}
+}
+
+// managerFactory wraps a resource manager factory. The resource managers it
+// returns call the AMP API with a session injecting faults in the requests.
+type managerFactory struct {
+	acktypes.AWSResourceManagerFactory
+
+	handler request.NamedHandler
+}
+
+// ManagerFor returns the resource manager of the wrapped factory for the
+// supplied AWS account and region, created with a copy of the supplied
+// session injecting faults in the requests.
+func (f *managerFactory) ManagerFor(
+	cfg ackcfg.Config,
+	log logr.Logger,
+	metrics *ackmetrics.Metrics,
+	rr acktypes.Reconciler,
+	sess *session.Session,
+	id ackv1alpha1.AWSAccountID,
+	region ackv1alpha1.AWSRegion,
+) (acktypes.AWSResourceManager, error) {
+	sess = sess.Copy()
+	sess.Handlers.Validate.PushBackNamed(f.handler)
+	return f.AWSResourceManagerFactory.ManagerFor(cfg, log, metrics, rr, sess, id, region)
+}
+
+// wrapManagerFactories returns the supplied resource manager factories,
+// wrapped to inject the supplied faults in the calls of their resource
+// managers to the AMP API.
+func wrapManagerFactories(
+	factories []acktypes.AWSResourceManagerFactory,
+	faults []Fault,
+) []acktypes.AWSResourceManagerFactory {
+	h := handler(newInjector(faults))
+	wrapped := make([]acktypes.AWSResourceManagerFactory, 0, len(factories))
+	for _, f := range factories {
+		wrapped = append(wrapped, &managerFactory{AWSResourceManagerFactory: f, handler: h})
+	}
+	return wrapped
+}
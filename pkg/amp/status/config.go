@@ -0,0 +1,97 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package status
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	flagStalledTimeoutSeconds = "stalled-timeout-seconds"
+)
+
+// machinesByKind are the state machines keyed by the lower case name of the
+// kind of their resources.
+var machinesByKind = map[string]*Machine{
+	"workspace":              Workspace,
+	"rulegroupsnamespace":    RuleGroupsNamespace,
+	"alertmanagerdefinition": AlertManagerDefinition,
+	"loggingconfiguration":   LoggingConfiguration,
+}
+
+// Config contains the configuration of the stalled resources detection.
+type Config struct {
+	// StalledTimeoutSeconds are the stalled timeouts of the kinds of
+	// resources, as "kind=seconds".
+	StalledTimeoutSeconds []string
+}
+
+// BindFlags defines the command line flags of the stalled resources
+// detection.
+func (cfg *Config) BindFlags() {
+	flag.StringArrayVar(
+		&cfg.StalledTimeoutSeconds, flagStalledTimeoutSeconds,
+		[]string{},
+		"A Key/Value list of strings representing the duration, in seconds, after which resources of a kind "+
+			"still CREATING, UPDATING or DELETING are reported stalled, e.g. 'Workspace=3600'. "+
+			"Set to 0 to never report the resources of a kind stalled.",
+	)
+}
+
+// StalledTimeouts are the stalled timeouts of the state machines.
+type StalledTimeouts map[*Machine]time.Duration
+
+// For returns the stalled timeout of the supplied state machine, which is
+// its default stalled timeout unless another timeout is configured.
+func (t StalledTimeouts) For(m *Machine) time.Duration {
+	if timeout, ok := t[m]; ok {
+		return timeout
+	}
+	return m.DefaultStalledTimeout
+}
+
+// Validate ensures the options are valid
+func (cfg *Config) Validate() error {
+	if _, err := cfg.StalledTimeouts(); err != nil {
+		return fmt.Errorf("invalid value for flag '%s': %v", flagStalledTimeoutSeconds, err)
+	}
+	return nil
+}
+
+// StalledTimeouts returns the configured stalled timeouts of the state
+// machines.
+func (cfg *Config) StalledTimeouts() (StalledTimeouts, error) {
+	timeouts := make(StalledTimeouts, len(cfg.StalledTimeoutSeconds))
+	for _, arg := range cfg.StalledTimeoutSeconds {
+		elements := strings.Split(arg, "=")
+		if len(elements) != 2 || elements[0] == "" {
+			return nil, fmt.Errorf("error parsing flag argument '%s'. Expected format: kind=seconds", arg)
+		}
+		m, ok := machinesByKind[strings.ToLower(elements[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown kind '%s'", elements[0])
+		}
+		seconds, err := strconv.Atoi(elements[1])
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("expected a non-negative number of seconds for kind '%s', got '%s'", elements[0], elements[1])
+		}
+		timeouts[m] = time.Duration(seconds) * time.Second
+	}
+	return timeouts, nil
+}
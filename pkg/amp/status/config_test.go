@@ -0,0 +1,65 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package status
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "no timeouts"},
+		{name: "valid timeouts", args: []string{"Workspace=3600", "rulegroupsnamespace=0"}},
+		{name: "missing value", args: []string{"Workspace"}, wantErr: true},
+		{name: "missing kind", args: []string{"=60"}, wantErr: true},
+		{name: "unknown kind", args: []string{"Scraper=60"}, wantErr: true},
+		{name: "not a number", args: []string{"Workspace=1h"}, wantErr: true},
+		{name: "negative", args: []string{"Workspace=-1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{StalledTimeoutSeconds: tt.args}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigStalledTimeouts(t *testing.T) {
+	cfg := &Config{StalledTimeoutSeconds: []string{"Workspace=3600", "AlertManagerDefinition=0"}}
+	timeouts, err := cfg.StalledTimeouts()
+	if err != nil {
+		t.Fatalf("StalledTimeouts() error = %v", err)
+	}
+	want := map[*Machine]time.Duration{
+		Workspace:              time.Hour,
+		RuleGroupsNamespace:    15 * time.Minute,
+		AlertManagerDefinition: 0,
+		LoggingConfiguration:   15 * time.Minute,
+	}
+	for m, timeout := range want {
+		if got := timeouts.For(m); got != timeout {
+			t.Errorf("%s stalled timeout = %v, want %v", m.Resource, got, timeout)
+		}
+		if got := StalledTimeouts(nil).For(m); got != m.DefaultStalledTimeout {
+			t.Errorf("%s stalled timeout = %v without configured timeouts, want %v", m.Resource, got, m.DefaultStalledTimeout)
+		}
+	}
+}
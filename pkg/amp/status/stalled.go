@@ -0,0 +1,112 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package status
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeStalled is set to True on resources that have been in the
+	// status of an ongoing operation for longer than the stalled timeout of
+	// their kind.
+	ConditionTypeStalled ackv1alpha1.ConditionType = "Stalled"
+)
+
+var (
+	// StalledRequeueAfter is the delay before a stalled resource is
+	// reconciled again.
+	StalledRequeueAfter = 5 * time.Minute
+
+	// now returns the current time. It is replaced in tests.
+	now = time.Now
+)
+
+// OperationStartTime returns the time at which a resource entered the
+// supplied status code, given its previous status code and the time at which
+// it entered it. It returns nil for the statuses of no ongoing operation.
+func (m *Machine) OperationStartTime(
+	prevCode *string,
+	prevStartTime *metav1.Time,
+	code *string,
+) *metav1.Time {
+	s, ok := m.state(code)
+	if !ok || s.RequeueAfter == 0 {
+		return nil
+	}
+	if prevStartTime != nil && prevCode != nil && *prevCode == *code {
+		return prevStartTime
+	}
+	startTime := metav1.NewTime(now())
+	return &startTime
+}
+
+// stalled returns for how long a resource with the supplied status code has
+// been in this status, given the time at which it entered it, and whether
+// this exceeds the supplied stalled timeout.
+func (m *Machine) stalled(
+	code *string,
+	startTime *metav1.Time,
+	timeout time.Duration,
+) (time.Duration, bool) {
+	s, ok := m.state(code)
+	if !ok || s.RequeueAfter == 0 || startTime == nil || timeout <= 0 {
+		return 0, false
+	}
+	elapsed := now().Sub(startTime.Time)
+	return elapsed, elapsed >= timeout
+}
+
+// EnsureNotStalled sets the Stalled condition of the supplied resource to
+// True, marks it as not synced and returns an error requeueing it after
+// StalledRequeueAfter if it has been in the status of an ongoing operation
+// for longer than the supplied stalled timeout. The resource entered the
+// supplied status code at the supplied time.
+func (m *Machine) EnsureNotStalled(
+	res acktypes.ConditionManager,
+	code *string,
+	startTime *metav1.Time,
+	timeout time.Duration,
+) error {
+	elapsed, stalled := m.stalled(code, startTime, timeout)
+	if !stalled {
+		return nil
+	}
+	message := fmt.Sprintf(
+		"%s has been in '%s' state for %s",
+		m.Resource, *code, elapsed.Round(time.Second),
+	)
+	reason := string(ConditionTypeStalled)
+	condition := ackcondition.FirstOfType(res, ConditionTypeStalled)
+	if condition == nil {
+		condition = &ackv1alpha1.Condition{Type: ConditionTypeStalled}
+		res.ReplaceConditions(append(res.Conditions(), condition))
+	}
+	transitionTime := metav1.NewTime(now())
+	condition.Status = corev1.ConditionTrue
+	condition.LastTransitionTime = &transitionTime
+	condition.Message = &message
+	condition.Reason = &reason
+	ackcondition.SetSynced(res, corev1.ConditionFalse, nil, &reason)
+	return ackrequeue.NeededAfter(errors.New(message), StalledRequeueAfter)
+}
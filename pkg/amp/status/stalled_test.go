@@ -0,0 +1,138 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package status
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// conditions is a minimal `acktypes.ConditionManager`.
+type conditions struct {
+	conditions []*ackv1alpha1.Condition
+}
+
+func (c *conditions) Conditions() []*ackv1alpha1.Condition {
+	return c.conditions
+}
+
+func (c *conditions) ReplaceConditions(conditions []*ackv1alpha1.Condition) {
+	c.conditions = conditions
+}
+
+// setNow makes now return the supplied time until the end of the test.
+func setNow(t *testing.T, at time.Time) {
+	prev := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = prev })
+}
+
+func TestOperationStartTime(t *testing.T) {
+	at := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, at)
+	earlier := metav1.NewTime(at.Add(-time.Hour))
+
+	tests := []struct {
+		name          string
+		prevCode      *string
+		prevStartTime *metav1.Time
+		code          *string
+		want          *metav1.Time
+	}{
+		{name: "unknown status", code: nil, want: nil},
+		{name: "active", prevCode: code(Updating), prevStartTime: &earlier, code: code(Active), want: nil},
+		{name: "failed", prevCode: code(Creating), prevStartTime: &earlier, code: code(CreationFailed), want: nil},
+		{name: "new resource", code: code(Creating), want: &metav1.Time{Time: at}},
+		{name: "started operation", prevCode: code(Active), code: code(Updating), want: &metav1.Time{Time: at}},
+		{name: "next operation", prevCode: code(Creating), prevStartTime: &earlier, code: code(Deleting), want: &metav1.Time{Time: at}},
+		{name: "ongoing operation", prevCode: code(Updating), prevStartTime: &earlier, code: code(Updating), want: &earlier},
+		{name: "ongoing operation without start time", prevCode: code(Updating), code: code(Updating), want: &metav1.Time{Time: at}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RuleGroupsNamespace.OperationStartTime(tt.prevCode, tt.prevStartTime, tt.code)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(tt.want)) {
+				t.Errorf("OperationStartTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureNotStalled(t *testing.T) {
+	at := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, at)
+	startTime := metav1.NewTime(at.Add(-(20*time.Minute + 300*time.Millisecond)))
+	recent := metav1.NewTime(at.Add(-time.Minute))
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		code      *string
+		startTime *metav1.Time
+		wantMsg   string
+	}{
+		{name: "not started", timeout: 15 * time.Minute, code: code(Creating)},
+		{name: "not busy", timeout: 15 * time.Minute, code: code(Active), startTime: &startTime},
+		{name: "recently started", timeout: 15 * time.Minute, code: code(Updating), startTime: &recent},
+		{name: "disabled", timeout: 0, code: code(Updating), startTime: &startTime},
+		{
+			name:      "stalled",
+			timeout:   15 * time.Minute,
+			code:      code(Updating),
+			startTime: &startTime,
+			wantMsg:   "Logging Configuration has been in 'UPDATING' state for 20m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newDefinitionMachine("Logging Configuration")
+			res := &conditions{}
+			err := m.EnsureNotStalled(res, tt.code, tt.startTime, tt.timeout)
+			stalled := ackcondition.FirstOfType(res, ConditionTypeStalled)
+			if tt.wantMsg == "" {
+				if err != nil || stalled != nil {
+					t.Errorf("EnsureNotStalled() = %v, condition %v, want neither", err, stalled)
+				}
+				return
+			}
+			var requeue *ackrequeue.RequeueNeededAfter
+			if !errors.As(err, &requeue) || requeue.Duration() != StalledRequeueAfter {
+				t.Fatalf("EnsureNotStalled() = %v, want a requeue after %v", err, StalledRequeueAfter)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("EnsureNotStalled() = %q, want %q", err.Error(), tt.wantMsg)
+			}
+			if stalled == nil || stalled.Status != corev1.ConditionTrue || *stalled.Message != tt.wantMsg {
+				t.Errorf("EnsureNotStalled() stalled condition = %v, want message %q", stalled, tt.wantMsg)
+			}
+			synced := ackcondition.Synced(res)
+			if synced == nil || synced.Status != corev1.ConditionFalse {
+				t.Errorf("EnsureNotStalled() synced condition = %v, want False", synced)
+			}
+
+			// The existing condition is updated rather than duplicated.
+			_ = m.EnsureNotStalled(res, tt.code, tt.startTime, tt.timeout)
+			if n := len(res.Conditions()); n != 2 {
+				t.Errorf("EnsureNotStalled() set %d conditions, want 2", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package status describes the lifecycle of the AMP resources as state
+// machines: the status codes the API reports for each kind of resource, the
+// transitions between them, and how the controller handles a resource in
+// each of them.
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+)
+
+// Code is a status code reported by the AMP API.
+type Code string
+
+const (
+	Creating       Code = "CREATING"
+	Active         Code = "ACTIVE"
+	Updating       Code = "UPDATING"
+	Deleting       Code = "DELETING"
+	CreationFailed Code = "CREATION_FAILED"
+	UpdateFailed   Code = "UPDATE_FAILED"
+)
+
+// State describes how the controller handles a resource in a status.
+type State struct {
+	// Next are the statuses the resource can move to from this status.
+	Next []Code
+	// RequeueAfter is the delay before a resource in this status is
+	// reconciled again. It is only set for the statuses of ongoing
+	// operations, in which the API rejects modifications with a
+	// ConflictException.
+	RequeueAfter time.Duration
+	// Terminal is true for the statuses of failed operations, which the
+	// resource only leaves when its spec is changed, if at all.
+	Terminal bool
+	// Updatable is true for the statuses in which the resource can be
+	// updated.
+	Updatable bool
+}
+
+// Machine is the state machine of a kind of AMP resources.
+type Machine struct {
+	// Resource is the name of the kind of resources, such as "Rule Groups
+	// Namespace", used in error messages.
+	Resource string
+	// DefaultStalledTimeout is the duration after which a resource still in
+	// the status of an ongoing operation is reported stalled, unless another
+	// timeout is configured with --stalled-timeout-seconds. Resources are
+	// never reported stalled if the timeout is zero.
+	DefaultStalledTimeout time.Duration
+	// States are the states of the resources, keyed by status code.
+	States map[Code]State
+}
+
+var (
+	// Workspace is the state machine of workspaces. Only the alias of a
+	// workspace can be updated, and only while it is ACTIVE.
+	Workspace = &Machine{
+		Resource:              "Workspace",
+		DefaultStalledTimeout: 30 * time.Minute,
+		States: map[Code]State{
+			Creating: {
+				Next:         []Code{Active, CreationFailed},
+				RequeueAfter: 15 * time.Second,
+			},
+			Active: {
+				Next:      []Code{Updating, Deleting},
+				Updatable: true,
+			},
+			Updating: {
+				Next:         []Code{Active},
+				RequeueAfter: 10 * time.Second,
+			},
+			Deleting: {
+				RequeueAfter: 10 * time.Second,
+			},
+			CreationFailed: {
+				Next:     []Code{Deleting},
+				Terminal: true,
+			},
+		},
+	}
+
+	// RuleGroupsNamespace is the state machine of rule groups namespaces.
+	RuleGroupsNamespace = newDefinitionMachine("Rule Groups Namespace")
+
+	// AlertManagerDefinition is the state machine of alert manager
+	// definitions.
+	AlertManagerDefinition = newDefinitionMachine("Alert Manager Definition")
+
+	// LoggingConfiguration is the state machine of logging configurations.
+	LoggingConfiguration = newDefinitionMachine("Logging Configuration")
+)
+
+// newDefinitionMachine returns the state machine of the resources whose
+// definition AMP validates asynchronously. The creation or update of these
+// resources can fail after the API accepted it, and the resources can then be
+// fixed by updating them.
+func newDefinitionMachine(resource string) *Machine {
+	return &Machine{
+		Resource:              resource,
+		DefaultStalledTimeout: 15 * time.Minute,
+		States: map[Code]State{
+			Creating: {
+				Next:         []Code{Active, CreationFailed},
+				RequeueAfter: 15 * time.Second,
+			},
+			Active: {
+				Next:      []Code{Updating, Deleting},
+				Updatable: true,
+			},
+			Updating: {
+				Next:         []Code{Active, UpdateFailed},
+				RequeueAfter: 10 * time.Second,
+			},
+			Deleting: {
+				RequeueAfter: 10 * time.Second,
+			},
+			CreationFailed: {
+				Next:      []Code{Updating, Deleting},
+				Terminal:  true,
+				Updatable: true,
+			},
+			UpdateFailed: {
+				Next:      []Code{Updating, Deleting},
+				Terminal:  true,
+				Updatable: true,
+			},
+		},
+	}
+}
+
+// BusyError is returned for resources in the status of an ongoing operation.
+type BusyError struct {
+	Resource string
+	Code     Code
+}
+
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("%s is in '%s' state, cannot be modified or deleted", e.Resource, e.Code)
+}
+
+// state returns the state of the supplied status code and whether it is a
+// known status of the resources.
+func (m *Machine) state(code *string) (State, bool) {
+	if code == nil {
+		return State{}, false
+	}
+	s, ok := m.States[Code(*code)]
+	return s, ok
+}
+
+// Is returns true if the supplied status code is one of the supplied ones.
+func (m *Machine) Is(code *string, codes ...Code) bool {
+	if code == nil {
+		return false
+	}
+	for _, c := range codes {
+		if Code(*code) == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Terminal returns true if the supplied status code is the status of a
+// failed operation.
+func (m *Machine) Terminal(code *string) bool {
+	s, _ := m.state(code)
+	return s.Terminal
+}
+
+// Updatable returns true if a resource with the supplied status code can be
+// updated.
+func (m *Machine) Updatable(code *string) bool {
+	s, _ := m.state(code)
+	return s.Updatable
+}
+
+// CanTransition returns true if a resource can move from the first supplied
+// status code to the second one. A resource can stay in the same status, and
+// can be in any known status when its previous status is unknown.
+func (m *Machine) CanTransition(from *string, to *string) bool {
+	if _, ok := m.state(to); !ok {
+		return false
+	}
+	s, ok := m.state(from)
+	if !ok || *from == *to {
+		return true
+	}
+	for _, c := range s.Next {
+		if Code(*to) == c {
+			return true
+		}
+	}
+	return false
+}
+
+// ObserveTransition returns true if a resource can move from the first
+// supplied status code to the second one. Otherwise, it logs the transition,
+// so that the differences between the state machine and the behavior of the
+// API get noticed, and returns false.
+func (m *Machine) ObserveTransition(ctx context.Context, from *string, to *string) bool {
+	if m.CanTransition(from, to) {
+		return true
+	}
+	ackrtlog.FromContext(ctx).Info(
+		"unexpected status transition",
+		"resource", m.Resource,
+		"from", codeString(from),
+		"to", codeString(to),
+	)
+	return false
+}
+
+// codeString returns the supplied status code, or an empty string if it is
+// nil.
+func codeString(code *string) string {
+	if code == nil {
+		return ""
+	}
+	return *code
+}
+
+// RequeueWhileBusy returns an error requeueing a resource with the supplied
+// status code after the delay of its state if the status is the one of an
+// ongoing operation. It returns nil otherwise, including for unknown
+// statuses, so that the API decides whether the resource can be modified.
+func (m *Machine) RequeueWhileBusy(code *string) error {
+	s, ok := m.state(code)
+	if !ok || s.RequeueAfter == 0 {
+		return nil
+	}
+	return ackrequeue.NeededAfter(
+		&BusyError{Resource: m.Resource, Code: Code(*code)},
+		s.RequeueAfter,
+	)
+}
@@ -0,0 +1,227 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+var machines = map[string]*Machine{
+	"Workspace":              Workspace,
+	"RuleGroupsNamespace":    RuleGroupsNamespace,
+	"AlertManagerDefinition": AlertManagerDefinition,
+	"LoggingConfiguration":   LoggingConfiguration,
+}
+
+func code(c Code) *string {
+	s := string(c)
+	return &s
+}
+
+// TestStatesMatchAPI checks that the state machines know all the status
+// codes of the API, and only those.
+func TestStatesMatchAPI(t *testing.T) {
+	apiCodes := map[string][]string{
+		"Workspace":              svcsdk.WorkspaceStatusCode_Values(),
+		"RuleGroupsNamespace":    svcsdk.RuleGroupsNamespaceStatusCode_Values(),
+		"AlertManagerDefinition": svcsdk.AlertManagerDefinitionStatusCode_Values(),
+		"LoggingConfiguration":   svcsdk.LoggingConfigurationStatusCode_Values(),
+	}
+	for name, m := range machines {
+		codes := apiCodes[name]
+		if len(codes) != len(m.States) {
+			t.Errorf("%s has %d states, want %d", name, len(m.States), len(codes))
+		}
+		for _, c := range codes {
+			if _, ok := m.States[Code(c)]; !ok {
+				t.Errorf("%s has no state for status %s", name, c)
+			}
+		}
+	}
+}
+
+// TestStatesConsistent checks the invariants of the state tables.
+func TestStatesConsistent(t *testing.T) {
+	for name, m := range machines {
+		for c, s := range m.States {
+			for _, next := range s.Next {
+				if _, ok := m.States[next]; !ok {
+					t.Errorf("%s: %s moves to unknown status %s", name, c, next)
+				}
+				if next == c {
+					t.Errorf("%s: %s lists itself as a next status", name, c)
+				}
+			}
+			if s.RequeueAfter > 0 && (s.Terminal || s.Updatable) {
+				t.Errorf("%s: busy status %s is terminal or updatable", name, c)
+			}
+			if c != Deleting && len(s.Next) == 0 {
+				t.Errorf("%s: %s is a dead end", name, c)
+			}
+		}
+		if len(m.States[Deleting].Next) != 0 {
+			t.Errorf("%s: a deleted resource cannot move to another status", name)
+		}
+	}
+}
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  *string
+		codes []Code
+		want  bool
+	}{
+		{name: "nil status code", code: nil, codes: []Code{Active}, want: false},
+		{name: "matching status code", code: code(Active), codes: []Code{Active}, want: true},
+		{name: "one of status codes", code: code(Updating), codes: []Code{Creating, Updating}, want: true},
+		{name: "different status code", code: code(Creating), codes: []Code{Active}, want: false},
+		{name: "no status codes", code: code(Active), codes: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Workspace.Is(tt.code, tt.codes...); got != tt.want {
+				t.Errorf("Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalAndUpdatable(t *testing.T) {
+	tests := []struct {
+		machine       *Machine
+		code          *string
+		wantTerminal  bool
+		wantUpdatable bool
+	}{
+		{machine: Workspace, code: nil},
+		{machine: Workspace, code: code("UNKNOWN")},
+		{machine: Workspace, code: code(Creating)},
+		{machine: Workspace, code: code(Active), wantUpdatable: true},
+		{machine: Workspace, code: code(Updating)},
+		{machine: Workspace, code: code(Deleting)},
+		{machine: Workspace, code: code(CreationFailed), wantTerminal: true},
+		{machine: RuleGroupsNamespace, code: nil},
+		{machine: RuleGroupsNamespace, code: code(Creating)},
+		{machine: RuleGroupsNamespace, code: code(Active), wantUpdatable: true},
+		{machine: RuleGroupsNamespace, code: code(Updating)},
+		{machine: RuleGroupsNamespace, code: code(Deleting)},
+		{machine: RuleGroupsNamespace, code: code(CreationFailed), wantTerminal: true, wantUpdatable: true},
+		{machine: RuleGroupsNamespace, code: code(UpdateFailed), wantTerminal: true, wantUpdatable: true},
+	}
+	for _, tt := range tests {
+		name := tt.machine.Resource + "/nil"
+		if tt.code != nil {
+			name = tt.machine.Resource + "/" + *tt.code
+		}
+		t.Run(name, func(t *testing.T) {
+			if got := tt.machine.Terminal(tt.code); got != tt.wantTerminal {
+				t.Errorf("Terminal() = %v, want %v", got, tt.wantTerminal)
+			}
+			if got := tt.machine.Updatable(tt.code); got != tt.wantUpdatable {
+				t.Errorf("Updatable() = %v, want %v", got, tt.wantUpdatable)
+			}
+		})
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		machine *Machine
+		from    *string
+		to      *string
+		want    bool
+	}{
+		{name: "unknown previous status", machine: Workspace, from: nil, to: code(Active), want: true},
+		{name: "unknown status", machine: Workspace, from: code(Active), to: nil, want: false},
+		{name: "unsupported status", machine: Workspace, from: code(Updating), to: code(UpdateFailed), want: false},
+		{name: "same status", machine: Workspace, from: code(Active), to: code(Active), want: true},
+		{name: "created", machine: Workspace, from: code(Creating), to: code(Active), want: true},
+		{name: "creation failed", machine: Workspace, from: code(Creating), to: code(CreationFailed), want: true},
+		{name: "workspace creation failed updated", machine: Workspace, from: code(CreationFailed), to: code(Updating), want: false},
+		{name: "definition creation failed updated", machine: RuleGroupsNamespace, from: code(CreationFailed), to: code(Updating), want: true},
+		{name: "update failed", machine: AlertManagerDefinition, from: code(Updating), to: code(UpdateFailed), want: true},
+		{name: "deleted", machine: LoggingConfiguration, from: code(Deleting), to: code(Active), want: false},
+		{name: "skipped creation", machine: RuleGroupsNamespace, from: code(Creating), to: code(Updating), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machine.CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserveTransition(t *testing.T) {
+	if !LoggingConfiguration.ObserveTransition(context.TODO(), code(Creating), code(Active)) {
+		t.Errorf("ObserveTransition() = false for an expected transition")
+	}
+	if LoggingConfiguration.ObserveTransition(context.TODO(), code(Deleting), code(Active)) {
+		t.Errorf("ObserveTransition() = true for an unexpected transition")
+	}
+	if Workspace.ObserveTransition(context.TODO(), code(Active), code("SUSPENDED")) {
+		t.Errorf("ObserveTransition() = true for an unknown status")
+	}
+}
+
+func TestRequeueWhileBusy(t *testing.T) {
+	tests := []struct {
+		code *string
+		want time.Duration
+	}{
+		{code: nil},
+		{code: code("UNKNOWN")},
+		{code: code(Active)},
+		{code: code(CreationFailed)},
+		{code: code(UpdateFailed)},
+		{code: code(Creating), want: 15 * time.Second},
+		{code: code(Updating), want: 10 * time.Second},
+		{code: code(Deleting), want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		name := "nil"
+		if tt.code != nil {
+			name = *tt.code
+		}
+		t.Run(name, func(t *testing.T) {
+			err := AlertManagerDefinition.RequeueWhileBusy(tt.code)
+			if tt.want == 0 {
+				if err != nil {
+					t.Errorf("RequeueWhileBusy() = %v, want nil", err)
+				}
+				return
+			}
+			var requeue *ackrequeue.RequeueNeededAfter
+			if !errors.As(err, &requeue) || requeue.Duration() != tt.want {
+				t.Fatalf("RequeueWhileBusy() = %v, want a requeue after %v", err, tt.want)
+			}
+			var busy *BusyError
+			if !errors.As(err, &busy) || busy.Code != Code(*tt.code) {
+				t.Errorf("RequeueWhileBusy() = %v, want a BusyError", err)
+			}
+			want := "Alert Manager Definition is in '" + *tt.code + "' state, cannot be modified or deleted"
+			if err.Error() != want {
+				t.Errorf("RequeueWhileBusy() = %q, want %q", err.Error(), want)
+			}
+		})
+	}
+}
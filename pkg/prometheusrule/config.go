@@ -0,0 +1,127 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package prometheusrule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	flagEnabled      = "prometheus-rule-sync"
+	flagSelector     = "prometheus-rule-selector"
+	flagGrouping     = "prometheus-rule-grouping"
+	flagWorkspaceID  = "prometheus-rule-workspace-id"
+	flagWorkspaceRef = "prometheus-rule-workspace-ref"
+)
+
+// Grouping determines how PrometheusRule objects are mapped to
+// RuleGroupsNamespace resources.
+type Grouping string
+
+const (
+	// GroupingObject generates one RuleGroupsNamespace per PrometheusRule,
+	// with the same name and namespace.
+	GroupingObject Grouping = "object"
+	// GroupingNamespace generates one RuleGroupsNamespace per Kubernetes
+	// namespace with the rule groups of all its selected PrometheusRules.
+	GroupingNamespace Grouping = "namespace"
+)
+
+// Config contains the configuration of the PrometheusRule sync.
+type Config struct {
+	// Enabled turns the PrometheusRule sync on.
+	Enabled bool
+	// Selector is the label selector of the synced PrometheusRules.
+	Selector string
+	// Grouping is either "object" or "namespace".
+	Grouping string
+	// WorkspaceID is the ID of the workspace of the generated resources.
+	WorkspaceID string
+	// WorkspaceRef is the name, optionally prefixed by its namespace and a
+	// slash, of the Workspace resource of the generated resources.
+	WorkspaceRef string
+}
+
+// BindFlags defines the command line flags of the PrometheusRule sync.
+func (cfg *Config) BindFlags() {
+	flag.BoolVar(
+		&cfg.Enabled, flagEnabled,
+		false,
+		"Generate RuleGroupsNamespace resources from prometheus-operator PrometheusRule objects.",
+	)
+	flag.StringVar(
+		&cfg.Selector, flagSelector,
+		"",
+		"The label selector of the PrometheusRule objects to generate RuleGroupsNamespace resources from. "+
+			"All PrometheusRule objects are selected by default.",
+	)
+	flag.StringVar(
+		&cfg.Grouping, flagGrouping,
+		string(GroupingObject),
+		"Either 'object', to generate one RuleGroupsNamespace per PrometheusRule, or 'namespace', "+
+			"to generate one RuleGroupsNamespace per Kubernetes namespace.",
+	)
+	flag.StringVar(
+		&cfg.WorkspaceID, flagWorkspaceID,
+		"",
+		"The ID of the workspace of the generated RuleGroupsNamespace resources.",
+	)
+	flag.StringVar(
+		&cfg.WorkspaceRef, flagWorkspaceRef,
+		"",
+		"The Workspace resource, as 'name' or 'namespace/name', of the generated RuleGroupsNamespace resources. "+
+			"A Workspace without a namespace is looked up in the namespace of each generated resource.",
+	)
+}
+
+// Validate ensures the options are valid
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if _, err := labels.Parse(cfg.Selector); err != nil {
+		return fmt.Errorf("invalid value for flag '%s': %v", flagSelector, err)
+	}
+	switch Grouping(cfg.Grouping) {
+	case GroupingObject, GroupingNamespace:
+	default:
+		return fmt.Errorf(
+			"invalid value for flag '%s': must be '%s' or '%s'",
+			flagGrouping, GroupingObject, GroupingNamespace,
+		)
+	}
+	if (cfg.WorkspaceID == "") == (cfg.WorkspaceRef == "") {
+		return fmt.Errorf("exactly one of the flags '%s' and '%s' must be set", flagWorkspaceID, flagWorkspaceRef)
+	}
+	if cfg.WorkspaceRef != "" {
+		if _, name := cfg.workspaceRef(); name == "" {
+			return errors.New("invalid value for flag '" + flagWorkspaceRef + "': empty Workspace name")
+		}
+	}
+	return nil
+}
+
+// workspaceRef returns the namespace and name of the configured Workspace
+// resource. The namespace is empty if it is not part of the reference.
+func (cfg *Config) workspaceRef() (string, string) {
+	if i := strings.Index(cfg.WorkspaceRef, "/"); i >= 0 {
+		return cfg.WorkspaceRef[:i], cfg.WorkspaceRef[i+1:]
+	}
+	return "", cfg.WorkspaceRef
+}
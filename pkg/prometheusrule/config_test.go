@@ -0,0 +1,79 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package prometheusrule
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{Grouping: "invalid"},
+		},
+		{
+			name: "workspace ID",
+			cfg:  Config{Enabled: true, Grouping: "object", WorkspaceID: "ws-1"},
+		},
+		{
+			name: "workspace reference with a namespace",
+			cfg:  Config{Enabled: true, Selector: "amp=true", Grouping: "namespace", WorkspaceRef: "monitoring/amp"},
+		},
+		{
+			name:    "invalid selector",
+			cfg:     Config{Enabled: true, Selector: "amp in", Grouping: "object", WorkspaceID: "ws-1"},
+			wantErr: flagSelector,
+		},
+		{
+			name:    "invalid grouping",
+			cfg:     Config{Enabled: true, Grouping: "cluster", WorkspaceID: "ws-1"},
+			wantErr: flagGrouping,
+		},
+		{
+			name:    "no workspace",
+			cfg:     Config{Enabled: true, Grouping: "object"},
+			wantErr: "exactly one",
+		},
+		{
+			name:    "workspace ID and reference",
+			cfg:     Config{Enabled: true, Grouping: "object", WorkspaceID: "ws-1", WorkspaceRef: "amp"},
+			wantErr: "exactly one",
+		},
+		{
+			name:    "empty workspace name",
+			cfg:     Config{Enabled: true, Grouping: "object", WorkspaceRef: "monitoring/"},
+			wantErr: "empty Workspace name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
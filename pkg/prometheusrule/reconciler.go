@@ -0,0 +1,312 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package prometheusrule generates RuleGroupsNamespace resources from
+// prometheus-operator PrometheusRule objects, so that rules written for
+// in-cluster Prometheus can be evaluated by AMP without being rewritten.
+package prometheusrule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch
+
+const (
+	// LabelGeneratedBy is set on the RuleGroupsNamespace resources generated
+	// from PrometheusRule objects. Resources without it are never modified.
+	LabelGeneratedBy = "prometheusservice.services.k8s.aws/generated-by"
+	generatedBy      = "prometheus-rule-sync"
+
+	// namespaceResourceName is the name of the RuleGroupsNamespace generated
+	// for a Kubernetes namespace with the "namespace" grouping.
+	namespaceResourceName = "prometheus-rules"
+)
+
+var (
+	// GroupVersionKind is the GroupVersionKind of the prometheus-operator
+	// PrometheusRule objects.
+	GroupVersionKind = schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1",
+		Kind:    "PrometheusRule",
+	}
+)
+
+// reconciler generates a RuleGroupsNamespace for each selected PrometheusRule
+// or for each Kubernetes namespace containing selected PrometheusRules. The
+// reconcile requests are the namespace and name of a PrometheusRule with the
+// "object" grouping and only the namespace with the "namespace" grouping.
+type reconciler struct {
+	kc       client.Client
+	log      logr.Logger
+	cfg      Config
+	selector labels.Selector
+}
+
+// New returns a reconciler generating RuleGroupsNamespace resources from
+// PrometheusRule objects. The configuration must have been validated.
+func New(kc client.Client, log logr.Logger, cfg Config) (reconcile.Reconciler, error) {
+	selector, err := labels.Parse(cfg.Selector)
+	if err != nil {
+		return nil, err
+	}
+	return &reconciler{
+		kc:       kc,
+		log:      log,
+		cfg:      cfg,
+		selector: selector,
+	}, nil
+}
+
+// SetupWithManager sets up the PrometheusRule sync with the supplied
+// manager. The PrometheusRule CRD must be installed in the cluster.
+func SetupWithManager(mgr ctrlrt.Manager, cfg Config) error {
+	r, err := New(mgr.GetClient(), ctrlrt.Log.WithName("prometheus-rule-sync"), cfg)
+	if err != nil {
+		return err
+	}
+	c, err := controller.New("prometheus-rule-sync", mgr, controller.Options{
+		Reconciler: r,
+	})
+	if err != nil {
+		return err
+	}
+	rules := &unstructured.Unstructured{}
+	rules.SetGroupVersionKind(GroupVersionKind)
+	grouping := Grouping(cfg.Grouping)
+	if err := c.Watch(
+		&source.Kind{Type: rules},
+		handler.EnqueueRequestsFromMapFunc(prometheusRuleRequests(grouping)),
+	); err != nil {
+		return err
+	}
+	// Generated resources that are changed or deleted by hand are restored.
+	return c.Watch(
+		&source.Kind{Type: &svcapitypes.RuleGroupsNamespace{}},
+		handler.EnqueueRequestsFromMapFunc(generatedResourceRequests(grouping)),
+	)
+}
+
+// prometheusRuleRequests returns a function that maps a PrometheusRule to the
+// reconcile request of its RuleGroupsNamespace.
+func prometheusRuleRequests(grouping Grouping) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		key := types.NamespacedName{Namespace: obj.GetNamespace()}
+		if grouping == GroupingObject {
+			key.Name = obj.GetName()
+		}
+		return []reconcile.Request{{NamespacedName: key}}
+	}
+}
+
+// generatedResourceRequests returns a function that maps a generated
+// RuleGroupsNamespace to the reconcile request that generated it.
+func generatedResourceRequests(grouping Grouping) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		if obj.GetLabels()[LabelGeneratedBy] != generatedBy {
+			return nil
+		}
+		key := types.NamespacedName{Namespace: obj.GetNamespace()}
+		if grouping == GroupingObject {
+			key.Name = obj.GetName()
+		}
+		return []reconcile.Request{{NamespacedName: key}}
+	}
+}
+
+// Reconcile implements `controller-runtime.Reconciler`
+func (r *reconciler) Reconcile(ctx context.Context, req ctrlrt.Request) (ctrlrt.Result, error) {
+	log := r.log.WithValues("namespace", req.Namespace, "name", req.Name)
+	rules, err := r.selectedPrometheusRules(ctx, req)
+	if err != nil {
+		return ctrlrt.Result{}, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = namespaceResourceName
+	}
+	key := types.NamespacedName{Namespace: req.Namespace, Name: name}
+	if len(rules) == 0 {
+		return ctrlrt.Result{}, r.deleteGenerated(ctx, log, key)
+	}
+
+	configuration, err := renderRuleGroups(rules)
+	if err != nil {
+		log.Error(err, "unable to render the rule groups of the selected PrometheusRules")
+		// Retrying will not help until a PrometheusRule is changed.
+		return ctrlrt.Result{}, nil
+	}
+
+	ko := &svcapitypes.RuleGroupsNamespace{}
+	ko.Namespace, ko.Name = key.Namespace, key.Name
+	op, err := controllerutil.CreateOrUpdate(ctx, r.kc, ko, func() error {
+		if ko.ResourceVersion != "" && ko.Labels[LabelGeneratedBy] != generatedBy {
+			return fmt.Errorf(
+				"RuleGroupsNamespace %s/%s exists and was not generated from PrometheusRules",
+				ko.Namespace, ko.Name,
+			)
+		}
+		r.setGenerated(ko, req, rules, string(configuration))
+		return nil
+	})
+	if err != nil {
+		return ctrlrt.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("synced RuleGroupsNamespace from PrometheusRules", "operation", op)
+	}
+	return ctrlrt.Result{}, nil
+}
+
+// selectedPrometheusRules returns the selected PrometheusRule objects of the
+// supplied reconcile request, sorted by name.
+func (r *reconciler) selectedPrometheusRules(
+	ctx context.Context,
+	req ctrlrt.Request,
+) ([]*unstructured.Unstructured, error) {
+	if req.Name != "" {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(GroupVersionKind)
+		if err := r.kc.Get(ctx, req.NamespacedName, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if !obj.GetDeletionTimestamp().IsZero() || !r.selector.Matches(labels.Set(obj.GetLabels())) {
+			return nil, nil
+		}
+		return []*unstructured.Unstructured{obj}, nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(GroupVersionKind.GroupVersion().WithKind("PrometheusRuleList"))
+	if err := r.kc.List(
+		ctx, list,
+		client.InNamespace(req.Namespace),
+		client.MatchingLabelsSelector{Selector: r.selector},
+	); err != nil {
+		return nil, err
+	}
+	var rules []*unstructured.Unstructured
+	for i := range list.Items {
+		if list.Items[i].GetDeletionTimestamp().IsZero() {
+			rules = append(rules, &list.Items[i])
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].GetName() < rules[j].GetName()
+	})
+	return rules, nil
+}
+
+// setGenerated sets the labels, owner references and spec of the
+// RuleGroupsNamespace generated for the supplied PrometheusRules. Owner
+// references to other objects are kept.
+func (r *reconciler) setGenerated(
+	ko *svcapitypes.RuleGroupsNamespace,
+	req ctrlrt.Request,
+	rules []*unstructured.Unstructured,
+	configuration string,
+) {
+	if ko.Labels == nil {
+		ko.Labels = map[string]string{}
+	}
+	ko.Labels[LabelGeneratedBy] = generatedBy
+
+	ownerRefs := []metav1.OwnerReference{}
+	for _, ref := range ko.OwnerReferences {
+		if ref.APIVersion != GroupVersionKind.GroupVersion().String() || ref.Kind != GroupVersionKind.Kind {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	for _, obj := range rules {
+		ref := metav1.NewControllerRef(obj, GroupVersionKind)
+		if req.Name == "" {
+			// The resource has several owners, none of which is its
+			// controller.
+			ref.Controller = nil
+			ref.BlockOwnerDeletion = nil
+		}
+		ownerRefs = append(ownerRefs, *ref)
+	}
+	ko.OwnerReferences = ownerRefs
+
+	// The AMP rule groups namespace name is immutable, keep the one the
+	// resource was created with.
+	if ko.Spec.Name == nil {
+		name := req.Namespace
+		if req.Name != "" {
+			name += "-" + req.Name
+		}
+		ko.Spec.Name = &name
+	}
+	if r.cfg.WorkspaceID != "" {
+		workspaceID := r.cfg.WorkspaceID
+		ko.Spec.WorkspaceID = &workspaceID
+		ko.Spec.WorkspaceRef = nil
+	} else {
+		namespace, name := r.cfg.workspaceRef()
+		ref := &svcapitypes.ResourceReference{Name: &name}
+		if namespace != "" {
+			ref.Namespace = &namespace
+		}
+		ko.Spec.WorkspaceID = nil
+		ko.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{From: ref}
+	}
+	ko.Spec.Configuration = &configuration
+	ko.Spec.Groups = nil
+	ko.Spec.ConfigurationFrom = nil
+}
+
+// deleteGenerated deletes the generated RuleGroupsNamespace with the supplied
+// key, if it exists.
+func (r *reconciler) deleteGenerated(
+	ctx context.Context,
+	log logr.Logger,
+	key types.NamespacedName,
+) error {
+	ko := &svcapitypes.RuleGroupsNamespace{}
+	if err := r.kc.Get(ctx, key, ko); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ko.Labels[LabelGeneratedBy] != generatedBy || !ko.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if err := r.kc.Delete(ctx, ko); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	log.Info("deleted RuleGroupsNamespace of unselected PrometheusRules")
+	return nil
+}
@@ -0,0 +1,245 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package prometheusrule
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+var syncLabels = map[string]string{"amp": "true"}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = svcapitypes.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(GroupVersionKind, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(
+		GroupVersionKind.GroupVersion().WithKind("PrometheusRuleList"),
+		&unstructured.UnstructuredList{},
+	)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newReconciler(t *testing.T, kc client.Client, cfg Config) reconcile.Reconciler {
+	if cfg.Grouping == "" {
+		cfg.Grouping = string(GroupingObject)
+	}
+	cfg.Enabled = true
+	cfg.Selector = "amp=true"
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(kc, ctrlrt.Log, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func reconcileRequest(t *testing.T, r reconcile.Reconciler, namespace, name string) {
+	req := ctrlrt.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+	if _, err := r.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("Reconcile() unexpected error = %v", err)
+	}
+}
+
+func getRuleGroupsNamespace(t *testing.T, kc client.Client, namespace, name string) *svcapitypes.RuleGroupsNamespace {
+	ko := &svcapitypes.RuleGroupsNamespace{}
+	err := kc.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, ko)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ko
+}
+
+func ownerNames(ko *svcapitypes.RuleGroupsNamespace) []string {
+	var names []string
+	for _, ref := range ko.OwnerReferences {
+		names = append(names, ref.Kind+"/"+ref.Name)
+	}
+	return names
+}
+
+func TestReconcile_object(t *testing.T) {
+	kc := newFakeClient(
+		newPrometheusRule("default", "api", syncLabels, "api"),
+		newPrometheusRule("default", "unselected", nil, "other"),
+	)
+	r := newReconciler(t, kc, Config{WorkspaceID: "ws-1"})
+
+	reconcileRequest(t, r, "default", "api")
+	ko := getRuleGroupsNamespace(t, kc, "default", "api")
+	if ko == nil {
+		t.Fatalf("RuleGroupsNamespace default/api was not generated")
+	}
+	if got := aws.StringValue(ko.Spec.Name); got != "default-api" {
+		t.Errorf("Spec.Name = %s, want default-api", got)
+	}
+	if got := aws.StringValue(ko.Spec.WorkspaceID); got != "ws-1" {
+		t.Errorf("Spec.WorkspaceID = %s, want ws-1", got)
+	}
+	if !strings.Contains(aws.StringValue(ko.Spec.Configuration), "- name: api") {
+		t.Errorf("Spec.Configuration = %s", aws.StringValue(ko.Spec.Configuration))
+	}
+	if len(ko.OwnerReferences) != 1 || ko.OwnerReferences[0].Controller == nil || !*ko.OwnerReferences[0].Controller {
+		t.Errorf("OwnerReferences = %v, want a controller reference", ko.OwnerReferences)
+	}
+
+	reconcileRequest(t, r, "default", "unselected")
+	if getRuleGroupsNamespace(t, kc, "default", "unselected") != nil {
+		t.Errorf("RuleGroupsNamespace generated for an unselected PrometheusRule")
+	}
+
+	// Unselecting the PrometheusRule deletes the generated resource.
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersionKind)
+	if err := kc.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "api"}, obj); err != nil {
+		t.Fatal(err)
+	}
+	obj.SetLabels(nil)
+	if err := kc.Update(context.TODO(), obj); err != nil {
+		t.Fatal(err)
+	}
+	reconcileRequest(t, r, "default", "api")
+	if getRuleGroupsNamespace(t, kc, "default", "api") != nil {
+		t.Errorf("RuleGroupsNamespace of an unselected PrometheusRule was not deleted")
+	}
+}
+
+func TestReconcile_namespace(t *testing.T) {
+	kc := newFakeClient(
+		newPrometheusRule("default", "b", syncLabels, "db"),
+		newPrometheusRule("default", "a", syncLabels, "api"),
+		newPrometheusRule("default", "c", nil, "other"),
+		newPrometheusRule("other", "d", syncLabels, "web"),
+	)
+	r := newReconciler(t, kc, Config{Grouping: "namespace", WorkspaceRef: "monitoring/amp"})
+
+	reconcileRequest(t, r, "default", "")
+	ko := getRuleGroupsNamespace(t, kc, "default", namespaceResourceName)
+	if ko == nil {
+		t.Fatalf("RuleGroupsNamespace was not generated")
+	}
+	if got := aws.StringValue(ko.Spec.Name); got != "default" {
+		t.Errorf("Spec.Name = %s, want default", got)
+	}
+	ref := ko.Spec.WorkspaceRef
+	if ref == nil || aws.StringValue(ref.From.Namespace) != "monitoring" || aws.StringValue(ref.From.Name) != "amp" {
+		t.Errorf("Spec.WorkspaceRef = %v, want monitoring/amp", ref)
+	}
+	configuration := aws.StringValue(ko.Spec.Configuration)
+	api, db := strings.Index(configuration, "name: api"), strings.Index(configuration, "name: db")
+	if api < 0 || db < api || strings.Contains(configuration, "name: other") || strings.Contains(configuration, "name: web") {
+		t.Errorf("Spec.Configuration = %s", configuration)
+	}
+	if got, want := ownerNames(ko), []string{"PrometheusRule/a", "PrometheusRule/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OwnerReferences = %v, want %v", got, want)
+	}
+	for _, ref := range ko.OwnerReferences {
+		if ref.Controller != nil {
+			t.Errorf("OwnerReference %s is a controller reference", ref.Name)
+		}
+	}
+}
+
+func TestReconcile_keepsOtherOwnerReferences(t *testing.T) {
+	other := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "uid-owner"}
+	existing := &svcapitypes.RuleGroupsNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "api",
+			Labels:          map[string]string{LabelGeneratedBy: generatedBy},
+			OwnerReferences: []metav1.OwnerReference{other},
+		},
+		Spec: svcapitypes.RuleGroupsNamespaceSpec{Name: aws.String("api-rules")},
+	}
+	kc := newFakeClient(existing, newPrometheusRule("default", "api", syncLabels, "api"))
+	r := newReconciler(t, kc, Config{WorkspaceID: "ws-1"})
+
+	reconcileRequest(t, r, "default", "api")
+	ko := getRuleGroupsNamespace(t, kc, "default", "api")
+	if got, want := ownerNames(ko), []string{"ConfigMap/owner", "PrometheusRule/api"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OwnerReferences = %v, want %v", got, want)
+	}
+	if got := aws.StringValue(ko.Spec.Name); got != "api-rules" {
+		t.Errorf("Spec.Name = %s, want the immutable api-rules", got)
+	}
+}
+
+func TestReconcile_notGenerated(t *testing.T) {
+	existing := &svcapitypes.RuleGroupsNamespace{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"},
+		Spec:       svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups: []\n")},
+	}
+	kc := newFakeClient(existing, newPrometheusRule("default", "api", syncLabels, "api"))
+	r := newReconciler(t, kc, Config{WorkspaceID: "ws-1"})
+
+	req := ctrlrt.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "api"}}
+	if _, err := r.Reconcile(context.TODO(), req); err == nil {
+		t.Errorf("Reconcile() expected an error")
+	}
+	ko := getRuleGroupsNamespace(t, kc, "default", "api")
+	if got := aws.StringValue(ko.Spec.Configuration); got != "groups: []\n" {
+		t.Errorf("Spec.Configuration = %s, want it unchanged", got)
+	}
+
+	// Resources that were not generated are not deleted either.
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(GroupVersionKind)
+	obj.SetNamespace("default")
+	obj.SetName("api")
+	if err := kc.Delete(context.TODO(), obj); err != nil {
+		t.Fatal(err)
+	}
+	reconcileRequest(t, r, "default", "api")
+	if getRuleGroupsNamespace(t, kc, "default", "api") == nil {
+		t.Errorf("RuleGroupsNamespace that was not generated was deleted")
+	}
+}
+
+func Test_generatedResourceRequests(t *testing.T) {
+	generated := &svcapitypes.RuleGroupsNamespace{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      namespaceResourceName,
+		Labels:    map[string]string{LabelGeneratedBy: generatedBy},
+	}}
+	got := generatedResourceRequests(GroupingNamespace)(generated)
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("generatedResourceRequests() = %v, want %v", got, want)
+	}
+
+	generated.Labels = nil
+	if got := generatedResourceRequests(GroupingNamespace)(generated); got != nil {
+		t.Errorf("generatedResourceRequests() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package prometheusrule
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// prometheusRuleSpec is the subset of the monitoring.coreos.com/v1
+// PrometheusRule spec that AMP supports. Fields specific to Thanos, such as
+// partial_response_strategy, are dropped.
+type prometheusRuleSpec struct {
+	Groups []struct {
+		Name        string `json:"name"`
+		Interval    string `json:"interval,omitempty"`
+		QueryOffset string `json:"query_offset,omitempty"`
+		Limit       *int   `json:"limit,omitempty"`
+		Rules       []struct {
+			Record        string             `json:"record,omitempty"`
+			Alert         string             `json:"alert,omitempty"`
+			Expr          intstr.IntOrString `json:"expr"`
+			For           string             `json:"for,omitempty"`
+			KeepFiringFor string             `json:"keep_firing_for,omitempty"`
+			Labels        map[string]string  `json:"labels,omitempty"`
+			Annotations   map[string]string  `json:"annotations,omitempty"`
+		} `json:"rules"`
+	} `json:"groups"`
+}
+
+// ruleGroupsFile is the AMP (Prometheus rules file) YAML representation of a
+// list of rule groups.
+type ruleGroupsFile struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name        string `yaml:"name"`
+	Interval    string `yaml:"interval,omitempty"`
+	QueryOffset string `yaml:"query_offset,omitempty"`
+	Limit       *int   `yaml:"limit,omitempty"`
+	Rules       []rule `yaml:"rules"`
+}
+
+type rule struct {
+	Record        string            `yaml:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           string            `yaml:"for,omitempty"`
+	KeepFiringFor string            `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+}
+
+// renderRuleGroups renders the rule groups of the supplied PrometheusRule
+// objects, in order, to the AMP rule groups YAML format. Prometheus requires
+// group names to be unique within a rules file, so an error is returned if
+// two of the objects define a group with the same name.
+func renderRuleGroups(objs []*unstructured.Unstructured) ([]byte, error) {
+	file := ruleGroupsFile{Groups: []ruleGroup{}}
+	definedBy := map[string]string{}
+	for _, obj := range objs {
+		specObj, _, err := unstructured.NestedMap(obj.Object, "spec")
+		if err != nil {
+			return nil, fmt.Errorf("PrometheusRule %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+		}
+		var spec prometheusRuleSpec
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specObj, &spec); err != nil {
+			return nil, fmt.Errorf("PrometheusRule %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+		}
+		for _, g := range spec.Groups {
+			if other, ok := definedBy[g.Name]; ok {
+				return nil, fmt.Errorf(
+					"rule group %q is defined by both PrometheusRule %s and %s",
+					g.Name, other, obj.GetName(),
+				)
+			}
+			definedBy[g.Name] = obj.GetName()
+			group := ruleGroup{
+				Name:        g.Name,
+				Interval:    g.Interval,
+				QueryOffset: g.QueryOffset,
+				Limit:       g.Limit,
+				Rules:       make([]rule, 0, len(g.Rules)),
+			}
+			for _, r := range g.Rules {
+				group.Rules = append(group.Rules, rule{
+					Record:        r.Record,
+					Alert:         r.Alert,
+					Expr:          r.Expr.String(),
+					For:           r.For,
+					KeepFiringFor: r.KeepFiringFor,
+					Labels:        r.Labels,
+					Annotations:   r.Annotations,
+				})
+			}
+			file.Groups = append(file.Groups, group)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&file); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package prometheusrule
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newPrometheusRule returns a PrometheusRule with the supplied labels and
+// groups, each group holding a single alerting rule.
+func newPrometheusRule(namespace, name string, labels map[string]string, groups ...string) *unstructured.Unstructured {
+	specGroups := []interface{}{}
+	for _, g := range groups {
+		specGroups = append(specGroups, map[string]interface{}{
+			"name":                      g,
+			"partial_response_strategy": "warn",
+			"rules": []interface{}{
+				map[string]interface{}{
+					"alert": g + "Down",
+					"expr":  "up == 0",
+					"for":   "5m",
+					"labels": map[string]interface{}{
+						"severity": "critical",
+					},
+				},
+			},
+		})
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"groups": specGroups},
+	}}
+	obj.SetGroupVersionKind(GroupVersionKind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetUID(types.UID("uid-" + name))
+	obj.SetLabels(labels)
+	return obj
+}
+
+func Test_renderRuleGroups(t *testing.T) {
+	got, err := renderRuleGroups([]*unstructured.Unstructured{
+		newPrometheusRule("default", "a", nil, "api"),
+		newPrometheusRule("default", "b", nil, "db"),
+	})
+	if err != nil {
+		t.Fatalf("renderRuleGroups() unexpected error = %v", err)
+	}
+	want := `groups:
+  - name: api
+    rules:
+      - alert: apiDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: critical
+  - name: db
+    rules:
+      - alert: dbDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: critical
+`
+	if string(got) != want {
+		t.Errorf("renderRuleGroups() = %s, want %s", got, want)
+	}
+}
+
+func Test_renderRuleGroups_empty(t *testing.T) {
+	got, err := renderRuleGroups([]*unstructured.Unstructured{newPrometheusRule("default", "a", nil)})
+	if err != nil {
+		t.Fatalf("renderRuleGroups() unexpected error = %v", err)
+	}
+	if want := "groups: []\n"; string(got) != want {
+		t.Errorf("renderRuleGroups() = %q, want %q", got, want)
+	}
+}
+
+func Test_renderRuleGroups_duplicateGroup(t *testing.T) {
+	_, err := renderRuleGroups([]*unstructured.Unstructured{
+		newPrometheusRule("default", "a", nil, "api"),
+		newPrometheusRule("default", "b", nil, "api"),
+	})
+	if err == nil || !strings.Contains(err.Error(), `rule group "api" is defined by both PrometheusRule a and b`) {
+		t.Errorf("renderRuleGroups() error = %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package promql validates PromQL expressions without evaluating them, with
+// the parser of the Prometheus query engine, and reports errors with their
+// line and column within the expression.
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// ParseError is a syntax or type error in a PromQL expression.
+type ParseError struct {
+	// Line and Column locate the error within the expression. Both start at
+	// 1, and Column counts characters rather than bytes.
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: parse error: %s", e.Line, e.Column, e.Msg)
+}
+
+// Validate parses the supplied PromQL expression and returns a *ParseError if
+// it is not valid.
+func Validate(expr string) error {
+	_, err := parser.ParseExpr(expr)
+	if err == nil {
+		return nil
+	}
+	var errs parser.ParseErrors
+	if !errors.As(err, &errs) || len(errs) == 0 {
+		return err
+	}
+	// Like the error message of the parser, only report the first error.
+	line, column := position(expr, int(errs[0].PositionRange.Start))
+	return &ParseError{Line: line, Column: column, Msg: errs[0].Err.Error()}
+}
+
+// position returns the line and the character column of the supplied byte
+// offset within the supplied expression.
+func position(expr string, offset int) (line, column int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(expr) {
+		offset = len(expr)
+	}
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if expr[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return line, utf8.RuneCountInString(expr[lineStart:offset]) + 1
+}
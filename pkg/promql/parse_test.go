@@ -0,0 +1,119 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package promql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		`up`,
+		`1`,
+		`-1.5e3`,
+		`0x1F`,
+		`Inf`,
+		`"string"`,
+		`up{job="prometheus"}`,
+		`{__name__=~"up|down", job!=""}`,
+		`http_requests_total{job='api', code=~"5.."}`,
+		`rate(http_requests_total[5m])`,
+		`rate(http_requests_total[1h30m] offset 1d)`,
+		`avg_over_time(rate(foo[5m])[30m:1m])`,
+		`max_over_time(deriv(rate(foo[1m])[5m:])[10m:])`,
+		`foo @ 1609746000`,
+		`foo offset -5m @ start()`,
+		`sum by (job) (rate(http_requests_total[5m]))`,
+		`sum(rate(http_requests_total[5m])) without (instance)`,
+		`topk(5, sum by (job, instance) (up))`,
+		`count_values("version", build_version)`,
+		`histogram_quantile(0.9, sum by (le) (rate(request_duration_seconds_bucket[5m])))`,
+		`a + b * c ^ d ^ e`,
+		`-2 ^ 2`,
+		`1 == bool 2`,
+		`foo > 0.5`,
+		`foo / on(job) group_left(version) bar`,
+		`foo and ignoring(instance) bar or baz unless qux`,
+		`metric:recording_rule > 0.9`,
+		`label_replace(up, "foo", "$1", "job", "(.*)")`,
+		`label_join(up, "foo", ",", "job", "instance")`,
+		`round(foo)`,
+		`round(foo, 5)`,
+		`day_of_week()`,
+		`time() - process_start_time_seconds`,
+		`absent(up{job="x"} == 1)`,
+		"avg(\n  rate(foo[5m]) # comment\n)",
+		`SUM BY (job) (up)`,
+	}
+	for _, expr := range valid {
+		t.Run(expr, func(t *testing.T) {
+			if err := Validate(expr); err != nil {
+				t.Errorf("Validate(%q) unexpected error = %v", expr, err)
+			}
+		})
+	}
+}
+
+func TestValidate_invalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		line int
+		col  int
+		msg  string
+	}{
+		{``, 1, 1, "no expression found in input"},
+		{`sum(`, 1, 5, "unclosed left parenthesis"},
+		{`rate(foo)`, 1, 6, `expected type range vector in call to function "rate", got instant vector`},
+		{`unknown_fn(foo)`, 1, 1, `unknown function with name "unknown_fn"`},
+		{`rate(foo[5m], 1)`, 1, 1, `expected 1 argument(s) in call to "rate", got 2`},
+		{`(foo)[5m]`, 1, 6, "ranges only allowed for vector selectors"},
+		{`foo[5]`, 1, 5, "missing unit character in duration"},
+		{`foo{job="x"`, 1, 12, "unexpected end of input inside braces"},
+		{`foo{job~"x"}`, 1, 8, "unexpected character inside braces: '~'"},
+		{`{job=""}`, 1, 1, "vector selector must contain at least one non-empty matcher"},
+		{`foo{job=~"("}`, 1, 5, "error parsing regexp"},
+		{`1 > 2`, 1, 3, "comparisons between scalars must use BOOL modifier"},
+		{`foo + bool bar`, 1, 5, "bool modifier can only be used on comparison operators"},
+		{`1 and foo`, 1, 1, `set operator "and" not allowed in binary scalar expression`},
+		{`foo and on(job) group_left bar`, 1, 1, `no grouping allowed for "and" operation`},
+		{`topk(foo)`, 1, 1, "wrong number of arguments for aggregate expression provided, expected 2, got 1"},
+		{`sum(1)`, 1, 5, "expected type instant vector in aggregation expression, got scalar"},
+		{`sum(foo) by (job) without (instance)`, 1, 19, "unexpected <without>"},
+		{`rate(foo[5m]) offset 5m`, 1, 1, "offset modifier must be preceded by"},
+		{`foo offset 5m offset 1m`, 1, 1, "offset may not be set multiple times"},
+		{`foo offset 1m [5m]`, 1, 15, "no offset modifiers allowed before range"},
+		{`foo[5m:1m][1m:]`, 1, 1, "subquery is only allowed on instant vector"},
+		{`foo bar`, 1, 5, `unexpected identifier "bar"`},
+		{`"unterminated`, 1, 1, "unterminated quoted string"},
+		{`foo[5min]`, 1, 5, "bad duration syntax"},
+		{"sum(\n  rate(foo[5m]\n)", 3, 2, "unclosed left parenthesis"},
+		{"sum(\n  rate(fóó[5m]))", 2, 9, "unexpected character: 'ó'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			err := Validate(tt.expr)
+			perr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("Validate(%q) error = %v, want *ParseError", tt.expr, err)
+			}
+			if perr.Line != tt.line || perr.Column != tt.col {
+				t.Errorf("Validate(%q) error at %d:%d, want %d:%d (%v)", tt.expr, perr.Line, perr.Column, tt.line, tt.col, err)
+			}
+			if !strings.Contains(perr.Msg, tt.msg) {
+				t.Errorf("Validate(%q) error = %q, want it to contain %q", tt.expr, perr.Msg, tt.msg)
+			}
+		})
+	}
+}
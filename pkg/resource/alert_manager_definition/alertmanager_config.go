@@ -0,0 +1,649 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=alertmanagerconfigs,verbs=get;list;watch
+
+var (
+	// alertmanagerConfigGVK is the GroupVersionKind of the prometheus-operator
+	// AlertmanagerConfig objects.
+	alertmanagerConfigGVK = schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1alpha1",
+		Kind:    "AlertmanagerConfig",
+	}
+
+	errNoAlertmanagerConfig = errors.New("configuration has no alertmanager_config")
+	errNoRootRoute          = errors.New("alertmanager_config has no route")
+)
+
+// alertmanagerConfigSpec is the subset of the monitoring.coreos.com/v1alpha1
+// AlertmanagerConfig spec that is merged into alert manager definitions.
+// Receivers are decoded generically to report the integrations AMP does not
+// support.
+type alertmanagerConfigSpec struct {
+	Route             *alertmanagerConfigRoute        `json:"route,omitempty"`
+	Receivers         []map[string]interface{}        `json:"receivers,omitempty"`
+	InhibitRules      []alertmanagerConfigInhibitRule `json:"inhibitRules,omitempty"`
+	MuteTimeIntervals []interface{}                   `json:"muteTimeIntervals,omitempty"`
+}
+
+type alertmanagerConfigRoute struct {
+	Receiver            string                      `json:"receiver,omitempty"`
+	GroupBy             []string                    `json:"groupBy,omitempty"`
+	GroupWait           string                      `json:"groupWait,omitempty"`
+	GroupInterval       string                      `json:"groupInterval,omitempty"`
+	RepeatInterval      string                      `json:"repeatInterval,omitempty"`
+	Matchers            []alertmanagerConfigMatcher `json:"matchers,omitempty"`
+	Continue            bool                        `json:"continue,omitempty"`
+	Routes              []alertmanagerConfigRoute   `json:"routes,omitempty"`
+	MuteTimeIntervals   []string                    `json:"muteTimeIntervals,omitempty"`
+	ActiveTimeIntervals []string                    `json:"activeTimeIntervals,omitempty"`
+}
+
+type alertmanagerConfigMatcher struct {
+	Name      string `json:"name"`
+	Value     string `json:"value,omitempty"`
+	MatchType string `json:"matchType,omitempty"`
+	Regex     bool   `json:"regex,omitempty"`
+}
+
+type alertmanagerConfigInhibitRule struct {
+	SourceMatch []alertmanagerConfigMatcher `json:"sourceMatch,omitempty"`
+	TargetMatch []alertmanagerConfigMatcher `json:"targetMatch,omitempty"`
+	Equal       []string                    `json:"equal,omitempty"`
+}
+
+type alertmanagerConfigSNSConfig struct {
+	SendResolved *bool  `json:"sendResolved,omitempty"`
+	APIURL       string `json:"apiURL,omitempty"`
+	Sigv4        *struct {
+		Region    string                 `json:"region,omitempty"`
+		AccessKey map[string]interface{} `json:"accessKey,omitempty"`
+		SecretKey map[string]interface{} `json:"secretKey,omitempty"`
+		Profile   string                 `json:"profile,omitempty"`
+		RoleArn   string                 `json:"roleArn,omitempty"`
+	} `json:"sigv4,omitempty"`
+	TopicARN    string                 `json:"topicARN,omitempty"`
+	Subject     string                 `json:"subject,omitempty"`
+	PhoneNumber string                 `json:"phoneNumber,omitempty"`
+	TargetARN   string                 `json:"targetARN,omitempty"`
+	Message     string                 `json:"message,omitempty"`
+	Attributes  map[string]string      `json:"attributes,omitempty"`
+	HTTPConfig  map[string]interface{} `json:"httpConfig,omitempty"`
+}
+
+// route, receiver and inhibitRule are the Alertmanager configuration
+// representations of the converted AlertmanagerConfig objects.
+type route struct {
+	Receiver       string   `yaml:"receiver,omitempty"`
+	GroupBy        []string `yaml:"group_by,omitempty"`
+	Matchers       []string `yaml:"matchers,omitempty"`
+	Continue       bool     `yaml:"continue,omitempty"`
+	GroupWait      string   `yaml:"group_wait,omitempty"`
+	GroupInterval  string   `yaml:"group_interval,omitempty"`
+	RepeatInterval string   `yaml:"repeat_interval,omitempty"`
+	Routes         []*route `yaml:"routes,omitempty"`
+}
+
+type receiver struct {
+	Name       string       `yaml:"name"`
+	SNSConfigs []*snsConfig `yaml:"sns_configs,omitempty"`
+}
+
+type snsConfig struct {
+	SendResolved *bool             `yaml:"send_resolved,omitempty"`
+	APIURL       string            `yaml:"api_url,omitempty"`
+	Sigv4        *sigv4Config      `yaml:"sigv4,omitempty"`
+	TopicARN     string            `yaml:"topic_arn,omitempty"`
+	PhoneNumber  string            `yaml:"phone_number,omitempty"`
+	TargetARN    string            `yaml:"target_arn,omitempty"`
+	Subject      string            `yaml:"subject,omitempty"`
+	Message      string            `yaml:"message,omitempty"`
+	Attributes   map[string]string `yaml:"attributes,omitempty"`
+}
+
+type sigv4Config struct {
+	Region  string `yaml:"region,omitempty"`
+	Profile string `yaml:"profile,omitempty"`
+	RoleARN string `yaml:"role_arn,omitempty"`
+}
+
+type inhibitRule struct {
+	SourceMatchers []string `yaml:"source_matchers,omitempty"`
+	TargetMatchers []string `yaml:"target_matchers,omitempty"`
+	Equal          []string `yaml:"equal,omitempty"`
+}
+
+// alertmanagerConfigFragment is the part of an Alertmanager configuration
+// generated from an AlertmanagerConfig object.
+type alertmanagerConfigFragment struct {
+	route        *route
+	receivers    []*receiver
+	inhibitRules []*inhibitRule
+}
+
+// resolveAlertmanagerConfigs merges the AlertmanagerConfig objects selected
+// by Spec.AlertmanagerConfigSelector into Spec.Configuration. Like other
+// resolved references, the merged configuration is not persisted to the
+// Kubernetes API, only the merged and conflicting AlertmanagerConfig objects
+// are recorded in the status of the resource. The status is left unchanged
+// if the objects cannot be merged. The objects are listed through the cached
+// client kc, which is backed by the informers of the watches set up by
+// setupAlertmanagerConfigWatch.
+func resolveAlertmanagerConfigs(
+	ctx context.Context,
+	kc client.Reader,
+	namespace string,
+	ko *svcapitypes.AlertManagerDefinition,
+) error {
+	if ko.Spec.AlertmanagerConfigSelector == nil || ko.Spec.Configuration == nil {
+		ko.Status.ImportedAlertmanagerConfigs = nil
+		ko.Status.AlertmanagerConfigConflicts = nil
+		return nil
+	}
+	if kc == nil {
+		return errors.New("no cached client to list the AlertmanagerConfig objects with")
+	}
+	objs, err := selectedAlertmanagerConfigs(ctx, kc, namespace, ko)
+	if err != nil {
+		return err
+	}
+	var (
+		imported  []*string
+		conflicts []*svcapitypes.AlertmanagerConfigConflict
+		fragments []*alertmanagerConfigFragment
+	)
+	if len(objs) > 0 {
+		outer, config, err := parseAlertManagerDefinition(*ko.Spec.Configuration)
+		if err != nil {
+			return err
+		}
+		baseReceivers := receiverNames(config)
+		for i := range objs {
+			obj := &objs[i]
+			fragment, err := convertAlertmanagerConfig(obj)
+			if err == nil {
+				for _, r := range fragment.receivers {
+					if baseReceivers[r.Name] {
+						err = fmt.Errorf("receiver %q is already defined by the configuration", r.Name)
+						break
+					}
+				}
+			}
+			if err != nil {
+				conflicts = append(conflicts, &svcapitypes.AlertmanagerConfigConflict{
+					Namespace: stringPtr(obj.GetNamespace()),
+					Name:      stringPtr(obj.GetName()),
+					Message:   stringPtr(err.Error()),
+				})
+				continue
+			}
+			fragments = append(fragments, fragment)
+			imported = append(imported, stringPtr(obj.GetNamespace()+"/"+obj.GetName()))
+		}
+		if len(fragments) > 0 {
+			configuration, err := mergeAlertmanagerConfigs(outer, config, fragments)
+			if err != nil {
+				return err
+			}
+			ko.Spec.Configuration = &configuration
+		}
+	}
+	ko.Status.ImportedAlertmanagerConfigs = imported
+	ko.Status.AlertmanagerConfigConflicts = conflicts
+	return nil
+}
+
+// receiverNames returns the names of the receivers of the supplied
+// Alertmanager configuration.
+func receiverNames(config map[string]interface{}) map[string]bool {
+	names := map[string]bool{}
+	receivers, _ := config["receivers"].([]interface{})
+	for _, r := range receivers {
+		if r, ok := r.(map[string]interface{}); ok {
+			if name, ok := r["name"].(string); ok {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
+
+// selectedAlertmanagerConfigs returns the AlertmanagerConfig objects selected
+// by the supplied resource, sorted by namespace and name. A terminal error is
+// returned if the AlertmanagerConfig CRD is not installed.
+func selectedAlertmanagerConfigs(
+	ctx context.Context,
+	kc client.Reader,
+	namespace string,
+	ko *svcapitypes.AlertManagerDefinition,
+) ([]unstructured.Unstructured, error) {
+	selector, err := metav1.LabelSelectorAsSelector(ko.Spec.AlertmanagerConfigSelector)
+	if err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(alertmanagerConfigGVK.GroupVersion().WithKind("AlertmanagerConfigList"))
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+	var namespaces map[string]bool
+	if ko.Spec.AlertmanagerConfigNamespaceSelector == nil {
+		opts = append(opts, client.InNamespace(namespace))
+	} else {
+		namespaces, err = selectedNamespaces(ctx, kc, ko.Spec.AlertmanagerConfigNamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := kc.List(ctx, list, opts...); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, ackerr.NewTerminalError(fmt.Errorf("AlertmanagerConfig CRD not installed: %w", err))
+		}
+		return nil, err
+	}
+
+	objs := []unstructured.Unstructured{}
+	for _, obj := range list.Items {
+		if namespaces == nil || namespaces[obj.GetNamespace()] {
+			objs = append(objs, obj)
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].GetNamespace() != objs[j].GetNamespace() {
+			return objs[i].GetNamespace() < objs[j].GetNamespace()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+	return objs, nil
+}
+
+// selectedNamespaces returns the names of the namespaces selected by the
+// supplied label selector.
+func selectedNamespaces(
+	ctx context.Context,
+	kc client.Reader,
+	labelSelector *metav1.LabelSelector,
+) (map[string]bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+	list := &corev1.NamespaceList{}
+	if err := kc.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	namespaces := map[string]bool{}
+	for _, ns := range list.Items {
+		namespaces[ns.Name] = true
+	}
+	return namespaces, nil
+}
+
+// convertAlertmanagerConfig converts an AlertmanagerConfig object to an
+// Alertmanager configuration fragment the way prometheus-operator does:
+// receiver names are prefixed with the namespace and name of the object, the
+// top-level route and the inhibit rules only match alerts with a namespace
+// label equal to the namespace of the object, and the top-level route always
+// continues so that other AlertmanagerConfig objects can match the alert.
+func convertAlertmanagerConfig(obj *unstructured.Unstructured) (*alertmanagerConfigFragment, error) {
+	specObj, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+	var spec alertmanagerConfigSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specObj, &spec); err != nil {
+		return nil, err
+	}
+	if len(spec.MuteTimeIntervals) > 0 {
+		return nil, errors.New("mute time intervals are not supported")
+	}
+
+	namespace := obj.GetNamespace()
+	prefix := namespace + "/" + obj.GetName() + "/"
+	namespaceMatcher := fmt.Sprintf("namespace=%q", namespace)
+	fragment := &alertmanagerConfigFragment{}
+	names := map[string]bool{}
+	for _, r := range spec.Receivers {
+		converted, err := convertReceiver(prefix, r)
+		if err != nil {
+			return nil, err
+		}
+		if names[converted.Name] {
+			return nil, fmt.Errorf("receiver %q is defined more than once", r["name"])
+		}
+		names[converted.Name] = true
+		fragment.receivers = append(fragment.receivers, converted)
+	}
+	if spec.Route != nil {
+		converted, err := convertRoute(prefix, names, spec.Route)
+		if err != nil {
+			return nil, err
+		}
+		converted.Matchers = append([]string{namespaceMatcher}, converted.Matchers...)
+		converted.Continue = true
+		fragment.route = converted
+	}
+	for _, r := range spec.InhibitRules {
+		fragment.inhibitRules = append(fragment.inhibitRules, &inhibitRule{
+			SourceMatchers: append([]string{namespaceMatcher}, convertMatchers(r.SourceMatch)...),
+			TargetMatchers: append([]string{namespaceMatcher}, convertMatchers(r.TargetMatch)...),
+			Equal:          r.Equal,
+		})
+	}
+	return fragment, nil
+}
+
+// convertReceiver converts an AlertmanagerConfig receiver. AMP only supports
+// SNS receivers, and signs the SNS requests with its own credentials.
+func convertReceiver(prefix string, r map[string]interface{}) (*receiver, error) {
+	name, _ := r["name"].(string)
+	if name == "" {
+		return nil, errors.New("receiver name must not be empty")
+	}
+	for key, value := range r {
+		if key == "name" || key == "snsConfigs" {
+			continue
+		}
+		if configs, ok := value.([]interface{}); ok && len(configs) == 0 {
+			continue
+		}
+		return nil, fmt.Errorf("receiver %q: %s are not supported, AMP only supports SNS receivers", name, key)
+	}
+	var configs struct {
+		SNSConfigs []alertmanagerConfigSNSConfig `json:"snsConfigs,omitempty"`
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(r, &configs); err != nil {
+		return nil, fmt.Errorf("receiver %q: %v", name, err)
+	}
+	converted := &receiver{Name: prefix + name}
+	for _, c := range configs.SNSConfigs {
+		if c.HTTPConfig != nil {
+			return nil, fmt.Errorf("receiver %q: SNS HTTP client configurations are not supported", name)
+		}
+		sns := &snsConfig{
+			SendResolved: c.SendResolved,
+			APIURL:       c.APIURL,
+			TopicARN:     c.TopicARN,
+			PhoneNumber:  c.PhoneNumber,
+			TargetARN:    c.TargetARN,
+			Subject:      c.Subject,
+			Message:      c.Message,
+			Attributes:   c.Attributes,
+		}
+		if c.Sigv4 != nil {
+			if c.Sigv4.AccessKey != nil || c.Sigv4.SecretKey != nil {
+				return nil, fmt.Errorf("receiver %q: SNS access and secret keys are not supported", name)
+			}
+			sns.Sigv4 = &sigv4Config{
+				Region:  c.Sigv4.Region,
+				Profile: c.Sigv4.Profile,
+				RoleARN: c.Sigv4.RoleArn,
+			}
+		}
+		converted.SNSConfigs = append(converted.SNSConfigs, sns)
+	}
+	return converted, nil
+}
+
+// convertRoute converts an AlertmanagerConfig route and its child routes.
+// The receivers of the routes must be defined by the same object.
+func convertRoute(
+	prefix string,
+	receivers map[string]bool,
+	r *alertmanagerConfigRoute,
+) (*route, error) {
+	if len(r.MuteTimeIntervals) > 0 || len(r.ActiveTimeIntervals) > 0 {
+		return nil, errors.New("route time intervals are not supported")
+	}
+	converted := &route{
+		GroupBy:        r.GroupBy,
+		Matchers:       convertMatchers(r.Matchers),
+		Continue:       r.Continue,
+		GroupWait:      r.GroupWait,
+		GroupInterval:  r.GroupInterval,
+		RepeatInterval: r.RepeatInterval,
+	}
+	if r.Receiver != "" {
+		converted.Receiver = prefix + r.Receiver
+		if !receivers[converted.Receiver] {
+			return nil, fmt.Errorf("route references undefined receiver %q", r.Receiver)
+		}
+	}
+	for i := range r.Routes {
+		child, err := convertRoute(prefix, receivers, &r.Routes[i])
+		if err != nil {
+			return nil, err
+		}
+		converted.Routes = append(converted.Routes, child)
+	}
+	return converted, nil
+}
+
+// convertMatchers converts AlertmanagerConfig matchers to the Alertmanager
+// matcher syntax.
+func convertMatchers(matchers []alertmanagerConfigMatcher) []string {
+	var converted []string
+	for _, m := range matchers {
+		matchType := m.MatchType
+		if matchType == "" {
+			matchType = "="
+			if m.Regex {
+				matchType = "=~"
+			}
+		}
+		converted = append(converted, fmt.Sprintf("%s%s%q", m.Name, matchType, m.Value))
+	}
+	return converted
+}
+
+// parseAlertManagerDefinition parses an alert manager definition in the AMP
+// format and returns the definition and its Alertmanager configuration.
+func parseAlertManagerDefinition(
+	configuration string,
+) (map[string]interface{}, map[string]interface{}, error) {
+	var outer map[string]interface{}
+	if err := yaml.Unmarshal([]byte(configuration), &outer); err != nil {
+		return nil, nil, err
+	}
+	data, ok := outer["alertmanager_config"].(string)
+	if !ok {
+		return nil, nil, errNoAlertmanagerConfig
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		return nil, nil, fmt.Errorf("alertmanager_config: %v", err)
+	}
+	if _, ok := config["route"].(map[string]interface{}); !ok {
+		return nil, nil, errNoRootRoute
+	}
+	return outer, config, nil
+}
+
+// mergeAlertmanagerConfigs merges the supplied fragments into an
+// Alertmanager configuration and returns the resulting alert manager
+// definition. Like prometheus-operator, the routes of the fragments are
+// inserted before the child routes of the root route.
+func mergeAlertmanagerConfigs(
+	outer map[string]interface{},
+	config map[string]interface{},
+	fragments []*alertmanagerConfigFragment,
+) (string, error) {
+	root := config["route"].(map[string]interface{})
+	routes := []interface{}{}
+	receivers, _ := config["receivers"].([]interface{})
+	inhibitRules, _ := config["inhibit_rules"].([]interface{})
+	for _, f := range fragments {
+		if f.route != nil {
+			routes = append(routes, f.route)
+		}
+		for _, r := range f.receivers {
+			receivers = append(receivers, r)
+		}
+		for _, r := range f.inhibitRules {
+			inhibitRules = append(inhibitRules, r)
+		}
+	}
+	if existing, ok := root["routes"].([]interface{}); ok {
+		routes = append(routes, existing...)
+	}
+	if len(routes) > 0 {
+		root["routes"] = routes
+	}
+	if len(receivers) > 0 {
+		config["receivers"] = receivers
+	}
+	if len(inhibitRules) > 0 {
+		config["inhibit_rules"] = inhibitRules
+	}
+
+	data, err := marshalYAML(config)
+	if err != nil {
+		return "", err
+	}
+	outer["alertmanager_config"] = string(data)
+	data, err = marshalYAML(outer)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func marshalYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// alertmanagerConfigRequests returns a function that maps an
+// AlertmanagerConfig to reconcile requests for the alert manager definitions
+// that may select it, or may have selected it before it was changed.
+func alertmanagerConfigRequests(kc client.Reader) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		return alertManagerDefinitionRequests(kc, func(ko *svcapitypes.AlertManagerDefinition) bool {
+			return ko.Spec.AlertmanagerConfigNamespaceSelector != nil ||
+				ko.Namespace == obj.GetNamespace()
+		})
+	}
+}
+
+// namespaceRequests returns a function that maps a namespace to reconcile
+// requests for the alert manager definitions selecting the namespaces of
+// AlertmanagerConfig objects by label.
+func namespaceRequests(kc client.Reader) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		return alertManagerDefinitionRequests(kc, func(ko *svcapitypes.AlertManagerDefinition) bool {
+			return ko.Spec.AlertmanagerConfigNamespaceSelector != nil
+		})
+	}
+}
+
+// alertManagerDefinitionRequests returns reconcile requests for the alert
+// manager definitions that select AlertmanagerConfig objects and match the
+// supplied filter.
+func alertManagerDefinitionRequests(
+	kc client.Reader,
+	filter func(*svcapitypes.AlertManagerDefinition) bool,
+) []reconcile.Request {
+	list := &svcapitypes.AlertManagerDefinitionList{}
+	if err := kc.List(context.TODO(), list); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		ko := &list.Items[i]
+		if ko.Spec.AlertmanagerConfigSelector != nil && filter(ko) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: ko.Namespace,
+					Name:      ko.Name,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// setupAlertmanagerConfigWatch merges AlertmanagerConfig objects again into
+// the alert manager definitions selecting them when they, or the labels of
+// their namespace, change, by enqueueing them. The watch is only set up if
+// the prometheus-operator AlertmanagerConfig CRD is installed.
+func setupAlertmanagerConfigWatch(mgr ctrlrt.Manager, b *builder.Builder) error {
+	_, err := mgr.GetRESTMapper().RESTMapping(alertmanagerConfigGVK.GroupKind(), alertmanagerConfigGVK.Version)
+	if meta.IsNoMatchError(err) {
+		mgr.GetLogger().Info(
+			"AlertmanagerConfig CRD not installed, not watching AlertmanagerConfig objects",
+			"kind", alertmanagerConfigGVK.GroupKind().String(),
+		)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(alertmanagerConfigGVK)
+	b.Watches(
+		&source.Kind{Type: obj},
+		handler.EnqueueRequestsFromMapFunc(alertmanagerConfigRequests(mgr.GetClient())),
+	)
+	// Namespaces are watched in full, rather than their metadata only, so that
+	// selectedNamespaces lists them from the informer of this watch.
+	b.Watches(
+		&source.Kind{Type: &corev1.Namespace{}},
+		handler.EnqueueRequestsFromMapFunc(namespaceRequests(mgr.GetClient())),
+	)
+	return nil
+}
+
+func init() {
+	svcresource.RegisterWatches("AlertManagerDefinition", setupAlertmanagerConfigWatch)
+}
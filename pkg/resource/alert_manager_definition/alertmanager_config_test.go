@@ -0,0 +1,421 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+const baseConfiguration = `alertmanager_config: |
+  route:
+    receiver: default
+    routes:
+      - receiver: default
+        matchers:
+          - team="platform"
+  receivers:
+    - name: default
+`
+
+// newAlertmanagerConfig returns an AlertmanagerConfig routing the alerts with
+// the supplied severity to an SNS receiver.
+func newAlertmanagerConfig(namespace, name string, labels map[string]string, severity string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"route": map[string]interface{}{
+				"receiver": "sns",
+				"groupBy":  []interface{}{"alertname"},
+				"matchers": []interface{}{
+					map[string]interface{}{"name": "severity", "value": severity},
+				},
+			},
+			"receivers": []interface{}{
+				map[string]interface{}{
+					"name": "sns",
+					"snsConfigs": []interface{}{
+						map[string]interface{}{
+							"topicARN": "arn:aws:sns:us-west-2:123456789012:" + name,
+							"sigv4":    map[string]interface{}{"region": "us-west-2"},
+						},
+					},
+				},
+			},
+			"inhibitRules": []interface{}{
+				map[string]interface{}{
+					"sourceMatch": []interface{}{
+						map[string]interface{}{"name": "severity", "value": "critical"},
+					},
+					"targetMatch": []interface{}{
+						map[string]interface{}{"name": "severity", "value": "warn.*", "regex": true},
+					},
+					"equal": []interface{}{"alertname"},
+				},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(alertmanagerConfigGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func newAlertmanagerConfigClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = svcapitypes.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(alertmanagerConfigGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(
+		alertmanagerConfigGVK.GroupVersion().WithKind("AlertmanagerConfigList"),
+		&unstructured.UnstructuredList{},
+	)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func Test_convertAlertmanagerConfig(t *testing.T) {
+	got, err := convertAlertmanagerConfig(newAlertmanagerConfig("team-a", "alerts", nil, "critical"))
+	if err != nil {
+		t.Fatalf("convertAlertmanagerConfig() unexpected error = %v", err)
+	}
+	wantRoute := &route{
+		Receiver: "team-a/alerts/sns",
+		GroupBy:  []string{"alertname"},
+		Matchers: []string{`namespace="team-a"`, `severity="critical"`},
+		Continue: true,
+	}
+	if !reflect.DeepEqual(got.route, wantRoute) {
+		t.Errorf("convertAlertmanagerConfig() route = %+v, want %+v", got.route, wantRoute)
+	}
+	if len(got.receivers) != 1 || got.receivers[0].Name != "team-a/alerts/sns" ||
+		got.receivers[0].SNSConfigs[0].Sigv4.Region != "us-west-2" {
+		t.Errorf("convertAlertmanagerConfig() receivers = %+v", got.receivers)
+	}
+	wantInhibitRule := &inhibitRule{
+		SourceMatchers: []string{`namespace="team-a"`, `severity="critical"`},
+		TargetMatchers: []string{`namespace="team-a"`, `severity=~"warn.*"`},
+		Equal:          []string{"alertname"},
+	}
+	if len(got.inhibitRules) != 1 || !reflect.DeepEqual(got.inhibitRules[0], wantInhibitRule) {
+		t.Errorf("convertAlertmanagerConfig() inhibit rules = %+v, want %+v", got.inhibitRules, wantInhibitRule)
+	}
+}
+
+func Test_convertAlertmanagerConfig_unsupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(spec map[string]interface{})
+		wantErr string
+	}{
+		{
+			name: "slack receiver",
+			mutate: func(spec map[string]interface{}) {
+				receiver := spec["receivers"].([]interface{})[0].(map[string]interface{})
+				receiver["slackConfigs"] = []interface{}{map[string]interface{}{"channel": "#alerts"}}
+			},
+			wantErr: "slackConfigs are not supported",
+		},
+		{
+			name: "undefined receiver",
+			mutate: func(spec map[string]interface{}) {
+				spec["route"].(map[string]interface{})["receiver"] = "missing"
+			},
+			wantErr: `undefined receiver "missing"`,
+		},
+		{
+			name: "undefined child route receiver",
+			mutate: func(spec map[string]interface{}) {
+				spec["route"].(map[string]interface{})["routes"] = []interface{}{
+					map[string]interface{}{"receiver": "missing"},
+				}
+			},
+			wantErr: `undefined receiver "missing"`,
+		},
+		{
+			name: "duplicate receiver",
+			mutate: func(spec map[string]interface{}) {
+				receivers := spec["receivers"].([]interface{})
+				spec["receivers"] = append(receivers, map[string]interface{}{"name": "sns"})
+			},
+			wantErr: `receiver "sns" is defined more than once`,
+		},
+		{
+			name: "SNS credentials",
+			mutate: func(spec map[string]interface{}) {
+				receiver := spec["receivers"].([]interface{})[0].(map[string]interface{})
+				sns := receiver["snsConfigs"].([]interface{})[0].(map[string]interface{})
+				sns["sigv4"].(map[string]interface{})["accessKey"] = map[string]interface{}{"name": "aws", "key": "id"}
+			},
+			wantErr: "access and secret keys are not supported",
+		},
+		{
+			name: "mute time intervals",
+			mutate: func(spec map[string]interface{}) {
+				spec["route"].(map[string]interface{})["muteTimeIntervals"] = []interface{}{"weekends"}
+			},
+			wantErr: "time intervals are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newAlertmanagerConfig("team-a", "alerts", nil, "critical")
+			tt.mutate(obj.Object["spec"].(map[string]interface{}))
+			_, err := convertAlertmanagerConfig(obj)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("convertAlertmanagerConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_resolveAlertmanagerConfigs(t *testing.T) {
+	selected := map[string]string{"amp": "true"}
+	conflicting := newAlertmanagerConfig("default", "conflicting", selected, "info")
+	conflicting.Object["spec"].(map[string]interface{})["route"].(map[string]interface{})["receiver"] = "missing"
+	kc := newAlertmanagerConfigClient(
+		newAlertmanagerConfig("default", "b", selected, "warning"),
+		newAlertmanagerConfig("default", "a", selected, "critical"),
+		newAlertmanagerConfig("default", "unselected", nil, "critical"),
+		newAlertmanagerConfig("other", "c", selected, "critical"),
+		conflicting,
+	)
+	ko := &svcapitypes.AlertManagerDefinition{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "amd"},
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			Configuration:              aws.String(baseConfiguration),
+			AlertmanagerConfigSelector: &metav1.LabelSelector{MatchLabels: selected},
+		},
+	}
+	if err := resolveAlertmanagerConfigs(context.TODO(), kc, "default", ko); err != nil {
+		t.Fatalf("resolveAlertmanagerConfigs() unexpected error = %v", err)
+	}
+
+	want := `alertmanager_config: |
+  inhibit_rules:
+    - source_matchers:
+        - namespace="default"
+        - severity="critical"
+      target_matchers:
+        - namespace="default"
+        - severity=~"warn.*"
+      equal:
+        - alertname
+    - source_matchers:
+        - namespace="default"
+        - severity="critical"
+      target_matchers:
+        - namespace="default"
+        - severity=~"warn.*"
+      equal:
+        - alertname
+  receivers:
+    - name: default
+    - name: default/a/sns
+      sns_configs:
+        - sigv4:
+            region: us-west-2
+          topic_arn: arn:aws:sns:us-west-2:123456789012:a
+    - name: default/b/sns
+      sns_configs:
+        - sigv4:
+            region: us-west-2
+          topic_arn: arn:aws:sns:us-west-2:123456789012:b
+  route:
+    receiver: default
+    routes:
+      - receiver: default/a/sns
+        group_by:
+          - alertname
+        matchers:
+          - namespace="default"
+          - severity="critical"
+        continue: true
+      - receiver: default/b/sns
+        group_by:
+          - alertname
+        matchers:
+          - namespace="default"
+          - severity="warning"
+        continue: true
+      - matchers:
+          - team="platform"
+        receiver: default
+`
+	if got := aws.StringValue(ko.Spec.Configuration); got != want {
+		t.Errorf("resolveAlertmanagerConfigs() configuration = %s, want %s", got, want)
+	}
+	if got, want := aws.StringValueSlice(ko.Status.ImportedAlertmanagerConfigs), []string{"default/a", "default/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Status.ImportedAlertmanagerConfigs = %v, want %v", got, want)
+	}
+	conflicts := ko.Status.AlertmanagerConfigConflicts
+	if len(conflicts) != 1 || aws.StringValue(conflicts[0].Name) != "conflicting" ||
+		!strings.Contains(aws.StringValue(conflicts[0].Message), "undefined receiver") {
+		t.Errorf("Status.AlertmanagerConfigConflicts = %v", conflicts)
+	}
+}
+
+func Test_resolveAlertmanagerConfigs_namespaceSelector(t *testing.T) {
+	selected := map[string]string{"amp": "true"}
+	kc := newAlertmanagerConfigClient(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: selected}},
+		newAlertmanagerConfig("default", "a", selected, "critical"),
+		newAlertmanagerConfig("team-a", "b", selected, "critical"),
+	)
+	ko := &svcapitypes.AlertManagerDefinition{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "amd"},
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			Configuration:                       aws.String(baseConfiguration),
+			AlertmanagerConfigSelector:          &metav1.LabelSelector{},
+			AlertmanagerConfigNamespaceSelector: &metav1.LabelSelector{MatchLabels: selected},
+		},
+	}
+	if err := resolveAlertmanagerConfigs(context.TODO(), kc, "default", ko); err != nil {
+		t.Fatalf("resolveAlertmanagerConfigs() unexpected error = %v", err)
+	}
+	if got, want := aws.StringValueSlice(ko.Status.ImportedAlertmanagerConfigs), []string{"team-a/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Status.ImportedAlertmanagerConfigs = %v, want %v", got, want)
+	}
+}
+
+func Test_resolveAlertmanagerConfigs_receiverConflict(t *testing.T) {
+	selected := map[string]string{"amp": "true"}
+	kc := newAlertmanagerConfigClient(newAlertmanagerConfig("default", "a", selected, "critical"))
+	configuration := baseConfiguration + "    - name: default/a/sns\n"
+	ko := &svcapitypes.AlertManagerDefinition{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "amd"},
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			Configuration:              aws.String(configuration),
+			AlertmanagerConfigSelector: &metav1.LabelSelector{MatchLabels: selected},
+		},
+		Status: svcapitypes.AlertManagerDefinitionStatus{
+			ImportedAlertmanagerConfigs: []*string{aws.String("default/a")},
+		},
+	}
+	if err := resolveAlertmanagerConfigs(context.TODO(), kc, "default", ko); err != nil {
+		t.Fatalf("resolveAlertmanagerConfigs() unexpected error = %v", err)
+	}
+	if got := aws.StringValue(ko.Spec.Configuration); got != configuration {
+		t.Errorf("resolveAlertmanagerConfigs() changed the configuration to %s", got)
+	}
+	if ko.Status.ImportedAlertmanagerConfigs != nil {
+		t.Errorf("Status.ImportedAlertmanagerConfigs = %v, want nil", ko.Status.ImportedAlertmanagerConfigs)
+	}
+	conflicts := ko.Status.AlertmanagerConfigConflicts
+	if len(conflicts) != 1 || !strings.Contains(aws.StringValue(conflicts[0].Message), "already defined") {
+		t.Errorf("Status.AlertmanagerConfigConflicts = %v", conflicts)
+	}
+}
+
+// noMatchReader is a client.Reader of a cluster without the
+// AlertmanagerConfig CRD.
+type noMatchReader struct {
+	client.Reader
+}
+
+func (noMatchReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return &meta.NoKindMatchError{GroupKind: alertmanagerConfigGVK.GroupKind()}
+}
+
+func Test_resolveAlertmanagerConfigs_errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		kc           client.Reader
+		wantTerminal bool
+		wantErr      string
+	}{
+		{
+			name:         "CRD not installed",
+			kc:           noMatchReader{},
+			wantTerminal: true,
+			wantErr:      "AlertmanagerConfig CRD not installed",
+		},
+		{
+			name:    "no cached client",
+			kc:      nil,
+			wantErr: "no cached client",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.AlertManagerDefinition{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "amd"},
+				Spec: svcapitypes.AlertManagerDefinitionSpec{
+					Configuration:              aws.String(baseConfiguration),
+					AlertmanagerConfigSelector: &metav1.LabelSelector{},
+				},
+			}
+			err := resolveAlertmanagerConfigs(context.TODO(), tt.kc, "default", ko)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("resolveAlertmanagerConfigs() error = %v, want %q", err, tt.wantErr)
+			}
+			var terminal *ackerr.TerminalError
+			if got := errors.As(err, &terminal); got != tt.wantTerminal {
+				t.Errorf("resolveAlertmanagerConfigs() terminal = %v, want %v", got, tt.wantTerminal)
+			}
+		})
+	}
+}
+
+func Test_alertmanagerConfigRequests(t *testing.T) {
+	newAlertManagerDefinition := func(namespace, name string, selector, namespaceSelector *metav1.LabelSelector) *svcapitypes.AlertManagerDefinition {
+		return &svcapitypes.AlertManagerDefinition{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: svcapitypes.AlertManagerDefinitionSpec{
+				AlertmanagerConfigSelector:          selector,
+				AlertmanagerConfigNamespaceSelector: namespaceSelector,
+			},
+		}
+	}
+	kc := newAlertmanagerConfigClient(
+		newAlertManagerDefinition("default", "a", &metav1.LabelSelector{}, nil),
+		newAlertManagerDefinition("monitoring", "b", &metav1.LabelSelector{}, &metav1.LabelSelector{}),
+		newAlertManagerDefinition("monitoring", "c", &metav1.LabelSelector{}, nil),
+		newAlertManagerDefinition("default", "d", nil, nil),
+	)
+
+	got := alertmanagerConfigRequests(kc)(newAlertmanagerConfig("default", "x", nil, "critical"))
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}},
+		{NamespacedName: types.NamespacedName{Namespace: "monitoring", Name: "b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("alertmanagerConfigRequests() = %v, want %v", got, want)
+	}
+
+	got = namespaceRequests(kc)(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+	want = []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "monitoring", Name: "b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("namespaceRequests() = %v, want %v", got, want)
+	}
+}
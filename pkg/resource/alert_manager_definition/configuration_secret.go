@@ -0,0 +1,153 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+)
+
+var (
+	ErrConfigurationAndSecretRefSet    = errors.New("only one of spec.configuration and spec.configurationSecretRef may be set")
+	ErrConfigurationOrSecretRefMissing = errors.New("one of spec.configuration or spec.configurationSecretRef must be set")
+)
+
+// validateConfigurationFields checks that exactly one of Spec.Configuration
+// and Spec.ConfigurationSecretRef is set.
+func validateConfigurationFields(ko *svcapitypes.AlertManagerDefinition) error {
+	if ko.Spec.Configuration != nil && ko.Spec.ConfigurationSecretRef != nil {
+		return ErrConfigurationAndSecretRefSet
+	}
+	if ko.Spec.Configuration == nil && ko.Spec.ConfigurationSecretRef == nil {
+		return ErrConfigurationOrSecretRefMissing
+	}
+	return nil
+}
+
+// resolveConfigurationSecretRef reads the Secret key referenced by
+// Spec.ConfigurationSecretRef into Spec.Configuration. Like other resolved
+// references, the configuration is not persisted to the Kubernetes API: the
+// patch of the custom resource is computed against the resolved desired
+// state. Spec.ConfigurationSecretRef is kept so that the rest of the resource
+// manager knows the configuration must not be exposed.
+func (rm *resourceManager) resolveConfigurationSecretRef(
+	ctx context.Context,
+	namespace string,
+	ko *svcapitypes.AlertManagerDefinition,
+) error {
+	if ko.Spec.ConfigurationSecretRef == nil {
+		return nil
+	}
+	if err := validateConfigurationFields(ko); err != nil {
+		return ackerr.NewTerminalError(err)
+	}
+	ref := ko.Spec.ConfigurationSecretRef.DeepCopy()
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+	configuration, err := rm.rr.SecretValueFromReference(ctx, ref)
+	if err != nil {
+		return err
+	}
+	ko.Spec.Configuration = &configuration
+	return nil
+}
+
+// configurationHash returns the hash of the supplied alert manager
+// definition recorded in Status.ConfigurationHash.
+func configurationHash(configuration string) string {
+	sum := sha256.Sum256([]byte(configuration))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// setConfigurationHash records the hash of the configuration of the supplied
+// resource if it is read from a Secret, and clears it otherwise.
+func setConfigurationHash(ko *svcapitypes.AlertManagerDefinition) {
+	if ko.Spec.ConfigurationSecretRef == nil || ko.Spec.Configuration == nil {
+		ko.Status.ConfigurationHash = nil
+		return
+	}
+	hash := configurationHash(*ko.Spec.Configuration)
+	ko.Status.ConfigurationHash = &hash
+}
+
+// referencesSecret returns true if the supplied resource references the
+// named Secret in Spec.ConfigurationSecretRef.
+func referencesSecret(ko *svcapitypes.AlertManagerDefinition, namespace, name string) bool {
+	ref := ko.Spec.ConfigurationSecretRef
+	if ref == nil || ref.Name != name {
+		return false
+	}
+	if ref.Namespace == "" {
+		return ko.Namespace == namespace
+	}
+	return ref.Namespace == namespace
+}
+
+// secretRequests returns a function that maps a Secret to reconcile requests
+// for the alert manager definitions that reference it.
+func secretRequests(kc client.Reader) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		list := &svcapitypes.AlertManagerDefinitionList{}
+		if err := kc.List(context.TODO(), list); err != nil {
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range list.Items {
+			if referencesSecret(&list.Items[i], obj.GetNamespace(), obj.GetName()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: list.Items[i].Namespace,
+						Name:      list.Items[i].Name,
+					},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// setupSecretWatch pushes the configuration of alert manager definitions
+// again when the Secret they reference changes, by enqueueing them. Only the
+// metadata of Secrets is watched, so that their values are not cached by the
+// controller.
+func setupSecretWatch(mgr ctrlrt.Manager, b *builder.Builder) error {
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	b.Watches(
+		&source.Kind{Type: secret},
+		handler.EnqueueRequestsFromMapFunc(secretRequests(mgr.GetClient())),
+	)
+	return nil
+}
+
+func init() {
+	svcresource.RegisterWatches("AlertManagerDefinition", setupSecretWatch)
+}
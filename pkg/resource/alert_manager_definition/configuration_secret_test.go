@@ -0,0 +1,208 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+const secretConfiguration = "alertmanager_config: |\n  route:\n    receiver: default\n  receivers:\n  - name: default\n"
+
+// fakeReconciler serves Secret values from a map keyed by
+// "namespace/name/key".
+type fakeReconciler struct {
+	secrets map[string]string
+}
+
+func (r *fakeReconciler) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (r *fakeReconciler) SecretValueFromReference(
+	ctx context.Context,
+	ref *ackv1alpha1.SecretKeyReference,
+) (string, error) {
+	value, ok := r.secrets[ref.Namespace+"/"+ref.Name+"/"+ref.Key]
+	if !ok {
+		return "", ackerr.SecretNotFound
+	}
+	return value, nil
+}
+
+func secretKeyRef(namespace, name, key string) *ackv1alpha1.SecretKeyReference {
+	return &ackv1alpha1.SecretKeyReference{
+		SecretReference: corev1.SecretReference{Namespace: namespace, Name: name},
+		Key:             key,
+	}
+}
+
+func Test_resolveConfigurationSecretRef(t *testing.T) {
+	rm := &resourceManager{rr: &fakeReconciler{secrets: map[string]string{
+		"default/alertmanager/config.yaml": secretConfiguration,
+		"other/alertmanager/config.yaml":   "other",
+	}}}
+	tests := []struct {
+		name    string
+		spec    svcapitypes.AlertManagerDefinitionSpec
+		want    *string
+		wantErr string
+	}{
+		{
+			name: "plain configuration",
+			spec: svcapitypes.AlertManagerDefinitionSpec{Configuration: aws.String("plain")},
+			want: aws.String("plain"),
+		},
+		{
+			name: "namespace defaults to the resource namespace",
+			spec: svcapitypes.AlertManagerDefinitionSpec{
+				ConfigurationSecretRef: secretKeyRef("", "alertmanager", "config.yaml"),
+			},
+			want: aws.String(secretConfiguration),
+		},
+		{
+			name: "explicit namespace",
+			spec: svcapitypes.AlertManagerDefinitionSpec{
+				ConfigurationSecretRef: secretKeyRef("other", "alertmanager", "config.yaml"),
+			},
+			want: aws.String("other"),
+		},
+		{
+			name: "missing Secret",
+			spec: svcapitypes.AlertManagerDefinitionSpec{
+				ConfigurationSecretRef: secretKeyRef("", "missing", "config.yaml"),
+			},
+			wantErr: ackerr.SecretNotFound.Error(),
+		},
+		{
+			name: "configuration and configurationSecretRef",
+			spec: svcapitypes.AlertManagerDefinitionSpec{
+				Configuration:          aws.String("plain"),
+				ConfigurationSecretRef: secretKeyRef("", "alertmanager", "config.yaml"),
+			},
+			wantErr: ErrConfigurationAndSecretRefSet.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.AlertManagerDefinition{Spec: tt.spec}
+			err := rm.resolveConfigurationSecretRef(context.TODO(), "default", ko)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveConfigurationSecretRef() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveConfigurationSecretRef() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(ko.Spec.Configuration, tt.want) {
+				t.Errorf("resolveConfigurationSecretRef() configuration = %v, want %v",
+					aws.StringValue(ko.Spec.Configuration), aws.StringValue(tt.want))
+			}
+			if !reflect.DeepEqual(ko.Spec.ConfigurationSecretRef, tt.spec.ConfigurationSecretRef) {
+				t.Errorf("resolveConfigurationSecretRef() changed Spec.ConfigurationSecretRef")
+			}
+		})
+	}
+}
+
+func Test_setConfigurationHash(t *testing.T) {
+	ko := &svcapitypes.AlertManagerDefinition{
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			Configuration:          aws.String("config"),
+			ConfigurationSecretRef: secretKeyRef("", "alertmanager", "config.yaml"),
+		},
+	}
+	setConfigurationHash(ko)
+	want := "sha256:b79606fb3afea5bd1609ed40b622142f1c98125abcfe89a76a661b0e8e343910"
+	if got := aws.StringValue(ko.Status.ConfigurationHash); got != want {
+		t.Errorf("setConfigurationHash() = %s, want %s", got, want)
+	}
+
+	ko.Spec.ConfigurationSecretRef = nil
+	setConfigurationHash(ko)
+	if ko.Status.ConfigurationHash != nil {
+		t.Errorf("setConfigurationHash() = %s, want nil", *ko.Status.ConfigurationHash)
+	}
+}
+
+func Test_customPreCompare_secret(t *testing.T) {
+	a := &resource{ko: &svcapitypes.AlertManagerDefinition{
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			Configuration:          aws.String(secretConfiguration),
+			ConfigurationSecretRef: secretKeyRef("", "alertmanager", "config.yaml"),
+		},
+	}}
+	b := a.DeepCopy().(*resource)
+	b.ko.Spec.Configuration = aws.String("alertmanager_config: |\n  route:\n    receiver: other\n")
+
+	delta := ackcompare.NewDelta()
+	customPreCompare(delta, a, b)
+	if !delta.DifferentAt("Spec.Configuration") {
+		t.Fatalf("customPreCompare() expected a difference")
+	}
+	for _, diff := range delta.Differences {
+		for _, v := range []interface{}{diff.A, diff.B} {
+			if s, ok := v.(*string); ok && s != nil && !strings.HasPrefix(*s, "sha256:") {
+				t.Errorf("customPreCompare() recorded the configuration %q in the delta", *s)
+			}
+		}
+	}
+}
+
+func Test_secretRequests(t *testing.T) {
+	newAlertManagerDefinition := func(namespace, name string, ref *ackv1alpha1.SecretKeyReference) *svcapitypes.AlertManagerDefinition {
+		return &svcapitypes.AlertManagerDefinition{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       svcapitypes.AlertManagerDefinitionSpec{ConfigurationSecretRef: ref},
+		}
+	}
+	scheme := runtime.NewScheme()
+	_ = svcapitypes.AddToScheme(scheme)
+	kc := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newAlertManagerDefinition("default", "a", secretKeyRef("", "alertmanager", "config.yaml")),
+		newAlertManagerDefinition("other", "b", secretKeyRef("default", "alertmanager", "config.yaml")),
+		newAlertManagerDefinition("other", "c", secretKeyRef("", "alertmanager", "config.yaml")),
+		newAlertManagerDefinition("default", "d", secretKeyRef("", "other", "config.yaml")),
+		newAlertManagerDefinition("default", "e", nil),
+	).Build()
+
+	secret := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "alertmanager"},
+	}
+	got := secretRequests(kc)(secret)
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}},
+		{NamespacedName: types.NamespacedName{Namespace: "other", Name: "b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("secretRequests() = %v, want %v", got, want)
+	}
+}
@@ -42,6 +42,7 @@ func newResourceDelta(
 		delta.Add("", a, b)
 		return delta
 	}
+	customPreCompare(delta, a, b)
 
 	if ackcompare.HasNilDifference(a.ko.Spec.WorkspaceID, b.ko.Spec.WorkspaceID) {
 		delta.Add("Spec.WorkspaceID", a.ko.Spec.WorkspaceID, b.ko.Spec.WorkspaceID)
@@ -50,12 +51,8 @@ func newResourceDelta(
 			delta.Add("Spec.WorkspaceID", a.ko.Spec.WorkspaceID, b.ko.Spec.WorkspaceID)
 		}
 	}
-	if ackcompare.HasNilDifference(a.ko.Spec.Configuration, b.ko.Spec.Configuration) {
-		delta.Add("Spec.Configuration", a.ko.Spec.Configuration, b.ko.Spec.Configuration)
-	} else if a.ko.Spec.Configuration != nil && b.ko.Spec.Configuration != nil {
-		if *a.ko.Spec.Configuration != *b.ko.Spec.Configuration {
-			delta.Add("Spec.Configuration", a.ko.Spec.Configuration, b.ko.Spec.Configuration)
-		}
+	if !reflect.DeepEqual(a.ko.Spec.WorkspaceRef, b.ko.Spec.WorkspaceRef) {
+		delta.Add("Spec.WorkspaceRef", a.ko.Spec.WorkspaceRef, b.ko.Spec.WorkspaceRef)
 	}
 
 	return delta
@@ -0,0 +1,47 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+)
+
+// resolveExtensionReferences resolves the references of the supplied alert
+// manager definition that are not declared in generator.yaml: the Secret key
+// of Spec.ConfigurationSecretRef, then the AlertmanagerConfig objects
+// selected by Spec.AlertmanagerConfigSelector, which are listed through the
+// cached client rather than the supplied API reader. It is called by the
+// generated ResolveReferences once the Workspace reference is resolved.
+func (rm *resourceManager) resolveExtensionReferences(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	ko *svcapitypes.AlertManagerDefinition,
+) error {
+	if err := rm.resolveConfigurationSecretRef(ctx, namespace, ko); err != nil {
+		return err
+	}
+	return resolveAlertmanagerConfigs(ctx, svcresource.KubeClient(rm.rr), namespace, ko)
+}
+
+// hasNonNilExtensionReferences returns true if the supplied alert manager
+// definition references a Secret key or selects AlertmanagerConfig objects.
+func hasNonNilExtensionReferences(ko *svcapitypes.AlertManagerDefinition) bool {
+	return ko.Spec.ConfigurationSecretRef != nil || ko.Spec.AlertmanagerConfigSelector != nil
+}
@@ -17,87 +17,70 @@ import (
 	"context"
 	"errors"
 	"strings"
-	"time"
 
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
 	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
-	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
 	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
 	corev1 "k8s.io/api/core/v1"
 
 	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
-)
-
-var (
-	ErrAlertManagerDefinitionCreating = errors.New("Alert Manager Definition in 'CREATING' state, cannot be modified or deleted")
-	ErrAlertManagerDefinitionDeleting = errors.New("Alert Manager Definition in 'DELETING' state, cannot be modified or deleted")
-	ErrAlertManagerDefinitionUpdating = errors.New("Alert Manager Definition in 'UPDATING' state, cannot be modified or deleted")
-)
-
-var (
-	requeueWaitWhileDeleting = ackrequeue.NeededAfter(
-		ErrAlertManagerDefinitionDeleting,
-		10*time.Second,
-	)
-	requeueWaitWhileCreating = ackrequeue.NeededAfter(
-		ErrAlertManagerDefinitionCreating,
-		15*time.Second,
-	)
-	requeueWaitWhileUpdating = ackrequeue.NeededAfter(
-		ErrAlertManagerDefinitionUpdating,
-		10*time.Second,
-	)
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspacestatus"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/yamlcompare"
 )
 
 // alertManagerDefinitionCreating returns true if the supplied definition
 // is in the process of being created
 func alertManagerDefinitionCreating(r *resource) bool {
-	if r.ko.Status.StatusCode == nil {
-		return false
-	}
-	ws := *r.ko.Status.StatusCode
-	return ws == string(svcapitypes.AlertManagerDefinitionStatusCode_CREATING)
-}
-
-// alertManagerDefinitionDeleting returns true if the supplied definition
-// is in the process of being deleted
-func alertManagerDefinitionDeleting(r *resource) bool {
-	if r.ko.Status.StatusCode == nil {
-		return false
-	}
-	ws := *r.ko.Status.StatusCode
-	return ws == string(svcapitypes.AlertManagerDefinitionStatusCode_DELETING)
+	return ampstatus.AlertManagerDefinition.Is(r.ko.Status.StatusCode, ampstatus.Creating)
 }
 
 // alertManagerDefinitionUpdating returns true if the supplied definition
 // is in the process of being updated
 func alertManagerDefinitionUpdating(r *resource) bool {
-	if r.ko.Status.StatusCode == nil {
-		return false
-	}
-	ws := *r.ko.Status.StatusCode
-	return ws == string(svcapitypes.AlertManagerDefinitionStatusCode_UPDATING)
-}
-
-// alertManagerDefinitionActive returns true if the supplied
-// definition is in an active state
-func alertManagerDefinitionActive(r *resource) bool {
-	if r.ko.Status.StatusCode == nil {
-		return false
-	}
-	ws := *r.ko.Status.StatusCode
-	return ws == string(svcapitypes.AlertManagerDefinitionStatusCode_ACTIVE)
+	return ampstatus.AlertManagerDefinition.Is(r.ko.Status.StatusCode, ampstatus.Updating)
 }
 
 // alertManagerDefinitionStatusFailed returns true if the supplied definition
 // has a status of creation failed  or update failed
 func alertManagerDefinitionStatusFailed(r *resource) bool {
-	if r.ko.Status.StatusCode == nil {
-		return false
-	}
-	ws := *r.ko.Status.StatusCode
-	return ws == string(svcapitypes.AlertManagerDefinitionStatusCode_CREATION_FAILED) || ws == string(svcapitypes.AlertManagerDefinitionStatusCode_UPDATE_FAILED)
+	return ampstatus.AlertManagerDefinition.Terminal(r.ko.Status.StatusCode)
+}
+
+// setAlertManagerDefinitionOperationStartTime records in the supplied
+// definition the time at which it entered its current CREATING, UPDATING or
+// DELETING status, given the resource it was observed from.
+func setAlertManagerDefinitionOperationStartTime(r *resource, ko *svcapitypes.AlertManagerDefinition) {
+	ko.Status.OperationStartTime = ampstatus.AlertManagerDefinition.OperationStartTime(
+		r.ko.Status.StatusCode, r.ko.Status.OperationStartTime, ko.Status.StatusCode,
+	)
+}
+
+// observeAlertManagerDefinitionTransition logs the supplied definition moving
+// to a status that its state machine does not expect, given the resource it
+// was observed from.
+func observeAlertManagerDefinitionTransition(ctx context.Context, r *resource, ko *svcapitypes.AlertManagerDefinition) {
+	ampstatus.AlertManagerDefinition.ObserveTransition(ctx, r.ko.Status.StatusCode, ko.Status.StatusCode)
+}
+
+// ensureAlertManagerDefinitionNotStalled returns an error requeueing the
+// supplied definition with a Stalled condition if it has been CREATING,
+// UPDATING or DELETING for longer than the configured stalled timeout of
+// alert manager definitions.
+func (rm *resourceManager) ensureAlertManagerDefinitionNotStalled(ko *svcapitypes.AlertManagerDefinition) error {
+	return ampstatus.AlertManagerDefinition.EnsureNotStalled(
+		&resource{ko}, ko.Status.StatusCode, ko.Status.OperationStartTime,
+		svcresource.StalledTimeouts(rm.rr).For(ampstatus.AlertManagerDefinition),
+	)
+}
+
+// requeueWhileAlertManagerDefinitionModifying returns a requeue error if the
+// supplied definition is being created, updated or deleted, since the API
+// rejects modifications in those states. It returns nil otherwise.
+func requeueWhileAlertManagerDefinitionModifying(r *resource) error {
+	return ampstatus.AlertManagerDefinition.RequeueWhileBusy(r.ko.Status.StatusCode)
 }
 
 // alertManagerDefinitionValidationError returns true if the Status Reason
@@ -129,14 +112,8 @@ func (rm *resourceManager) customUpdateAlertManagerDefinition(
 	// If it is, then requeue because we can't update while it is in those states.
 	// For failed states (create & update) and active states, the user can
 	// still update the alert manager definition.
-	var sc *string = latest.ko.Status.StatusCode
-	switch *sc {
-	case string(svcapitypes.AlertManagerDefinitionStatusCode_DELETING):
-		return desired, requeueWaitWhileDeleting
-	case string(svcapitypes.AlertManagerDefinitionStatusCode_UPDATING):
-		return desired, requeueWaitWhileUpdating
-	case string(svcapitypes.AlertManagerDefinitionStatusCode_CREATING):
-		return desired, requeueWaitWhileCreating
+	if err = requeueWhileAlertManagerDefinitionModifying(latest); err != nil {
+		return desired, err
 	}
 
 	// Merge in the information we read from the API call above to the copy of
@@ -208,8 +185,10 @@ func (rm *resourceManager) updateAlertManagerDefinitionData(
 			ko.Status.StatusReason = nil
 
 		}
+		setConfigurationHash(ko)
 
 		rm.setStatusDefaults(ko)
+		setAlertManagerDefinitionOperationStartTime(desired, ko)
 		// Some updates might be instant and the resource will remain in an active state.
 		// While other updates, might take a while to update and the resource will be in an `UPDATING`
 		// state. If this is the case, then we want to requeue until the resource is done updating.
@@ -223,3 +202,64 @@ func (rm *resourceManager) updateAlertManagerDefinitionData(
 		return &resource{ko}, nil
 	}
 }
+
+// customPreCompare compares the configurations of the supplied resources as
+// parsed YAML, including the nested Alertmanager configuration, so that
+// formatting, key order and comment differences between the desired
+// configuration and the one AMP returns don't trigger an update.
+// Configurations read from a Secret are recorded in the delta by their hash,
+// since the runtime logs the differences.
+func customPreCompare(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	configA, configB := a.ko.Spec.Configuration, b.ko.Spec.Configuration
+	different := false
+	if ackcompare.HasNilDifference(configA, configB) {
+		different = true
+	} else if configA != nil && configB != nil {
+		different = !yamlcompare.Equal(*configA, *configB, "alertmanager_config")
+	}
+	if !different {
+		return
+	}
+	if a.ko.Spec.ConfigurationSecretRef != nil {
+		configA, configB = redactedConfiguration(configA), redactedConfiguration(configB)
+	}
+	delta.Add("Spec.Configuration", configA, configB)
+}
+
+// redactedConfiguration returns the hash of the supplied configuration in
+// place of the configuration.
+func redactedConfiguration(configuration *string) *string {
+	if configuration == nil {
+		return nil
+	}
+	hash := configurationHash(*configuration)
+	return &hash
+}
+
+// ensureWorkspaceActive returns a copy of the supplied resource with a
+// WorkspaceNotReady condition and an error requeueing it if its workspace is
+// not ACTIVE yet. AMP rejects the creation with a ConflictException until
+// then.
+func (rm *resourceManager) ensureWorkspaceActive(
+	ctx context.Context,
+	r *resource,
+) (*resource, error) {
+	if r.ko.Spec.WorkspaceID == nil {
+		return nil, nil
+	}
+	err := workspacestatus.EnsureActive(
+		ctx, rm.sdkapi, rm.metrics,
+		workspacestatus.For(rm.awsAccountID, rm.awsRegion),
+		*r.ko.Spec.WorkspaceID,
+	)
+	var notReady *workspacestatus.NotReadyError
+	if !errors.As(err, &notReady) {
+		return nil, err
+	}
+	ko := r.ko.DeepCopy()
+	return &resource{ko}, workspacestatus.SetNotReady(&resource{ko}, notReady)
+}
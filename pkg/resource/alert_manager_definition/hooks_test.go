@@ -0,0 +1,101 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func Test_customPreCompare(t *testing.T) {
+	newResource := func(configuration string) *resource {
+		return &resource{ko: &svcapitypes.AlertManagerDefinition{
+			Spec: svcapitypes.AlertManagerDefinitionSpec{Configuration: aws.String(configuration)},
+		}}
+	}
+	tests := []struct {
+		name     string
+		a        *resource
+		b        *resource
+		wantDiff bool
+	}{
+		{
+			name:     "identical",
+			a:        newResource("alertmanager_config: |\n  route:\n    receiver: default\n"),
+			b:        newResource("alertmanager_config: |\n  route:\n    receiver: default\n"),
+			wantDiff: false,
+		},
+		{
+			name:     "reformatted nested configuration",
+			a:        newResource("alertmanager_config: |\n  route:\n    receiver: default\n    group_wait: 30s\n"),
+			b:        newResource("alertmanager_config: |\n  # comment\n  route: {group_wait: 30s, receiver: default}\n"),
+			wantDiff: false,
+		},
+		{
+			name:     "changed nested configuration",
+			a:        newResource("alertmanager_config: |\n  route:\n    receiver: default\n"),
+			b:        newResource("alertmanager_config: |\n  route:\n    receiver: other\n"),
+			wantDiff: true,
+		},
+		{
+			name:     "changed template",
+			a:        newResource("template_files:\n  t: a\nalertmanager_config: |\n  route:\n    receiver: default\n"),
+			b:        newResource("template_files:\n  t: b\nalertmanager_config: |\n  route:\n    receiver: default\n"),
+			wantDiff: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := ackcompare.NewDelta()
+			customPreCompare(delta, tt.a, tt.b)
+			if got := delta.DifferentAt("Spec.Configuration"); got != tt.wantDiff {
+				t.Errorf("customPreCompare() difference = %v, want %v", got, tt.wantDiff)
+			}
+		})
+	}
+}
+
+func Test_customUpdateAlertManagerDefinition_status(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  *string
+		wantRequeue bool
+	}{
+		{name: "unknown status", statusCode: nil},
+		{name: "active", statusCode: aws.String("ACTIVE")},
+		{name: "update failed", statusCode: aws.String("UPDATE_FAILED")},
+		{name: "creating", statusCode: aws.String("CREATING"), wantRequeue: true},
+		{name: "deleting", statusCode: aws.String("DELETING"), wantRequeue: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := &resource{ko: &svcapitypes.AlertManagerDefinition{}}
+			latest := &resource{ko: &svcapitypes.AlertManagerDefinition{}}
+			latest.ko.Status.StatusCode = tt.statusCode
+			rm := &resourceManager{}
+			_, err := rm.customUpdateAlertManagerDefinition(context.TODO(), desired, latest, ackcompare.NewDelta())
+			var requeue *ackrequeue.RequeueNeededAfter
+			if got := errors.As(err, &requeue); got != tt.wantRequeue || (!got && err != nil) {
+				t.Errorf("customUpdateAlertManagerDefinition() error = %v, want requeue %v", err, tt.wantRequeue)
+			}
+		})
+	}
+}
@@ -19,9 +19,11 @@ import (
 	"context"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
 	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
 
 	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspaceref"
 )
 
 // ResolveReferences finds if there are any Reference field(s) present
@@ -36,17 +38,54 @@ func (rm *resourceManager) ResolveReferences(
 	apiReader client.Reader,
 	res acktypes.AWSResource,
 ) (acktypes.AWSResource, error) {
-	return res, nil
+	namespace := res.MetaObject().GetNamespace()
+	ko := rm.concreteResource(res).ko.DeepCopy()
+	// Resolving the extension references may clear them, so check for
+	// references before resolving them.
+	hasReferences := hasNonNilReferences(ko)
+	err := validateReferenceFields(ko)
+	if err == nil {
+		err = resolveReferenceForWorkspaceID(ctx, apiReader, namespace, ko)
+	}
+	if err == nil {
+		err = rm.resolveExtensionReferences(ctx, apiReader, namespace, ko)
+	}
+
+	if hasReferences {
+		return ackcondition.WithReferencesResolvedCondition(&resource{ko}, err)
+	}
+	return &resource{ko}, err
 }
 
 // validateReferenceFields validates the reference field and corresponding
 // identifier field.
 func validateReferenceFields(ko *svcapitypes.AlertManagerDefinition) error {
+	if err := workspaceref.Validate(ko.Spec.WorkspaceID, ko.Spec.WorkspaceRef); err != nil {
+		return err
+	}
 	return nil
 }
 
 // hasNonNilReferences returns true if resource contains a reference to another
 // resource
 func hasNonNilReferences(ko *svcapitypes.AlertManagerDefinition) bool {
-	return false
+	return false || (ko.Spec.WorkspaceRef != nil) || hasNonNilExtensionReferences(ko)
+}
+
+// resolveReferenceForWorkspaceID reads the resource reference, reads the
+// referenced resource and sets the resource's WorkspaceID field
+func resolveReferenceForWorkspaceID(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	ko *svcapitypes.AlertManagerDefinition,
+) error {
+	workspaceID, err := workspaceref.Resolve(ctx, apiReader, namespace, ko.Spec.WorkspaceRef)
+	if err != nil {
+		return err
+	}
+	if workspaceID != nil {
+		ko.Spec.WorkspaceID = workspaceID
+	}
+	return nil
 }
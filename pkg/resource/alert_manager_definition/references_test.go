@@ -0,0 +1,73 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func TestResolveReferences(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ws := &svcapitypes.Workspace{}
+	ws.Namespace, ws.Name = "monitoring", "main"
+	ws.Status.WorkspaceID = aws.String("ws-1")
+	ws.Status.Conditions = []*ackv1alpha1.Condition{{
+		Type:   ackv1alpha1.ConditionTypeResourceSynced,
+		Status: corev1.ConditionTrue,
+	}}
+	apiReader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).Build()
+	rm := &resourceManager{rr: &fakeReconciler{secrets: map[string]string{
+		"apps/alertmanager/config.yaml": secretConfiguration,
+	}}}
+
+	ko := &svcapitypes.AlertManagerDefinition{}
+	ko.Namespace, ko.Name = "apps", "amd"
+	ko.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{
+		From: &svcapitypes.ResourceReference{Namespace: aws.String("monitoring"), Name: aws.String("main")},
+	}
+	ko.Spec.ConfigurationSecretRef = secretKeyRef("", "alertmanager", "config.yaml")
+
+	res, err := rm.ResolveReferences(context.TODO(), apiReader, &resource{ko})
+	if err != nil {
+		t.Fatalf("ResolveReferences() error = %v", err)
+	}
+	got := rm.concreteResource(res).ko
+	if aws.StringValue(got.Spec.WorkspaceID) != "ws-1" {
+		t.Errorf("WorkspaceID = %v, want ws-1", aws.StringValue(got.Spec.WorkspaceID))
+	}
+	if aws.StringValue(got.Spec.Configuration) != secretConfiguration {
+		t.Errorf("Configuration = %q, want the Secret value", aws.StringValue(got.Spec.Configuration))
+	}
+	var resolved bool
+	for _, c := range got.Status.Conditions {
+		if c.Type == ackv1alpha1.ConditionTypeReferencesResolved && c.Status == corev1.ConditionTrue {
+			resolved = true
+		}
+	}
+	if !resolved {
+		t.Errorf("ResolveReferences() did not set ReferencesResolved=True")
+	}
+}
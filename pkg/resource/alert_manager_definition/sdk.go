@@ -105,6 +105,20 @@ func (rm *resourceManager) sdkFind(
 
 	}
 
+	// Status changes that the state machine does not expect are logged.
+	observeAlertManagerDefinitionTransition(ctx, r, ko)
+	// An alert manager definition that AMP leaves CREATING, UPDATING or
+	// DELETING for too long is reported as stalled and reconciled less often.
+	setAlertManagerDefinitionOperationStartTime(r, ko)
+	if err := rm.ensureAlertManagerDefinitionNotStalled(ko); err != nil {
+		return &resource{ko}, err
+	}
+
+	// The hash is only recorded for configurations read from a Secret.
+	if ko.Spec.ConfigurationSecretRef == nil {
+		ko.Status.ConfigurationHash = nil
+	}
+
 	// When adding an invalid alert manager configuration, the AMP API has different behaviour
 	// for different kinds of invalid input. For some invalid input, the API returns an error (e.g. ValidationException)
 	// instantly in the http response and we set the controller to terminal state. The specified
@@ -124,6 +138,8 @@ func (rm *resourceManager) sdkFind(
 	// Hence, we should treat the asynchronous validation errors similarly to how the regular http validation
 	// exceptions are treated in ACK. So when there is a failed creation/update, we don't change the configuration in the spec
 	// that caused this failed status, and also set to terminal error.
+	// The validating webhook in webhook.go rejects most of these configurations at
+	// admission time, but it is optional, so the failed statuses still need handling here.
 
 	// When a failed status occurs, we skip setting the configuration field to be what the API returns,
 	// and instead keep it to be what the user desires. We only do this once right after a resource becomes
@@ -220,6 +236,9 @@ func (rm *resourceManager) sdkCreate(
 	defer func() {
 		exit(err)
 	}()
+	if notReady, err := rm.ensureWorkspaceActive(ctx, desired); err != nil {
+		return notReady, err
+	}
 	input, err := rm.newCreateRequestPayload(ctx, desired)
 	if err != nil {
 		return nil, err
@@ -256,6 +275,13 @@ func (rm *resourceManager) sdkCreate(
 
 	rm.setStatusDefaults(ko)
 
+	// Only the hash of a configuration read from a Secret is recorded, the
+	// configuration itself is never written back to the resource.
+	setConfigurationHash(ko)
+
+	// Record when the alert manager definition started being created, to
+	// report it as stalled if it stays CREATING for too long.
+	setAlertManagerDefinitionOperationStartTime(desired, ko)
 	// We expect the workspace to be in 'creating' status since we just
 	// issued the call to create it, but I suppose it doesn't hurt to check
 	// here.
@@ -305,15 +331,12 @@ func (rm *resourceManager) sdkDelete(
 	defer func() {
 		exit(err)
 	}()
-	// Can't delete alert manager definition in non-(ACTIVE/CREATION_FAILED/UPDATE_FAILED) state
-	// Otherwise, API will return a 409 and ConflictException
-	if !alertManagerDefinitionStatusFailed(r) && !alertManagerDefinitionActive(r) {
-		msg := "Cannot delete alert manager definition as the status is not ACTIVE/CREATION_FAILED/UPDATE_FAILED, current status=" + string(*r.ko.Status.StatusCode)
+	// Can't delete an alert manager definition that is being created, updated
+	// or deleted. Otherwise, API will return a 409 and ConflictException
+	if err = requeueWhileAlertManagerDefinitionModifying(r); err != nil {
+		msg := err.Error()
 		ackcondition.SetSynced(r, corev1.ConditionFalse, &msg, nil)
-		return r, ackrequeue.NeededAfter(
-			errors.New(msg),
-			ackrequeue.DefaultRequeueAfterDuration,
-		)
+		return r, err
 	}
 	input, err := rm.newDeleteRequestPayload(r)
 	if err != nil {
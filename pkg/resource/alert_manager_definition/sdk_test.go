@@ -0,0 +1,435 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspacestatus"
+)
+
+const (
+	testConfiguration    = "alertmanager_config: |\n  route:\n    receiver: default\n"
+	testNewConfiguration = "alertmanager_config: |\n  route:\n    receiver: other\n"
+	// testInvalidConfiguration is rejected by the asynchronous validation
+	// of the fake AMP API.
+	testInvalidConfiguration = "alertmanager_config: |\n  route:\n    receiver: missing\n"
+)
+
+// newTestAPI returns a fake AMP API rejecting testInvalidConfiguration.
+func newTestAPI() *ampfake.API {
+	api := ampfake.New(ampfake.TestAccountID, ampfake.TestRegion)
+	api.ValidateAlertManagerDefinition = func(data []byte) error {
+		if string(data) == testInvalidConfiguration {
+			return errors.New(`error validating Alertmanager config: undefined receiver "missing" used in route`)
+		}
+		return nil
+	}
+	return api
+}
+
+// newTestResourceManager returns a resource manager calling the supplied fake
+// AMP API.
+func newTestResourceManager(api svcsdkapi.PrometheusServiceAPI) *resourceManager {
+	return &resourceManager{sdkapi: api, metrics: ampfake.TestMetrics, awsAccountID: ampfake.TestAccountID, awsRegion: ampfake.TestRegion}
+}
+
+// newTestAlertManagerDefinition returns an alert manager definition with the
+// supplied configuration in the supplied workspace.
+func newTestAlertManagerDefinition(workspaceID string, configuration string) *resource {
+	ko := &svcapitypes.AlertManagerDefinition{}
+	ko.Spec.WorkspaceID = aws.String(workspaceID)
+	ko.Spec.Configuration = aws.String(configuration)
+	return &resource{ko}
+}
+
+func Test_sdkFind(t *testing.T) {
+	tests := []struct {
+		name string
+		// transitions is the number of descriptions before the creation of
+		// the alert manager definition completes.
+		transitions       int
+		configuration     string
+		missingWorkspace  bool
+		wantNotFound      bool
+		wantStatus        string
+		wantConfiguration *string
+		wantTerminal      bool
+	}{
+		{
+			name:             "not created",
+			missingWorkspace: true,
+			wantNotFound:     true,
+		},
+		{
+			name:         "no alert manager definition in the workspace",
+			wantNotFound: true,
+		},
+		{
+			name:          "creating",
+			transitions:   1,
+			configuration: testConfiguration,
+			wantStatus:    svcsdk.AlertManagerDefinitionStatusCodeCreating,
+		},
+		{
+			name:              "active",
+			configuration:     testConfiguration,
+			wantStatus:        svcsdk.AlertManagerDefinitionStatusCodeActive,
+			wantConfiguration: aws.String(testConfiguration),
+		},
+		{
+			name:              "creation failed",
+			configuration:     testInvalidConfiguration,
+			wantStatus:        svcsdk.AlertManagerDefinitionStatusCodeCreationFailed,
+			wantConfiguration: aws.String(testInvalidConfiguration),
+			wantTerminal:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			workspaceID := ampfake.NewActiveWorkspace(t, api)
+			desired := newTestAlertManagerDefinition(workspaceID, tt.configuration)
+			if tt.configuration != "" {
+				created, err := rm.sdkCreate(context.TODO(), desired)
+				if err != nil {
+					t.Fatalf("sdkCreate() error = %v", err)
+				}
+				desired = created
+			}
+			if tt.missingWorkspace {
+				desired.ko.Spec.WorkspaceID = nil
+			}
+			api.Transitions = tt.transitions
+
+			latest, err := rm.sdkFind(context.TODO(), desired)
+			if tt.wantNotFound {
+				if err != ackerr.NotFound {
+					t.Errorf("sdkFind() error = %v, want NotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdkFind() error = %v", err)
+			}
+			if got := *latest.ko.Status.StatusCode; got != tt.wantStatus {
+				t.Errorf("sdkFind() status = %s, want %s", got, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(latest.ko.Spec.Configuration, tt.wantConfiguration) {
+				t.Errorf("sdkFind() configuration = %q, want %q",
+					aws.StringValue(latest.ko.Spec.Configuration), aws.StringValue(tt.wantConfiguration))
+			}
+			terminal := ackcondition.Terminal(latest)
+			if tt.wantTerminal != (terminal != nil && terminal.Status == corev1.ConditionTrue) {
+				t.Errorf("sdkFind() terminal condition = %v, want %v", terminal, tt.wantTerminal)
+			}
+		})
+	}
+}
+
+func Test_sdkCreate(t *testing.T) {
+	tests := []struct {
+		name string
+		// workspaceActive is false if the workspace is still being created.
+		workspaceActive bool
+		// exists is true if the alert manager definition already exists.
+		exists       bool
+		wantCalls    []string
+		wantNotReady bool
+		wantErrCode  string
+	}{
+		{
+			name:            "workspace active",
+			workspaceActive: true,
+			wantCalls:       []string{"CreateAlertManagerDefinition"},
+		},
+		{
+			name:         "workspace creating",
+			wantCalls:    []string{"DescribeWorkspace"},
+			wantNotReady: true,
+		},
+		{
+			name:            "already exists",
+			workspaceActive: true,
+			exists:          true,
+			wantCalls:       []string{"CreateAlertManagerDefinition"},
+			wantErrCode:     svcsdk.ErrCodeConflictException,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			if !tt.workspaceActive {
+				api.Transitions = 1
+			}
+			workspaceID := ampfake.NewWorkspace(t, api)
+			if tt.workspaceActive {
+				api.Settle()
+				// The status of the workspace is described once and cached.
+				if err := workspacestatus.EnsureActive(
+					context.TODO(), api, nil,
+					workspacestatus.For(rm.awsAccountID, rm.awsRegion), workspaceID,
+				); err != nil {
+					t.Fatalf("EnsureActive() error = %v", err)
+				}
+			}
+			if tt.exists {
+				ampfake.CreateAndSettle(t, api, newTestResourceManager(api), newTestAlertManagerDefinition(workspaceID, testConfiguration))
+			}
+
+			calls := len(api.Calls())
+			created, err := rm.sdkCreate(context.TODO(), newTestAlertManagerDefinition(workspaceID, testConfiguration))
+			if got := api.Calls()[calls:]; strings.Join(got, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("sdkCreate() calls = %v, want %v", got, tt.wantCalls)
+			}
+			switch {
+			case tt.wantNotReady:
+				var notReady *workspacestatus.NotReadyError
+				if !errors.As(err, &notReady) {
+					t.Fatalf("sdkCreate() error = %v, want a NotReadyError", err)
+				}
+				if condition := ackcondition.FirstOfType(created, workspacestatus.ConditionTypeWorkspaceNotReady); condition == nil {
+					t.Errorf("sdkCreate() did not set the WorkspaceNotReady condition")
+				}
+				return
+			case tt.wantErrCode != "":
+				if code := ampfake.ErrorCode(err); code != tt.wantErrCode {
+					t.Errorf("sdkCreate() error = %v, want a %s", err, tt.wantErrCode)
+				}
+				return
+			case err != nil:
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if got := *created.ko.Status.StatusCode; got != svcsdk.AlertManagerDefinitionStatusCodeCreating {
+				t.Errorf("sdkCreate() status = %s, want CREATING", got)
+			}
+			if synced := ackcondition.Synced(created); synced == nil || synced.Status != corev1.ConditionFalse {
+				t.Errorf("sdkCreate() synced condition = %v, want False", synced)
+			}
+			if created.ko.Status.OperationStartTime == nil {
+				t.Errorf("sdkCreate() did not record the operation start time")
+			}
+		})
+	}
+}
+
+func Test_sdkUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		// configuration is the configuration the alert manager definition
+		// is created with.
+		configuration string
+		// busy is true if the alert manager definition is updated while an
+		// update is still ongoing.
+		busy              bool
+		desired           string
+		wantCalls         []string
+		wantBusy          bool
+		wantStatus        string
+		wantConfiguration string
+	}{
+		{
+			name:              "configuration",
+			configuration:     testConfiguration,
+			desired:           testNewConfiguration,
+			wantCalls:         []string{"PutAlertManagerDefinition"},
+			wantStatus:        svcsdk.AlertManagerDefinitionStatusCodeActive,
+			wantConfiguration: testNewConfiguration,
+		},
+		{
+			name:              "reformatted configuration",
+			configuration:     testConfiguration,
+			desired:           "alertmanager_config: |\n  route: {receiver: default}\n",
+			wantStatus:        svcsdk.AlertManagerDefinitionStatusCodeActive,
+			wantConfiguration: testConfiguration,
+		},
+		{
+			name:              "invalid configuration",
+			configuration:     testConfiguration,
+			desired:           testInvalidConfiguration,
+			wantCalls:         []string{"PutAlertManagerDefinition"},
+			wantStatus:        svcsdk.AlertManagerDefinitionStatusCodeUpdateFailed,
+			wantConfiguration: testConfiguration,
+		},
+		{
+			name:              "fixed creation",
+			configuration:     testInvalidConfiguration,
+			desired:           testConfiguration,
+			wantCalls:         []string{"PutAlertManagerDefinition"},
+			wantStatus:        svcsdk.AlertManagerDefinitionStatusCodeActive,
+			wantConfiguration: testConfiguration,
+		},
+		{
+			name:              "updating",
+			configuration:     testConfiguration,
+			busy:              true,
+			desired:           testConfiguration,
+			wantBusy:          true,
+			wantStatus:        svcsdk.AlertManagerDefinitionStatusCodeActive,
+			wantConfiguration: testNewConfiguration,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			workspaceID := ampfake.NewActiveWorkspace(t, api)
+			latest := ampfake.CreateAndSettle(t, api, rm, newTestAlertManagerDefinition(workspaceID, tt.configuration)).(*resource)
+			if tt.busy {
+				updating := &resource{latest.ko.DeepCopy()}
+				updating.ko.Spec.Configuration = aws.String(testNewConfiguration)
+				var err error
+				api.Transitions = 1
+				if latest, err = rm.sdkUpdate(context.TODO(), updating, latest, newResourceDelta(updating, latest)); err != nil {
+					t.Fatalf("sdkUpdate() error = %v", err)
+				}
+			}
+
+			desired := &resource{latest.ko.DeepCopy()}
+			desired.ko.Spec.Configuration = aws.String(tt.desired)
+			calls := len(api.Calls())
+			updated, err := rm.sdkUpdate(context.TODO(), desired, latest, newResourceDelta(desired, latest))
+			if got := api.Calls()[calls:]; strings.Join(got, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("sdkUpdate() calls = %v, want %v", got, tt.wantCalls)
+			}
+			if tt.wantBusy {
+				var requeue *ackrequeue.RequeueNeededAfter
+				if !errors.As(err, &requeue) {
+					t.Errorf("sdkUpdate() error = %v, want a requeue while busy", err)
+				}
+			} else if err != nil {
+				t.Fatalf("sdkUpdate() error = %v", err)
+			} else if len(tt.wantCalls) > 0 {
+				if got := *updated.ko.Status.StatusCode; got != svcsdk.AlertManagerDefinitionStatusCodeUpdating {
+					t.Errorf("sdkUpdate() status = %s, want UPDATING", got)
+				}
+				if synced := ackcondition.Synced(updated); synced == nil || synced.Status != corev1.ConditionFalse {
+					t.Errorf("sdkUpdate() synced condition = %v, want False", synced)
+				}
+			}
+
+			api.Settle()
+			resp, err := api.DescribeAlertManagerDefinitionWithContext(context.TODO(), &svcsdk.DescribeAlertManagerDefinitionInput{
+				WorkspaceId: aws.String(workspaceID),
+			})
+			if err != nil {
+				t.Fatalf("DescribeAlertManagerDefinition() error = %v", err)
+			}
+			if got := *resp.AlertManagerDefinition.Status.StatusCode; got != tt.wantStatus {
+				t.Errorf("alert manager definition status = %s, want %s", got, tt.wantStatus)
+			}
+			if got := string(resp.AlertManagerDefinition.Data); got != tt.wantConfiguration {
+				t.Errorf("alert manager definition data = %q, want %q", got, tt.wantConfiguration)
+			}
+		})
+	}
+}
+
+func Test_sdkDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		// transitions is the number of descriptions before an operation
+		// completes. The alert manager definition is deleted while its
+		// creation is still ongoing if it is not zero.
+		transitions int
+		// deleted is true if the alert manager definition was already
+		// deleted.
+		deleted     bool
+		wantCalls   []string
+		wantRequeue bool
+		wantErrCode string
+		wantDeleted bool
+	}{
+		{
+			name:        "active",
+			wantCalls:   []string{"DeleteAlertManagerDefinition"},
+			wantDeleted: true,
+		},
+		{
+			name:        "creating",
+			transitions: 2,
+			wantRequeue: true,
+		},
+		{
+			name:        "already deleted",
+			deleted:     true,
+			wantCalls:   []string{"DeleteAlertManagerDefinition"},
+			wantErrCode: svcsdk.ErrCodeResourceNotFoundException,
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			workspaceID := ampfake.NewActiveWorkspace(t, api)
+			latest, err := rm.sdkCreate(context.TODO(), newTestAlertManagerDefinition(workspaceID, testConfiguration))
+			if err != nil {
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if tt.transitions == 0 {
+				api.Settle()
+			}
+			api.Transitions = tt.transitions
+			if latest, err = rm.sdkFind(context.TODO(), latest); err != nil {
+				t.Fatalf("sdkFind() error = %v", err)
+			}
+			if tt.deleted {
+				if _, err := rm.sdkDelete(context.TODO(), latest); err != nil {
+					t.Fatalf("sdkDelete() error = %v", err)
+				}
+				api.Settle()
+			}
+
+			calls := len(api.Calls())
+			deleting, err := rm.sdkDelete(context.TODO(), latest)
+			if got := api.Calls()[calls:]; strings.Join(got, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("sdkDelete() calls = %v, want %v", got, tt.wantCalls)
+			}
+			if tt.wantRequeue {
+				var requeue *ackrequeue.RequeueNeededAfter
+				if !errors.As(err, &requeue) {
+					t.Errorf("sdkDelete() error = %v, want a requeue", err)
+				}
+				if synced := ackcondition.Synced(deleting); synced == nil || synced.Status != corev1.ConditionFalse {
+					t.Errorf("sdkDelete() synced condition = %v, want False", synced)
+				}
+			} else if code := ampfake.ErrorCode(err); code != tt.wantErrCode || (tt.wantErrCode == "" && err != nil) {
+				t.Errorf("sdkDelete() error = %v, want %q", err, tt.wantErrCode)
+			}
+
+			api.Settle()
+			_, err = rm.sdkFind(context.TODO(), latest)
+			if deleted := err == ackerr.NotFound; deleted != tt.wantDeleted {
+				t.Errorf("sdkFind() error = %v after the deletion, want deleted %v", err, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	ackutil "github.com/aws-controllers-k8s/runtime/pkg/util"
+	amconfig "github.com/prometheus/alertmanager/config"
+	"gopkg.in/yaml.v3"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/yamlvalidation"
+)
+
+// configErrorLineRE matches the line of the YAML errors of the Alertmanager
+// configuration.
+var configErrorLineRE = regexp.MustCompile(`^\s*line (\d+):`)
+
+// supportedReceiverTypes are the Alertmanager receiver integrations that
+// AMP supports.
+var supportedReceiverTypes = []string{
+	"sns_configs",
+}
+
+// unsupportedReceiverTypes are the Alertmanager receiver integrations that
+// AMP rejects.
+var unsupportedReceiverTypes = []string{
+	"discord_configs",
+	"email_configs",
+	"msteams_configs",
+	"opsgenie_configs",
+	"pagerduty_configs",
+	"pushover_configs",
+	"slack_configs",
+	"telegram_configs",
+	"victorops_configs",
+	"webex_configs",
+	"webhook_configs",
+	"wechat_configs",
+}
+
+// validateAlertManagerDefinition validates the Alertmanager configuration of
+// the supplied resource locally, so that configurations AMP would move to
+// CREATION_FAILED or UPDATE_FAILED are rejected before they reach AMP.
+func validateAlertManagerDefinition(
+	ko *svcapitypes.AlertManagerDefinition,
+) field.ErrorList {
+	specPath := field.NewPath("spec")
+	switch err := validateConfigurationFields(ko); {
+	case err == ErrConfigurationOrSecretRefMissing:
+		return field.ErrorList{field.Required(specPath.Child("configuration"), err.Error())}
+	case err != nil:
+		return field.ErrorList{field.Forbidden(specPath, err.Error())}
+	}
+	var errs field.ErrorList
+	if sel := ko.Spec.AlertmanagerConfigSelector; sel != nil {
+		errs = append(errs, metav1validation.ValidateLabelSelector(sel, specPath.Child("alertmanagerConfigSelector"))...)
+	}
+	if sel := ko.Spec.AlertmanagerConfigNamespaceSelector; sel != nil {
+		errs = append(errs, metav1validation.ValidateLabelSelector(sel, specPath.Child("alertmanagerConfigNamespaceSelector"))...)
+	}
+	// A configuration read from a Secret is validated by AMP, it may change
+	// after admission and must not be echoed in error messages.
+	if ref := ko.Spec.ConfigurationSecretRef; ref != nil {
+		refPath := specPath.Child("configurationSecretRef")
+		if ref.Name == "" {
+			errs = append(errs, field.Required(refPath.Child("name"), "Secret name must not be empty"))
+		}
+		if ref.Key == "" {
+			errs = append(errs, field.Required(refPath.Child("key"), "Secret key must not be empty"))
+		}
+		return errs
+	}
+	return append(errs, validateAlertManagerConfiguration(specPath.Child("configuration"), *ko.Spec.Configuration)...)
+}
+
+// validateAlertManagerConfiguration validates an alert manager definition in
+// the AMP format, a YAML document with the Alertmanager configuration in
+// alertmanager_config and optional templates in template_files. The
+// Alertmanager configuration is loaded by Alertmanager itself, on top of
+// which the receivers AMP does not support are rejected.
+func validateAlertManagerConfiguration(
+	path *field.Path,
+	configuration string,
+) field.ErrorList {
+	v := yamlvalidation.New(path, configuration)
+	root, ok := v.Parse(configuration, 0)
+	if !ok {
+		return v.Errors()
+	}
+	if root == nil {
+		v.Errorf(1, "field \"alertmanager_config\" must be set")
+		return v.Errors()
+	}
+	fields := v.Mapping(root, "alert manager definition", "alertmanager_config", "template_files")
+	if fields == nil {
+		return v.Errors()
+	}
+	if templates := fields["template_files"]; templates != nil {
+		if files := v.Mapping(templates, "template_files"); files != nil {
+			for name, content := range files {
+				v.Scalar(content, "template_files."+name)
+			}
+		}
+	}
+	config, ok := v.Scalar(fields["alertmanager_config"], "alertmanager_config")
+	if !ok {
+		if fields["alertmanager_config"] == nil {
+			v.Errorf(root.Line, "field \"alertmanager_config\" must be set")
+		}
+		return v.Errors()
+	}
+	// The Alertmanager configuration is a YAML document nested in a string,
+	// parse it with its lines relative to the whole definition.
+	line, _ := v.Position(fields["alertmanager_config"], 1, 1)
+	amRoot, ok := v.Parse(config, line-1)
+	switch {
+	case !ok:
+		return v.Errors()
+	case amRoot == nil:
+		v.Errorf(line, "alertmanager_config: field \"route\" must be set")
+		return v.Errors()
+	}
+	// Alertmanager would report the receivers AMP does not support as valid,
+	// or as unknown fields, so report them first.
+	if validateReceiverTypes(v, amRoot) {
+		if _, err := amconfig.Load(config); err != nil {
+			errLine, msg := configErrorPosition(err)
+			if errLine == 0 {
+				errLine = fields["alertmanager_config"].Line
+			} else {
+				errLine += line - 1
+			}
+			v.Errorf(errLine, "alertmanager_config: %s", msg)
+		}
+	}
+	return v.Errors()
+}
+
+// validateReceiverTypes reports the receivers of the supplied Alertmanager
+// configuration that use integrations AMP does not support, and returns true
+// if there is none.
+func validateReceiverTypes(v *yamlvalidation.Validator, root *yaml.Node) bool {
+	receivers := mappingValue(root, "receivers")
+	if receivers == nil || receivers.Kind != yaml.SequenceNode {
+		return true
+	}
+	valid := true
+	for i, r := range receivers.Content {
+		if r.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(r.Content); j += 2 {
+			key := r.Content[j]
+			if ackutil.InStrings(key.Value, unsupportedReceiverTypes) {
+				v.Errorf(key.Line, "receivers[%d]: receiver type %q is not supported by AMP, supported types are %s",
+					i, key.Value, strings.Join(supportedReceiverTypes, ", "))
+				valid = false
+			}
+		}
+	}
+	return valid
+}
+
+// mappingValue returns the value of the supplied key in the supplied mapping
+// node, or nil if it is not a mapping or has no such key.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// configErrorPosition returns the line within the Alertmanager configuration
+// of the supplied error returned by Alertmanager, or 0 if it has none, and the
+// error message without the line. Only the YAML errors have a line.
+func configErrorPosition(err error) (int, string) {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	msg = strings.TrimPrefix(msg, "unmarshal errors:\n")
+	m := configErrorLineRE.FindStringSubmatch(msg)
+	if m == nil {
+		return 0, msg
+	}
+	line, _ := strconv.Atoi(m[1])
+	return line, strings.TrimSpace(msg[len(m[0]):])
+}
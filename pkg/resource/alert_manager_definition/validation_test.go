@@ -0,0 +1,160 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func Test_validateAlertManagerConfiguration(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration string
+		wantErrs      []string
+	}{
+		{
+			name: "valid",
+			configuration: `template_files:
+  default_template: |
+    {{ define "sns.default.message" }}{{ .CommonAnnotations.summary }}{{ end }}
+alertmanager_config: |
+  route:
+    receiver: default
+    group_by: [alertname, job]
+    group_wait: 30s
+    routes:
+    - receiver: critical
+      matchers:
+      - severity="critical"
+      - team=~"infra|platform"
+      continue: true
+      mute_time_intervals: [weekends]
+  receivers:
+  - name: default
+    sns_configs:
+    - topic_arn: arn:aws:sns:us-west-2:123456789012:default
+      sigv4:
+        region: us-west-2
+      attributes:
+        key: value
+  - name: critical
+    sns_configs:
+    - topic_arn: arn:aws:sns:us-west-2:123456789012:critical
+  time_intervals:
+  - name: weekends
+    time_intervals:
+    - weekdays: [saturday, sunday]
+  inhibit_rules:
+  - source_matchers: [severity="critical"]
+    target_matchers: [severity="warning"]
+    equal: [alertname]
+`,
+		},
+		{
+			name:          "missing alertmanager_config",
+			configuration: "template_files: {}\n",
+			wantErrs:      []string{`line 1: field "alertmanager_config" must be set`},
+		},
+		{
+			name:          "unknown wrapper field",
+			configuration: "alertmanager_config: |\n  route:\n    receiver: default\n  receivers:\n  - name: default\nconfig: x\n",
+			wantErrs:      []string{`line 6: field "config" is not valid in alert manager definition`},
+		},
+		{
+			name:          "invalid nested YAML",
+			configuration: "alertmanager_config: |\n  route:\n    receiver: [\n",
+			wantErrs:      []string{"line 3: invalid YAML"},
+		},
+		{
+			name: "missing route",
+			configuration: `alertmanager_config: |
+  receivers:
+  - name: default
+`,
+			wantErrs: []string{`line 1: alertmanager_config: no routes provided`},
+		},
+		{
+			name: "unknown field",
+			configuration: `alertmanager_config: |
+  route:
+    receiver: default
+    group: [alertname]
+  receivers:
+  - name: default
+`,
+			wantErrs: []string{`line 4: alertmanager_config: field group not found`},
+		},
+		{
+			name: "undefined receiver",
+			configuration: `alertmanager_config: |
+  route:
+    receiver: default
+    routes:
+    - receiver: missing
+  receivers:
+  - name: default
+`,
+			wantErrs: []string{`line 1: alertmanager_config: undefined receiver "missing" used in route`},
+		},
+		{
+			name: "SNS receiver without target",
+			configuration: `alertmanager_config: |
+  route:
+    receiver: default
+  receivers:
+  - name: default
+    sns_configs:
+    - subject: no target
+`,
+			wantErrs: []string{`line 1: alertmanager_config: must provide either a Target ARN, Topic ARN, or Phone Number for SNS config`},
+		},
+		{
+			name: "unsupported receivers",
+			configuration: `alertmanager_config: |
+  route:
+    receiver: default
+  receivers:
+  - name: default
+    sns_configs:
+    - topic_arn: arn:aws:sns:us-west-2:123456789012:default
+  - name: slack
+    slack_configs:
+    - channel: '#alerts'
+  - name: discord
+    discord_configs:
+    - webhook_url: https://discord.example.com
+`,
+			wantErrs: []string{
+				`line 9: receivers[1]: receiver type "slack_configs" is not supported by AMP`,
+				`line 12: receivers[2]: receiver type "discord_configs" is not supported by AMP`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateAlertManagerConfiguration(field.NewPath("spec", "configuration"), tt.configuration)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("validateAlertManagerConfiguration() = %v, want %d errors", errs, len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if !strings.HasPrefix(errs[i].Detail, want) {
+					t.Errorf("validateAlertManagerConfiguration() error %d = %q, want %q", i, errs[i].Detail, want)
+				}
+			}
+		})
+	}
+}
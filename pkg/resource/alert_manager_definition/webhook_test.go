@@ -0,0 +1,93 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func Test_validator(t *testing.T) {
+	valid := &svcapitypes.AlertManagerDefinition{
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			Configuration: aws.String("alertmanager_config: |\n  route:\n    receiver: default\n  receivers:\n  - name: default\n"),
+		},
+	}
+	invalid := &svcapitypes.AlertManagerDefinition{
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			Configuration: aws.String("alertmanager_config: |\n  route:\n    receiver: undefined\n  receivers:\n  - name: default\n"),
+		},
+	}
+	deleting := invalid.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{}
+	relabelled := invalid.DeepCopy()
+	relabelled.Labels = map[string]string{"k": "v"}
+
+	ctx := context.TODO()
+	v := &validator{}
+	if err := v.ValidateCreate(ctx, valid); err != nil {
+		t.Errorf("ValidateCreate() unexpected error = %v", err)
+	}
+	if err := v.ValidateCreate(ctx, invalid); err == nil {
+		t.Errorf("ValidateCreate() expected an error")
+	}
+	if err := v.ValidateUpdate(ctx, valid, invalid); err == nil {
+		t.Errorf("ValidateUpdate() expected an error")
+	}
+	// Metadata only updates of invalid resources, such as removing the
+	// finalizer, are accepted.
+	if err := v.ValidateUpdate(ctx, invalid, relabelled); err != nil {
+		t.Errorf("ValidateUpdate() unexpected error = %v", err)
+	}
+	if err := v.ValidateUpdate(ctx, valid, deleting); err != nil {
+		t.Errorf("ValidateUpdate() unexpected error = %v", err)
+	}
+
+	// Configurations read from a Secret are not available at admission
+	// time, only the reference is validated.
+	fromSecret := &svcapitypes.AlertManagerDefinition{
+		Spec: svcapitypes.AlertManagerDefinitionSpec{
+			ConfigurationSecretRef: secretKeyRef("", "alertmanager", "config.yaml"),
+		},
+	}
+	if err := v.ValidateCreate(ctx, fromSecret); err != nil {
+		t.Errorf("ValidateCreate() unexpected error = %v", err)
+	}
+	withoutKey := fromSecret.DeepCopy()
+	withoutKey.Spec.ConfigurationSecretRef.Key = ""
+	if err := v.ValidateCreate(ctx, withoutKey); err == nil {
+		t.Errorf("ValidateCreate() expected an error")
+	}
+	both := fromSecret.DeepCopy()
+	both.Spec.Configuration = valid.Spec.Configuration
+	if err := v.ValidateCreate(ctx, both); err == nil {
+		t.Errorf("ValidateCreate() expected an error")
+	}
+	if err := v.ValidateCreate(ctx, &svcapitypes.AlertManagerDefinition{}); err == nil {
+		t.Errorf("ValidateCreate() expected an error")
+	}
+
+	invalidSelector := valid.DeepCopy()
+	invalidSelector.Spec.AlertmanagerConfigSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "amp", Operator: "Matches"}},
+	}
+	if err := v.ValidateCreate(ctx, invalidSelector); err == nil {
+		t.Errorf("ValidateCreate() expected an error")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package alert_manager_definition
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspaceowner"
+)
+
+// workspaceOwnerKind describes the AlertManagerDefinition resources to the controller
+// adding owner references to the Workspace resources they are created in.
+var workspaceOwnerKind = workspaceowner.Kind{
+	Name: "AlertManagerDefinition",
+	NewObject: func() client.Object {
+		return &svcapitypes.AlertManagerDefinition{}
+	},
+	NewList: func() client.ObjectList {
+		return &svcapitypes.AlertManagerDefinitionList{}
+	},
+	Workspace: func(obj client.Object) (*string, *svcapitypes.ResourceReferenceWrapper) {
+		ko := obj.(*svcapitypes.AlertManagerDefinition)
+		return ko.Spec.WorkspaceID, ko.Spec.WorkspaceRef
+	},
+}
+
+func init() {
+	svcresource.RegisterController(workspaceOwnerKind.Setup)
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logging_configuration
+
+import (
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+	ampfaults "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/faults"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/faults/faultstest"
+)
+
+func Test_faults(t *testing.T) {
+	faultstest.Run(t, faultstest.Kind{
+		NewAPI: newTestAPI,
+		NewResourceManager: func(api svcsdkapi.PrometheusServiceAPI) acktypes.AWSResourceManager {
+			return newTestResourceManager(api)
+		},
+		Descriptor: &resourceDescriptor{},
+		NewResource: func(t testing.TB, api *ampfake.API) acktypes.AWSResource {
+			return newTestLoggingConfiguration(ampfake.NewActiveWorkspace(t, api), testLogGroupARN)
+		},
+		Update: func(desired acktypes.AWSResource) {
+			desired.(*resource).ko.Spec.LogGroupARN = aws.String(testNewLogGroupARN)
+		},
+		StatusCode: func(res acktypes.AWSResource) *string {
+			return res.(*resource).ko.Status.StatusCode
+		},
+	}, []faultstest.Case{
+		{
+			Name:      "creation throttled",
+			Operation: "Create",
+			Faults: []ampfaults.Fault{
+				{Operation: "CreateLoggingConfiguration", Probability: 1, ErrorCode: svcsdk.ErrCodeThrottlingException},
+			},
+			WantErrCode:   svcsdk.ErrCodeThrottlingException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "creation over quota",
+			Operation: "Create",
+			Faults: []ampfaults.Fault{
+				{Operation: "CreateLoggingConfiguration", Probability: 1, ErrorCode: svcsdk.ErrCodeServiceQuotaExceededException},
+			},
+			WantErrCode:   svcsdk.ErrCodeServiceQuotaExceededException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "creation rejected",
+			Operation: "Create",
+			Faults: []ampfaults.Fault{
+				{Operation: "CreateLoggingConfiguration", Probability: 1, ErrorCode: svcsdk.ErrCodeValidationException},
+			},
+			WantErrCode:   svcsdk.ErrCodeValidationException,
+			WantCondition: ackv1alpha1.ConditionTypeTerminal,
+		},
+		{
+			Name:      "update timed out",
+			Operation: "Update",
+			Faults: []ampfaults.Fault{
+				{Operation: "UpdateLoggingConfiguration", Probability: 1, Latency: time.Hour},
+			},
+			Timeout:       10 * time.Millisecond,
+			WantErrCode:   request.CanceledErrorCode,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "deletion conflicting",
+			Operation: "Delete",
+			Faults: []ampfaults.Fault{
+				{Operation: "DeleteLoggingConfiguration", Probability: 1, ErrorCode: svcsdk.ErrCodeConflictException},
+			},
+			WantErrCode:   svcsdk.ErrCodeConflictException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+	})
+}
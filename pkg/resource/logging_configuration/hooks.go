@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logging_configuration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+)
+
+// loggingConfigurationHasStatus returns true if the supplied logging
+// configuration has the given status code
+func loggingConfigurationHasStatus(
+	r *resource,
+	code svcapitypes.LoggingConfigurationStatusCode,
+) bool {
+	return ampstatus.LoggingConfiguration.Is(r.ko.Status.StatusCode, ampstatus.Code(code))
+}
+
+// loggingConfigurationCreating returns true if the supplied logging
+// configuration is in the process of being created
+func loggingConfigurationCreating(r *resource) bool {
+	return loggingConfigurationHasStatus(r, svcapitypes.LoggingConfigurationStatusCode_CREATING)
+}
+
+// loggingConfigurationUpdating returns true if the supplied logging
+// configuration is in the process of being updated
+func loggingConfigurationUpdating(r *resource) bool {
+	return loggingConfigurationHasStatus(r, svcapitypes.LoggingConfigurationStatusCode_UPDATING)
+}
+
+// loggingConfigurationStatusFailed returns true if the supplied logging
+// configuration has a status of creation failed or update failed
+func loggingConfigurationStatusFailed(r *resource) bool {
+	return ampstatus.LoggingConfiguration.Terminal(r.ko.Status.StatusCode)
+}
+
+// loggingConfigurationLogGroupChanged returns true if the desired log group
+// differs from the one of the latest observed logging configuration
+func loggingConfigurationLogGroupChanged(desired *resource, latest *resource) bool {
+	delta := newResourceDelta(desired, latest)
+	return delta.DifferentAt("Spec.LogGroupARN")
+}
+
+// newLoggingConfigurationFailedError returns a terminal error describing why
+// the supplied logging configuration is in a failed state
+func newLoggingConfigurationFailedError(r *resource) error {
+	msg := fmt.Sprintf("Logging Configuration is in '%s' status", *r.ko.Status.StatusCode)
+	if r.ko.Status.StatusReason != nil {
+		msg += ": " + *r.ko.Status.StatusReason
+	}
+	return ackerr.NewTerminalError(errors.New(msg))
+}
+
+// setLoggingConfigurationOperationStartTime records in the supplied logging configuration
+// the time at which it entered its current CREATING, UPDATING or DELETING
+// status, given the resource it was observed from.
+func setLoggingConfigurationOperationStartTime(r *resource, ko *svcapitypes.LoggingConfiguration) {
+	ko.Status.OperationStartTime = ampstatus.LoggingConfiguration.OperationStartTime(
+		r.ko.Status.StatusCode, r.ko.Status.OperationStartTime, ko.Status.StatusCode,
+	)
+}
+
+// observeLoggingConfigurationTransition logs the supplied logging
+// configuration moving to a status that its state machine does not expect,
+// given the resource it was observed from.
+func observeLoggingConfigurationTransition(ctx context.Context, r *resource, ko *svcapitypes.LoggingConfiguration) {
+	ampstatus.LoggingConfiguration.ObserveTransition(ctx, r.ko.Status.StatusCode, ko.Status.StatusCode)
+}
+
+// ensureLoggingConfigurationNotStalled returns an error requeueing the supplied
+// logging configuration with a Stalled condition if it has been CREATING, UPDATING
+// or DELETING for longer than the configured stalled timeout of logging
+// configurations.
+func (rm *resourceManager) ensureLoggingConfigurationNotStalled(ko *svcapitypes.LoggingConfiguration) error {
+	return ampstatus.LoggingConfiguration.EnsureNotStalled(
+		&resource{ko}, ko.Status.StatusCode, ko.Status.OperationStartTime,
+		svcresource.StalledTimeouts(rm.rr).For(ampstatus.LoggingConfiguration),
+	)
+}
+
+// requeueWhileLoggingConfigurationModifying returns a requeue error if the
+// supplied logging configuration is being created, updated or deleted, since
+// the API rejects modifications in those states. It returns nil otherwise.
+func requeueWhileLoggingConfigurationModifying(r *resource) error {
+	return ampstatus.LoggingConfiguration.RequeueWhileBusy(r.ko.Status.StatusCode)
+}
@@ -0,0 +1,122 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logging_configuration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func newTestResource(statusCode *string, logGroupARN *string) *resource {
+	return &resource{
+		ko: &svcapitypes.LoggingConfiguration{
+			Spec: svcapitypes.LoggingConfigurationSpec{
+				WorkspaceID: aws.String("ws-123"),
+				LogGroupARN: logGroupARN,
+			},
+			Status: svcapitypes.LoggingConfigurationStatus{
+				StatusCode: statusCode,
+			},
+		},
+	}
+}
+
+func Test_requeueWhileLoggingConfigurationModifying(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode *string
+		want       time.Duration
+	}{
+		{
+			name:       "nil status code",
+			statusCode: nil,
+		},
+		{
+			name:       "active",
+			statusCode: aws.String("ACTIVE"),
+		},
+		{
+			name:       "update failed",
+			statusCode: aws.String("UPDATE_FAILED"),
+		},
+		{
+			name:       "creating",
+			statusCode: aws.String("CREATING"),
+			want:       15 * time.Second,
+		},
+		{
+			name:       "updating",
+			statusCode: aws.String("UPDATING"),
+			want:       10 * time.Second,
+		},
+		{
+			name:       "deleting",
+			statusCode: aws.String("DELETING"),
+			want:       10 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestResource(tt.statusCode, nil)
+			err := requeueWhileLoggingConfigurationModifying(r)
+			if tt.want == 0 {
+				if err != nil {
+					t.Errorf("requeueWhileLoggingConfigurationModifying() = %v, want nil", err)
+				}
+				return
+			}
+			var requeue *ackrequeue.RequeueNeededAfter
+			if !errors.As(err, &requeue) || requeue.Duration() != tt.want {
+				t.Errorf("requeueWhileLoggingConfigurationModifying() = %v, want a requeue after %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loggingConfigurationLogGroupChanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired *string
+		latest  *string
+		want    bool
+	}{
+		{
+			name:    "same log group",
+			desired: aws.String("arn:aws:logs:us-west-2:111111111111:log-group:a:*"),
+			latest:  aws.String("arn:aws:logs:us-west-2:111111111111:log-group:a:*"),
+			want:    false,
+		},
+		{
+			name:    "different log group",
+			desired: aws.String("arn:aws:logs:us-west-2:111111111111:log-group:b:*"),
+			latest:  aws.String("arn:aws:logs:us-west-2:111111111111:log-group:a:*"),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestResource(nil, tt.desired)
+			latest := newTestResource(nil, tt.latest)
+			if got := loggingConfigurationLogGroupChanged(desired, latest); got != tt.want {
+				t.Errorf("loggingConfigurationLogGroupChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
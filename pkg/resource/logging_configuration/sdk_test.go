@@ -0,0 +1,421 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logging_configuration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+)
+
+const (
+	testLogGroupARN    = "arn:aws:logs:us-west-2:111111111111:log-group:amp:*"
+	testNewLogGroupARN = "arn:aws:logs:us-west-2:111111111111:log-group:amp-new:*"
+	// testMissingLogGroupARN is rejected by the asynchronous validation of
+	// the fake AMP API.
+	testMissingLogGroupARN = "arn:aws:logs:us-west-2:111111111111:log-group:missing:*"
+)
+
+// newTestAPI returns a fake AMP API rejecting testMissingLogGroupARN.
+func newTestAPI() *ampfake.API {
+	api := ampfake.New(ampfake.TestAccountID, ampfake.TestRegion)
+	api.ValidateLogGroup = func(arn string) error {
+		if arn == testMissingLogGroupARN {
+			return errors.New("log group not found")
+		}
+		return nil
+	}
+	return api
+}
+
+// newTestResourceManager returns a resource manager calling the supplied fake
+// AMP API.
+func newTestResourceManager(api svcsdkapi.PrometheusServiceAPI) *resourceManager {
+	return &resourceManager{sdkapi: api, metrics: ampfake.TestMetrics, awsAccountID: ampfake.TestAccountID, awsRegion: ampfake.TestRegion}
+}
+
+// newTestLoggingConfiguration returns a logging configuration with the
+// supplied log group in the supplied workspace.
+func newTestLoggingConfiguration(workspaceID string, logGroupARN string) *resource {
+	r := newTestResource(nil, aws.String(logGroupARN))
+	r.ko.Spec.WorkspaceID = aws.String(workspaceID)
+	return r
+}
+
+func Test_sdkFind(t *testing.T) {
+	tests := []struct {
+		name string
+		// transitions is the number of descriptions before the creation of
+		// the logging configuration completes.
+		transitions int
+		logGroupARN string
+		// desiredLogGroupARN is the log group of the described resource.
+		desiredLogGroupARN string
+		deleting           bool
+		wantNotFound       bool
+		wantStatus         string
+		wantTerminal       bool
+		wantNotSynced      bool
+	}{
+		{
+			name:         "no logging configuration in the workspace",
+			wantNotFound: true,
+		},
+		{
+			name:          "creating",
+			transitions:   1,
+			logGroupARN:   testLogGroupARN,
+			wantStatus:    svcsdk.LoggingConfigurationStatusCodeCreating,
+			wantNotSynced: true,
+		},
+		{
+			name:        "active",
+			logGroupARN: testLogGroupARN,
+			wantStatus:  svcsdk.LoggingConfigurationStatusCodeActive,
+		},
+		{
+			name:         "creation failed",
+			logGroupARN:  testMissingLogGroupARN,
+			wantStatus:   svcsdk.LoggingConfigurationStatusCodeCreationFailed,
+			wantTerminal: true,
+		},
+		{
+			name:               "creation failed with a new log group",
+			logGroupARN:        testMissingLogGroupARN,
+			desiredLogGroupARN: testLogGroupARN,
+			wantStatus:         svcsdk.LoggingConfigurationStatusCodeCreationFailed,
+		},
+		{
+			name:        "creation failed while deleting",
+			logGroupARN: testMissingLogGroupARN,
+			deleting:    true,
+			wantStatus:  svcsdk.LoggingConfigurationStatusCodeCreationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			workspaceID := ampfake.NewActiveWorkspace(t, api)
+			desired := newTestResource(nil, aws.String(tt.logGroupARN))
+			desired.ko.Spec.WorkspaceID = aws.String(workspaceID)
+			if tt.logGroupARN != "" {
+				created, err := rm.sdkCreate(context.TODO(), desired)
+				if err != nil {
+					t.Fatalf("sdkCreate() error = %v", err)
+				}
+				desired = created
+			}
+			if tt.desiredLogGroupARN != "" {
+				desired.ko.Spec.LogGroupARN = aws.String(tt.desiredLogGroupARN)
+			}
+			if tt.deleting {
+				now := metav1.Now()
+				desired.ko.DeletionTimestamp = &now
+			}
+			api.Transitions = tt.transitions
+
+			latest, err := rm.sdkFind(context.TODO(), desired)
+			if tt.wantNotFound {
+				if err != ackerr.NotFound {
+					t.Errorf("sdkFind() error = %v, want NotFound", err)
+				}
+				return
+			}
+			if isTerminal := errors.As(err, new(*ackerr.TerminalError)); isTerminal != tt.wantTerminal {
+				t.Fatalf("sdkFind() error = %v, want terminal %v", err, tt.wantTerminal)
+			} else if !isTerminal && err != nil {
+				t.Fatalf("sdkFind() error = %v", err)
+			}
+			if got := *latest.ko.Status.StatusCode; got != tt.wantStatus {
+				t.Errorf("sdkFind() status = %s, want %s", got, tt.wantStatus)
+			}
+			if got := aws.StringValue(latest.ko.Spec.LogGroupARN); got != tt.logGroupARN {
+				t.Errorf("sdkFind() log group = %s, want %s", got, tt.logGroupARN)
+			}
+			if tt.wantNotSynced {
+				if synced := ackcondition.Synced(latest); synced == nil || synced.Status != corev1.ConditionFalse {
+					t.Errorf("sdkFind() synced condition = %v, want False", synced)
+				}
+			}
+		})
+	}
+}
+
+func Test_sdkCreate(t *testing.T) {
+	tests := []struct {
+		name string
+		// workspaceID replaces the ID of the workspace the logging
+		// configuration is created in.
+		workspaceID *string
+		// exists is true if the logging configuration already exists.
+		exists      bool
+		wantErrCode string
+	}{
+		{name: "workspace active"},
+		{name: "workspace not found", workspaceID: aws.String("ws-missing"), wantErrCode: svcsdk.ErrCodeResourceNotFoundException},
+		{name: "already exists", exists: true, wantErrCode: svcsdk.ErrCodeConflictException},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			workspaceID := ampfake.NewActiveWorkspace(t, api)
+			if tt.exists {
+				ampfake.CreateAndSettle(t, api, newTestResourceManager(api), newTestLoggingConfiguration(workspaceID, testLogGroupARN))
+			}
+			desired := newTestResource(nil, aws.String(testLogGroupARN))
+			desired.ko.Spec.WorkspaceID = aws.String(workspaceID)
+			if tt.workspaceID != nil {
+				desired.ko.Spec.WorkspaceID = tt.workspaceID
+			}
+
+			created, err := rm.sdkCreate(context.TODO(), desired)
+			if tt.wantErrCode != "" {
+				if code := ampfake.ErrorCode(err); code != tt.wantErrCode {
+					t.Errorf("sdkCreate() error = %v, want a %s", err, tt.wantErrCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if got := *created.ko.Status.StatusCode; got != svcsdk.LoggingConfigurationStatusCodeCreating {
+				t.Errorf("sdkCreate() status = %s, want CREATING", got)
+			}
+			if synced := ackcondition.Synced(created); synced == nil || synced.Status != corev1.ConditionFalse {
+				t.Errorf("sdkCreate() synced condition = %v, want False", synced)
+			}
+			if created.ko.Status.OperationStartTime == nil {
+				t.Errorf("sdkCreate() did not record the operation start time")
+			}
+		})
+	}
+}
+
+func Test_sdkUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		// logGroupARN is the log group the logging configuration is
+		// created with.
+		logGroupARN string
+		// busy is true if the logging configuration is updated while an
+		// update is still ongoing.
+		busy         bool
+		desired      string
+		workspaceID  *string
+		wantCalls    []string
+		wantBusy     bool
+		wantErr      bool
+		wantStatus   string
+		wantLogGroup string
+	}{
+		{
+			name:         "log group",
+			logGroupARN:  testLogGroupARN,
+			desired:      testNewLogGroupARN,
+			wantCalls:    []string{"UpdateLoggingConfiguration"},
+			wantStatus:   svcsdk.LoggingConfigurationStatusCodeActive,
+			wantLogGroup: testNewLogGroupARN,
+		},
+		{
+			name:         "missing log group",
+			logGroupARN:  testLogGroupARN,
+			desired:      testMissingLogGroupARN,
+			wantCalls:    []string{"UpdateLoggingConfiguration"},
+			wantStatus:   svcsdk.LoggingConfigurationStatusCodeUpdateFailed,
+			wantLogGroup: testMissingLogGroupARN,
+		},
+		{
+			name:         "fixed creation",
+			logGroupARN:  testMissingLogGroupARN,
+			desired:      testLogGroupARN,
+			wantCalls:    []string{"UpdateLoggingConfiguration"},
+			wantStatus:   svcsdk.LoggingConfigurationStatusCodeActive,
+			wantLogGroup: testLogGroupARN,
+		},
+		{
+			name:         "updating",
+			logGroupARN:  testLogGroupARN,
+			busy:         true,
+			desired:      testLogGroupARN,
+			wantBusy:     true,
+			wantStatus:   svcsdk.LoggingConfigurationStatusCodeActive,
+			wantLogGroup: testNewLogGroupARN,
+		},
+		{
+			name:         "workspace",
+			logGroupARN:  testLogGroupARN,
+			desired:      testLogGroupARN,
+			workspaceID:  aws.String("ws-other"),
+			wantErr:      true,
+			wantStatus:   svcsdk.LoggingConfigurationStatusCodeActive,
+			wantLogGroup: testLogGroupARN,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			workspaceID := ampfake.NewActiveWorkspace(t, api)
+			latest := ampfake.CreateAndSettle(t, api, rm, newTestLoggingConfiguration(workspaceID, tt.logGroupARN)).(*resource)
+			if tt.busy {
+				updating := &resource{latest.ko.DeepCopy()}
+				updating.ko.Spec.LogGroupARN = aws.String(testNewLogGroupARN)
+				var err error
+				api.Transitions = 1
+				if latest, err = rm.sdkUpdate(context.TODO(), updating, latest, newResourceDelta(updating, latest)); err != nil {
+					t.Fatalf("sdkUpdate() error = %v", err)
+				}
+			}
+
+			desired := &resource{latest.ko.DeepCopy()}
+			desired.ko.Spec.LogGroupARN = aws.String(tt.desired)
+			if tt.workspaceID != nil {
+				desired.ko.Spec.WorkspaceID = tt.workspaceID
+			}
+			calls := len(api.Calls())
+			updated, err := rm.sdkUpdate(context.TODO(), desired, latest, newResourceDelta(desired, latest))
+			if got := api.Calls()[calls:]; strings.Join(got, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("sdkUpdate() calls = %v, want %v", got, tt.wantCalls)
+			}
+			switch {
+			case tt.wantBusy:
+				var requeue *ackrequeue.RequeueNeededAfter
+				if !errors.As(err, &requeue) {
+					t.Errorf("sdkUpdate() error = %v, want a requeue while busy", err)
+				}
+			case tt.wantErr:
+				if !errors.As(err, new(*ackerr.TerminalError)) {
+					t.Errorf("sdkUpdate() error = %v, want a terminal error", err)
+				}
+			case err != nil:
+				t.Fatalf("sdkUpdate() error = %v", err)
+			default:
+				if got := *updated.ko.Status.StatusCode; got != svcsdk.LoggingConfigurationStatusCodeUpdating {
+					t.Errorf("sdkUpdate() status = %s, want UPDATING", got)
+				}
+				if synced := ackcondition.Synced(updated); synced == nil || synced.Status != corev1.ConditionFalse {
+					t.Errorf("sdkUpdate() synced condition = %v, want False", synced)
+				}
+			}
+
+			api.Settle()
+			resp, err := api.DescribeLoggingConfigurationWithContext(context.TODO(), &svcsdk.DescribeLoggingConfigurationInput{
+				WorkspaceId: aws.String(workspaceID),
+			})
+			if err != nil {
+				t.Fatalf("DescribeLoggingConfiguration() error = %v", err)
+			}
+			if got := *resp.LoggingConfiguration.Status.StatusCode; got != tt.wantStatus {
+				t.Errorf("logging configuration status = %s, want %s", got, tt.wantStatus)
+			}
+			if got := *resp.LoggingConfiguration.LogGroupArn; got != tt.wantLogGroup {
+				t.Errorf("logging configuration log group = %s, want %s", got, tt.wantLogGroup)
+			}
+		})
+	}
+}
+
+func Test_sdkDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		// transitions is the number of descriptions before an operation
+		// completes. The logging configuration is deleted while its
+		// creation is still ongoing if it is not zero.
+		transitions int
+		// deleted is true if the logging configuration was already deleted.
+		deleted     bool
+		wantCalls   []string
+		wantRequeue bool
+		wantErrCode string
+		wantDeleted bool
+	}{
+		{
+			name:        "active",
+			wantCalls:   []string{"DeleteLoggingConfiguration"},
+			wantDeleted: true,
+		},
+		{
+			name:        "creating",
+			transitions: 2,
+			wantRequeue: true,
+		},
+		{
+			name:        "already deleted",
+			deleted:     true,
+			wantCalls:   []string{"DeleteLoggingConfiguration"},
+			wantErrCode: svcsdk.ErrCodeResourceNotFoundException,
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newTestAPI()
+			rm := newTestResourceManager(api)
+			workspaceID := ampfake.NewActiveWorkspace(t, api)
+			desired := newTestResource(nil, aws.String(testLogGroupARN))
+			desired.ko.Spec.WorkspaceID = aws.String(workspaceID)
+			latest, err := rm.sdkCreate(context.TODO(), desired)
+			if err != nil {
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if tt.transitions == 0 {
+				api.Settle()
+			}
+			api.Transitions = tt.transitions
+			if latest, err = rm.sdkFind(context.TODO(), latest); err != nil {
+				t.Fatalf("sdkFind() error = %v", err)
+			}
+			if tt.deleted {
+				if _, err := rm.sdkDelete(context.TODO(), latest); err != nil {
+					t.Fatalf("sdkDelete() error = %v", err)
+				}
+				api.Settle()
+			}
+
+			calls := len(api.Calls())
+			_, err = rm.sdkDelete(context.TODO(), latest)
+			if got := api.Calls()[calls:]; strings.Join(got, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("sdkDelete() calls = %v, want %v", got, tt.wantCalls)
+			}
+			if tt.wantRequeue {
+				var requeue *ackrequeue.RequeueNeededAfter
+				if !errors.As(err, &requeue) {
+					t.Errorf("sdkDelete() error = %v, want a requeue", err)
+				}
+			} else if code := ampfake.ErrorCode(err); code != tt.wantErrCode || (tt.wantErrCode == "" && err != nil) {
+				t.Errorf("sdkDelete() error = %v, want %q", err, tt.wantErrCode)
+			}
+
+			api.Settle()
+			_, err = rm.sdkFind(context.TODO(), latest)
+			if deleted := err == ackerr.NotFound; deleted != tt.wantDeleted {
+				t.Errorf("sdkFind() error = %v after the deletion, want deleted %v", err, tt.wantDeleted)
+			}
+		})
+	}
+}
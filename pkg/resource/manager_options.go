@@ -0,0 +1,114 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resource
+
+import (
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
+)
+
+// ManagerOptions are the options of the resource managers that the ACK
+// runtime does not pass to their factories.
+type ManagerOptions struct {
+	// KubeClient is the cached client of the controller manager.
+	KubeClient client.Client
+	// StalledTimeouts are the stalled timeouts of the state machines
+	// configured with --stalled-timeout-seconds.
+	StalledTimeouts ampstatus.StalledTimeouts
+}
+
+// optionsReconciler is the reconciler of a resource manager, along with the
+// options of the resource manager.
+type optionsReconciler struct {
+	acktypes.Reconciler
+
+	opts ManagerOptions
+}
+
+// optionsManagerFactory wraps a resource manager factory. The resource
+// managers it returns can read their options with KubeClient and
+// StalledTimeouts.
+type optionsManagerFactory struct {
+	acktypes.AWSResourceManagerFactory
+
+	opts ManagerOptions
+}
+
+// ManagerFor returns the resource manager of the wrapped factory for the
+// supplied AWS account and region, created with the supplied reconciler
+// along with the options of the factory.
+func (f *optionsManagerFactory) ManagerFor(
+	cfg ackcfg.Config,
+	log logr.Logger,
+	metrics *ackmetrics.Metrics,
+	rr acktypes.Reconciler,
+	sess *session.Session,
+	id ackv1alpha1.AWSAccountID,
+	region ackv1alpha1.AWSRegion,
+) (acktypes.AWSResourceManager, error) {
+	rr = ReconcilerWithOptions(rr, f.opts)
+	return f.AWSResourceManagerFactory.ManagerFor(cfg, log, metrics, rr, sess, id, region)
+}
+
+// ReconcilerWithOptions returns the supplied reconciler along with the
+// supplied options, which KubeClient and StalledTimeouts return for it.
+func ReconcilerWithOptions(rr acktypes.Reconciler, opts ManagerOptions) acktypes.Reconciler {
+	return &optionsReconciler{Reconciler: rr, opts: opts}
+}
+
+// WithManagerOptions returns the supplied resource manager factories, wrapped
+// so that their resource managers can read the supplied options with
+// KubeClient and StalledTimeouts. The generated resource managers are only
+// passed the uncached API reader of the ACK runtime.
+func WithManagerOptions(
+	factories []acktypes.AWSResourceManagerFactory,
+	opts ManagerOptions,
+) []acktypes.AWSResourceManagerFactory {
+	wrapped := make([]acktypes.AWSResourceManagerFactory, 0, len(factories))
+	for _, f := range factories {
+		wrapped = append(wrapped, &optionsManagerFactory{AWSResourceManagerFactory: f, opts: opts})
+	}
+	return wrapped
+}
+
+// managerOptions returns the options of the resource manager created with
+// the supplied reconciler, which are empty if its factory was not wrapped by
+// WithManagerOptions.
+func managerOptions(rr acktypes.Reconciler) ManagerOptions {
+	if r, ok := rr.(*optionsReconciler); ok {
+		return r.opts
+	}
+	return ManagerOptions{}
+}
+
+// KubeClient returns the cached client of the controller manager of the
+// resource manager created with the supplied reconciler, or nil if its
+// factory was not wrapped by WithManagerOptions.
+func KubeClient(rr acktypes.Reconciler) client.Client {
+	return managerOptions(rr).KubeClient
+}
+
+// StalledTimeouts returns the stalled timeouts of the resource manager
+// created with the supplied reconciler. They are the default timeouts of the
+// state machines if its factory was not wrapped by WithManagerOptions.
+func StalledTimeouts(rr acktypes.Reconciler) ampstatus.StalledTimeouts {
+	return managerOptions(rr).StalledTimeouts
+}
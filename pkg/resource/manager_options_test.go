@@ -0,0 +1,77 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resource
+
+import (
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
+)
+
+// recordingFactory is a resource manager factory recording the reconciler it
+// creates resource managers with.
+type recordingFactory struct {
+	acktypes.AWSResourceManagerFactory
+	rr acktypes.Reconciler
+}
+
+func (f *recordingFactory) ManagerFor(
+	_ ackcfg.Config,
+	_ logr.Logger,
+	_ *ackmetrics.Metrics,
+	rr acktypes.Reconciler,
+	_ *session.Session,
+	_ ackv1alpha1.AWSAccountID,
+	_ ackv1alpha1.AWSRegion,
+) (acktypes.AWSResourceManager, error) {
+	f.rr = rr
+	return nil, nil
+}
+
+func TestWithManagerOptions(t *testing.T) {
+	opts := ManagerOptions{
+		KubeClient:      fake.NewClientBuilder().Build(),
+		StalledTimeouts: ampstatus.StalledTimeouts{ampstatus.Workspace: time.Hour},
+	}
+	factory := &recordingFactory{}
+	wrapped := WithManagerOptions([]acktypes.AWSResourceManagerFactory{factory}, opts)
+	if len(wrapped) != 1 {
+		t.Fatalf("WithManagerOptions() returned %d factories, want 1", len(wrapped))
+	}
+	rr := &fakeReconciler{kind: "Workspace"}
+	if _, err := wrapped[0].ManagerFor(ackcfg.Config{}, logr.Discard(), nil, rr, nil, "", ""); err != nil {
+		t.Fatalf("ManagerFor() error = %v", err)
+	}
+	if got := KubeClient(factory.rr); got != opts.KubeClient {
+		t.Errorf("KubeClient() = %v, want the client of the wrapping factory", got)
+	}
+	if got := StalledTimeouts(factory.rr).For(ampstatus.Workspace); got != time.Hour {
+		t.Errorf("StalledTimeouts() workspace timeout = %v, want %v", got, time.Hour)
+	}
+	if got := KubeClient(rr); got != nil {
+		t.Errorf("KubeClient() = %v for a reconciler of an unwrapped factory, want nil", got)
+	}
+	if got := StalledTimeouts(rr).For(ampstatus.Workspace); got != ampstatus.Workspace.DefaultStalledTimeout {
+		t.Errorf("StalledTimeouts() workspace timeout = %v for a reconciler of an unwrapped factory, want the default", got)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+)
+
+// resolveExtensionReferences resolves the references of the supplied rule
+// groups namespace that are not declared in generator.yaml. It is called by
+// the generated ResolveReferences once the Workspace reference is resolved.
+func (rm *resourceManager) resolveExtensionReferences(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	ko *svcapitypes.RuleGroupsNamespace,
+) error {
+	return resolveConfigurationFrom(ctx, apiReader, namespace, ko)
+}
+
+// hasNonNilExtensionReferences returns true if the supplied rule groups
+// namespace references ConfigMap keys.
+func hasNonNilExtensionReferences(ko *svcapitypes.RuleGroupsNamespace) bool {
+	return ko.Spec.ConfigurationFrom != nil
+}
+
+// resolveConfigurationFrom reads the ConfigMap keys referenced by
+// Spec.ConfigurationFrom and replaces the references with the concatenated
+// rule groups in Spec.Configuration. Like other resolved references, the
+// resolved configuration is not persisted to the Kubernetes API: the patch
+// of the custom resource is computed against the resolved desired state.
+func resolveConfigurationFrom(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	ko *svcapitypes.RuleGroupsNamespace,
+) error {
+	if len(ko.Spec.ConfigurationFrom) == 0 {
+		return nil
+	}
+	if _, err := configurationSource(ko); err != nil {
+		return ackerr.NewTerminalError(err)
+	}
+	path := field.NewPath("spec", "configurationFrom")
+	var errs field.ErrorList
+	configurations := []string{}
+	for i, ref := range ko.Spec.ConfigurationFrom {
+		if ref == nil || ref.Name == nil || ref.Key == nil {
+			return fmt.Errorf("provided ConfigMap key reference is nil or empty")
+		}
+		configuration, found, err := getConfigMapValue(ctx, apiReader, namespace, ref)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if ref.Optional != nil && *ref.Optional {
+				continue
+			}
+			return fmt.Errorf(
+				"ConfigMap %s/%s or its key %q not found",
+				namespace, *ref.Name, *ref.Key,
+			)
+		}
+		if verrs := validateRuleGroupsConfiguration(path.Index(i), configuration); len(verrs) > 0 {
+			errs = append(errs, verrs...)
+			continue
+		}
+		configurations = append(configurations, configuration)
+	}
+	if len(errs) > 0 {
+		return ackerr.NewTerminalError(errs.ToAggregate())
+	}
+	data, err := concatRuleGroups(configurations)
+	if err != nil {
+		return err
+	}
+	configuration := string(data)
+	ko.Spec.Configuration = &configuration
+	ko.Spec.ConfigurationFrom = nil
+	return nil
+}
+
+// getConfigMapValue returns the value of the referenced ConfigMap key, and
+// whether the ConfigMap and the key exist.
+func getConfigMapValue(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	ref *svcapitypes.ConfigMapKeyReference,
+) (string, bool, error) {
+	cm := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      *ref.Name,
+	}
+	if err := apiReader.Get(ctx, namespacedName, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	value, found := cm.Data[*ref.Key]
+	return value, found, nil
+}
+
+// concatRuleGroups concatenates the rule groups of the supplied rule groups
+// configurations, in order, into a single configuration. Comments and the
+// formatting of the groups are preserved.
+func concatRuleGroups(configurations []string) ([]byte, error) {
+	groups := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	names := map[string]bool{}
+	for _, configuration := range configurations {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(configuration), &doc); err != nil {
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			return nil, ackerr.NewTerminalError(errors.New("rule groups configuration is empty"))
+		}
+		root := doc.Content[0]
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value != "groups" {
+				continue
+			}
+			for _, g := range root.Content[i+1].Content {
+				var group struct {
+					Name string `yaml:"name"`
+				}
+				if err := g.Decode(&group); err != nil {
+					return nil, err
+				}
+				if names[group.Name] {
+					return nil, fmt.Errorf(
+						"rule group %q is defined by more than one ConfigMap key", group.Name,
+					)
+				}
+				names[group.Name] = true
+				groups.Content = append(groups.Content, g)
+			}
+		}
+	}
+
+	file := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "groups"},
+			groups,
+		},
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(file); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// referencesConfigMap returns true if the supplied resource references the
+// named ConfigMap in Spec.ConfigurationFrom.
+func referencesConfigMap(ko *svcapitypes.RuleGroupsNamespace, name string) bool {
+	for _, ref := range ko.Spec.ConfigurationFrom {
+		if ref != nil && ref.Name != nil && *ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// configMapRequests returns a function that maps a ConfigMap to reconcile
+// requests for the rule groups namespaces in its namespace that reference it.
+func configMapRequests(kc client.Reader) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		list := &svcapitypes.RuleGroupsNamespaceList{}
+		if err := kc.List(context.TODO(), list, client.InNamespace(obj.GetNamespace())); err != nil {
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range list.Items {
+			if referencesConfigMap(&list.Items[i], obj.GetName()) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: list.Items[i].Namespace,
+						Name:      list.Items[i].Name,
+					},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// setupConfigMapWatch re-renders the configuration of rule groups namespaces
+// when a ConfigMap they reference changes, by enqueueing them. Only the
+// metadata of ConfigMaps is watched, so that the ConfigMaps of the cluster
+// are not cached by the controller.
+func setupConfigMapWatch(mgr ctrlrt.Manager, b *builder.Builder) error {
+	configMap := &metav1.PartialObjectMetadata{}
+	configMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	b.Watches(
+		&source.Kind{Type: configMap},
+		handler.EnqueueRequestsFromMapFunc(configMapRequests(mgr.GetClient())),
+	)
+	return nil
+}
+
+func init() {
+	svcresource.RegisterWatches("RuleGroupsNamespace", setupConfigMapWatch)
+}
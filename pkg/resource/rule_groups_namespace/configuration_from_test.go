@@ -0,0 +1,253 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+const (
+	recordingRules = `groups:
+  # Recording rules
+  - name: recording
+    rules:
+      - record: job:up:sum
+        expr: sum by (job) (up)
+`
+	alertingRules = `groups:
+  - name: alerting
+    rules:
+      - alert: JobDown
+        expr: job:up:sum == 0
+        for: 5m
+`
+)
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = svcapitypes.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newConfigMap(name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Data:       data,
+	}
+}
+
+func configMapKeyRef(name, key string, optional bool) *svcapitypes.ConfigMapKeyReference {
+	ref := &svcapitypes.ConfigMapKeyReference{
+		Name: aws.String(name),
+		Key:  aws.String(key),
+	}
+	if optional {
+		ref.Optional = aws.Bool(true)
+	}
+	return ref
+}
+
+func Test_resolveConfigurationFrom(t *testing.T) {
+	kc := newFakeClient(
+		newConfigMap("rules", map[string]string{
+			"recording.yaml": recordingRules,
+			"alerting.yaml":  alertingRules,
+			"invalid.yaml":   "groups:\n  - name: invalid\n    rules:\n      - record: job:up:sum\n        expr: sum(\n",
+			"empty.yaml":     "# No rules yet\n",
+		}),
+		newConfigMap("duplicate", map[string]string{
+			"recording.yaml": recordingRules,
+		}),
+	)
+	tests := []struct {
+		name         string
+		refs         []*svcapitypes.ConfigMapKeyReference
+		want         string
+		wantErr      string
+		wantTerminal bool
+	}{
+		{
+			name: "concatenated in order",
+			refs: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("rules", "recording.yaml", false),
+				configMapKeyRef("rules", "alerting.yaml", false),
+			},
+			want: `groups:
+  # Recording rules
+  - name: recording
+    rules:
+      - record: job:up:sum
+        expr: sum by (job) (up)
+  - name: alerting
+    rules:
+      - alert: JobDown
+        expr: job:up:sum == 0
+        for: 5m
+`,
+		},
+		{
+			name: "missing optional references are skipped",
+			refs: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("rules", "alerting.yaml", false),
+				configMapKeyRef("rules", "missing.yaml", true),
+				configMapKeyRef("missing", "alerting.yaml", true),
+			},
+			want: `groups:
+  - name: alerting
+    rules:
+      - alert: JobDown
+        expr: job:up:sum == 0
+        for: 5m
+`,
+		},
+		{
+			name: "missing ConfigMap",
+			refs: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("missing", "alerting.yaml", false),
+			},
+			wantErr: `ConfigMap default/missing or its key "alerting.yaml" not found`,
+		},
+		{
+			name: "missing key",
+			refs: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("rules", "missing.yaml", false),
+			},
+			wantErr: `ConfigMap default/rules or its key "missing.yaml" not found`,
+		},
+		{
+			name: "invalid configuration",
+			refs: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("rules", "recording.yaml", false),
+				configMapKeyRef("rules", "invalid.yaml", false),
+			},
+			wantErr:      "spec.configurationFrom[1]: Invalid value",
+			wantTerminal: true,
+		},
+		{
+			name: "empty configuration",
+			refs: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("rules", "empty.yaml", false),
+			},
+			wantErr:      "spec.configurationFrom[0]: Invalid value",
+			wantTerminal: true,
+		},
+		{
+			name: "group defined twice",
+			refs: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("rules", "recording.yaml", false),
+				configMapKeyRef("duplicate", "recording.yaml", false),
+			},
+			wantErr: `rule group "recording" is defined by more than one ConfigMap key`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.RuleGroupsNamespace{
+				Spec: svcapitypes.RuleGroupsNamespaceSpec{
+					ConfigurationFrom: tt.refs,
+				},
+			}
+			err := resolveConfigurationFrom(context.TODO(), kc, "default", ko)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveConfigurationFrom() error = %v, want %q", err, tt.wantErr)
+				}
+				var terminal *ackerr.TerminalError
+				if errors.As(err, &terminal) != tt.wantTerminal {
+					t.Errorf("resolveConfigurationFrom() terminal error = %v, want %v", !tt.wantTerminal, tt.wantTerminal)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveConfigurationFrom() unexpected error = %v", err)
+			}
+			if ko.Spec.ConfigurationFrom != nil {
+				t.Errorf("resolveConfigurationFrom() did not clear Spec.ConfigurationFrom")
+			}
+			if got := aws.StringValue(ko.Spec.Configuration); got != tt.want {
+				t.Errorf("resolveConfigurationFrom() configuration = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_concatRuleGroups_empty(t *testing.T) {
+	for _, configuration := range []string{"", "  \n", "# No rules yet\n"} {
+		_, err := concatRuleGroups([]string{recordingRules, configuration})
+		var terminal *ackerr.TerminalError
+		if !errors.As(err, &terminal) {
+			t.Errorf("concatRuleGroups(%q) error = %v, want a terminal error", configuration, err)
+		}
+	}
+}
+
+func Test_resolveConfigurationFrom_configurationSet(t *testing.T) {
+	ko := &svcapitypes.RuleGroupsNamespace{
+		Spec: svcapitypes.RuleGroupsNamespaceSpec{
+			Configuration: aws.String(recordingRules),
+			ConfigurationFrom: []*svcapitypes.ConfigMapKeyReference{
+				configMapKeyRef("rules", "recording.yaml", false),
+			},
+		},
+	}
+	err := resolveConfigurationFrom(context.TODO(), newFakeClient(), "default", ko)
+	if err == nil || !strings.Contains(err.Error(), ErrConfigurationSourcesSet.Error()) {
+		t.Errorf("resolveConfigurationFrom() error = %v, want %v", err, ErrConfigurationSourcesSet)
+	}
+}
+
+func Test_configMapRequests(t *testing.T) {
+	newRuleGroupsNamespace := func(namespace, name string, configMaps ...string) *svcapitypes.RuleGroupsNamespace {
+		ko := &svcapitypes.RuleGroupsNamespace{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		}
+		for _, cm := range configMaps {
+			ko.Spec.ConfigurationFrom = append(ko.Spec.ConfigurationFrom, configMapKeyRef(cm, "rules.yaml", false))
+		}
+		return ko
+	}
+	kc := newFakeClient(
+		newRuleGroupsNamespace("default", "a", "rules"),
+		newRuleGroupsNamespace("default", "b", "other", "rules"),
+		newRuleGroupsNamespace("default", "c", "other"),
+		newRuleGroupsNamespace("default", "d"),
+		newRuleGroupsNamespace("other", "e", "rules"),
+	)
+	got := configMapRequests(kc)(newConfigMap("rules", nil))
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}},
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("configMapRequests() = %v, want %v", got, want)
+	}
+}
@@ -16,92 +16,85 @@ package rule_groups_namespace
 import (
 	"context"
 	"errors"
-	"time"
 
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
 	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
-	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
 	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
 	corev1 "k8s.io/api/core/v1"
 
 	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspacestatus"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/yamlcompare"
 )
 
-var (
-	ErrRuleGroupsNamespaceCreating = errors.New("Rule Groups Namespace is in 'CREATING' state, cannot be modified or deleted")
-	ErrRuleGroupsNamespaceDeleting = errors.New("Rule Groups Namespace is in 'DELETING' state, cannot be modified or deleted")
-	ErrRuleGroupsNamespaceUpdating = errors.New("Rule Groups Namespace is in 'UPDATING' state, cannot be modified or deleted")
-)
-
-var (
-	// TerminalStatuses are the status strings that are a rule groups namespace
-	TerminalStatuses = []svcapitypes.RuleGroupsNamespaceStatusCode{
-		svcapitypes.RuleGroupsNamespaceStatusCode_CREATION_FAILED,
-		svcapitypes.RuleGroupsNamespaceStatusCode_UPDATE_FAILED,
-		svcapitypes.RuleGroupsNamespaceStatusCode_DELETING,
+// ruleGroupsNamespaceStatusCode returns the status code of the supplied rule
+// groups namespace, or nil if it is unknown
+func ruleGroupsNamespaceStatusCode(r *resource) *string {
+	if r.ko.Status.Status == nil {
+		return nil
 	}
-)
-
-var (
-	requeueWaitWhileDeleting = ackrequeue.NeededAfter(
-		ErrRuleGroupsNamespaceDeleting,
-		10*time.Second,
-	)
-	requeueWaitWhileCreating = ackrequeue.NeededAfter(
-		ErrRuleGroupsNamespaceCreating,
-		15*time.Second,
-	)
-	requeueWaitWhileUpdating = ackrequeue.NeededAfter(
-		ErrRuleGroupsNamespaceUpdating,
-		10*time.Second,
-	)
-)
+	return r.ko.Status.Status.StatusCode
+}
 
 // ruleGroupsNamespaceCreating returns true if the supplied rule groups is in the process
 // of being created
 func ruleGroupsNamespaceCreating(r *resource) bool {
-	if r.ko.Status.Status == nil {
-		return false
-	}
-	ws := *r.ko.Status.Status.StatusCode
-	return ws == string(svcapitypes.RuleGroupsNamespaceStatusCode_CREATING)
-}
-
-// ruleGroupsNamespaceDeleting returns true if the supplied rule groups is in the process
-// of being deleted
-func ruleGroupsNamespaceDeleting(r *resource) bool {
-	if r.ko.Status.Status == nil {
-		return false
-	}
-	ws := *r.ko.Status.Status.StatusCode
-	return ws == string(svcapitypes.RuleGroupsNamespaceStatusCode_DELETING)
+	return ampstatus.RuleGroupsNamespace.Is(ruleGroupsNamespaceStatusCode(r), ampstatus.Creating)
 }
 
 // ruleGroupsNamespaceUpdating returns true if the supplied rule groups is in the process
 // of being updated
 func ruleGroupsNamespaceUpdating(r *resource) bool {
-	if r.ko.Status.Status == nil {
-		return false
-	}
-	ws := *r.ko.Status.Status.StatusCode
-	return ws == string(svcapitypes.RuleGroupsNamespaceStatusCode_UPDATING)
+	return ampstatus.RuleGroupsNamespace.Is(ruleGroupsNamespaceStatusCode(r), ampstatus.Updating)
 }
 
-// ruleGroupsNamespaceHasTerminalStatus returns whether the supplied rule groups namespace is in a
-// terminal state
-func ruleGroupsNamespaceHasTerminalStatus(r *resource) bool {
-	if r.ko.Status.Status.StatusCode == nil {
-		return false
-	}
-	ts := *r.ko.Status.Status.StatusCode
-	for _, s := range TerminalStatuses {
-		if ts == string(s) {
-			return true
-		}
+// ruleGroupsNamespaceStatusFailed returns true if the asynchronous creation
+// or update of the supplied rule groups namespace failed
+func ruleGroupsNamespaceStatusFailed(r *resource) bool {
+	return ampstatus.RuleGroupsNamespace.Terminal(ruleGroupsNamespaceStatusCode(r))
+}
+
+// setRuleGroupsNamespaceOperationStartTime records in the supplied rule
+// groups namespace the time at which it entered its current CREATING,
+// UPDATING or DELETING status, given the resource it was observed from.
+func setRuleGroupsNamespaceOperationStartTime(r *resource, ko *svcapitypes.RuleGroupsNamespace) {
+	ko.Status.OperationStartTime = ampstatus.RuleGroupsNamespace.OperationStartTime(
+		ruleGroupsNamespaceStatusCode(r), r.ko.Status.OperationStartTime,
+		ruleGroupsNamespaceStatusCode(&resource{ko}),
+	)
+}
+
+// observeRuleGroupsNamespaceTransition logs the supplied rule groups namespace
+// moving to a status that its state machine does not expect, given the
+// resource it was observed from.
+func observeRuleGroupsNamespaceTransition(ctx context.Context, r *resource, ko *svcapitypes.RuleGroupsNamespace) {
+	ampstatus.RuleGroupsNamespace.ObserveTransition(ctx, ruleGroupsNamespaceStatusCode(r), ruleGroupsNamespaceStatusCode(&resource{ko}))
+}
+
+// ensureRuleGroupsNamespaceNotStalled returns an error requeueing the
+// supplied rule groups namespace with a Stalled condition if it has been
+// CREATING, UPDATING or DELETING for longer than the configured stalled
+// timeout of rule groups namespaces.
+func (rm *resourceManager) ensureRuleGroupsNamespaceNotStalled(ko *svcapitypes.RuleGroupsNamespace) error {
+	return ampstatus.RuleGroupsNamespace.EnsureNotStalled(
+		&resource{ko}, ruleGroupsNamespaceStatusCode(&resource{ko}), ko.Status.OperationStartTime,
+		svcresource.StalledTimeouts(rm.rr).For(ampstatus.RuleGroupsNamespace),
+	)
+}
+
+// ruleGroupsNamespaceFailedMessage returns the message of the terminal
+// condition of the supplied failed rule groups namespace, including the
+// reason given by AMP, if any.
+func ruleGroupsNamespaceFailedMessage(r *resource) string {
+	msg := "Rule Groups Namespace is in '" + *r.ko.Status.Status.StatusCode + "' status"
+	if r.ko.Status.Status.StatusReason != nil && *r.ko.Status.Status.StatusReason != "" {
+		msg += ": " + *r.ko.Status.Status.StatusReason
 	}
-	return false
+	return msg
 }
 
 // customUpdateRuleGroupsNamespace patches each of the resource properties in the backend AWS
@@ -119,24 +112,10 @@ func (rm *resourceManager) customUpdateRuleGroupsNamespace(
 
 	// Check if the state is being currently created, updated or deleted.
 	// If it is, then requeue because we can't update while it is in those states.
-	var sc string = ""
-	if latest.ko.Status.Status != nil {
-		sc = *latest.ko.Status.Status.StatusCode
-	}
-	switch sc {
-	case string(svcapitypes.RuleGroupsNamespaceStatusCode_DELETING):
-		return desired, requeueWaitWhileDeleting
-	case string(svcapitypes.RuleGroupsNamespaceStatusCode_UPDATING):
-		return desired, requeueWaitWhileUpdating
-	case string(svcapitypes.RuleGroupsNamespaceStatusCode_CREATING):
-		return desired, requeueWaitWhileCreating
-	}
-
-	if ruleGroupsNamespaceHasTerminalStatus(latest) {
-		msg := "Rule Groups Namespace is in '" + *latest.ko.Status.Status.StatusCode + "' status"
-		ackcondition.SetTerminal(desired, corev1.ConditionTrue, &msg, nil)
-		ackcondition.SetSynced(desired, corev1.ConditionTrue, nil, nil)
-		return desired, nil
+	// For failed states (create & update) and active states, the user can
+	// still update the rule groups namespace.
+	if err = ampstatus.RuleGroupsNamespace.RequeueWhileBusy(ruleGroupsNamespaceStatusCode(latest)); err != nil {
+		return desired, err
 	}
 
 	// Merge in the information we read from the API call above to the copy of
@@ -151,7 +130,7 @@ func (rm *resourceManager) customUpdateRuleGroupsNamespace(
 		}
 	}
 
-	if delta.DifferentAt("Spec.Configuration") {
+	if delta.DifferentAt("Spec.Configuration") || delta.DifferentAt("Spec.Groups") {
 		updatedResource, err := rm.updateRuleGroupsNamespace(ctx, desired)
 		if err != nil {
 			return nil, err
@@ -221,12 +200,12 @@ func (rm *resourceManager) updateRuleGroupsNamespace(
 	exit := rlog.Trace("rm.updateRuleGroupsNamespace")
 	defer exit(err)
 
-	var configurationBytes []byte = nil
-	// Convert the string version of the configuration to a byte slice
-	// because the API expects a base64 encoding. The conversion to base64
-	// is handled automatically by k8s.
-	if desired.ko.Spec.Configuration != nil {
-		configurationBytes = []byte(*desired.ko.Spec.Configuration)
+	// Convert the configuration, or the rendered rule groups, to a byte
+	// slice because the API expects a base64 encoding. The conversion to
+	// base64 is handled automatically by k8s.
+	configurationBytes, err := ruleGroupsNamespaceData(desired.ko)
+	if err != nil {
+		return nil, err
 	}
 
 	input := &svcsdk.PutRuleGroupsNamespaceInput{
@@ -282,6 +261,7 @@ func (rm *resourceManager) updateRuleGroupsNamespace(
 	}
 
 	rm.setStatusDefaults(ko)
+	setRuleGroupsNamespaceOperationStartTime(desired, ko)
 	// Some updates might be instant and the resource will remain in an active state.
 	// While other updates, might take a while to update and the resource will be in an `UPDATING`
 	// state. If this is the case, then we want to requeue until the resource is done updating.
@@ -338,4 +318,44 @@ func customPreCompare(
 			delta.Add("Spec.Tags", a.ko.Spec.Tags, b.ko.Spec.Tags)
 		}
 	}
+
+	// The rule groups configuration is compared in its rendered form, so
+	// that Spec.Groups in the desired state can be compared against the
+	// configuration that AMP returns. The documents are compared as parsed
+	// YAML, so that formatting, key order and comment differences don't
+	// trigger an update. An invalid spec is always reported as a difference
+	// so that the error surfaces from the update.
+	dataA, errA := ruleGroupsNamespaceData(a.ko)
+	dataB, errB := ruleGroupsNamespaceData(b.ko)
+	if errA != nil || errB != nil || !yamlcompare.Equal(string(dataA), string(dataB)) {
+		if len(a.ko.Spec.Groups) > 0 {
+			delta.Add("Spec.Groups", a.ko.Spec.Groups, b.ko.Spec.Configuration)
+		} else {
+			delta.Add("Spec.Configuration", a.ko.Spec.Configuration, b.ko.Spec.Configuration)
+		}
+	}
+}
+
+// ensureWorkspaceActive returns a copy of the supplied resource with a
+// WorkspaceNotReady condition and an error requeueing it if its workspace is
+// not ACTIVE yet. AMP rejects the creation with a ConflictException until
+// then.
+func (rm *resourceManager) ensureWorkspaceActive(
+	ctx context.Context,
+	r *resource,
+) (*resource, error) {
+	if r.ko.Spec.WorkspaceID == nil {
+		return nil, nil
+	}
+	err := workspacestatus.EnsureActive(
+		ctx, rm.sdkapi, rm.metrics,
+		workspacestatus.For(rm.awsAccountID, rm.awsRegion),
+		*r.ko.Spec.WorkspaceID,
+	)
+	var notReady *workspacestatus.NotReadyError
+	if !errors.As(err, &notReady) {
+		return nil, err
+	}
+	ko := r.ko.DeepCopy()
+	return &resource{ko}, workspacestatus.SetNotReady(&resource{ko}, notReady)
 }
@@ -14,10 +14,25 @@
 package rule_groups_namespace
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
 )
 
 // Same test function as in the Workspace resource. Test function modified from:
@@ -91,3 +106,191 @@ func Test_compareMaps(t *testing.T) {
 		})
 	}
 }
+
+func Test_customPreCompare(t *testing.T) {
+	newResource := func(spec svcapitypes.RuleGroupsNamespaceSpec) *resource {
+		return &resource{ko: &svcapitypes.RuleGroupsNamespace{Spec: spec}}
+	}
+	groups := []*svcapitypes.RuleGroup{
+		{
+			Name: aws.String("test"),
+			Rules: []*svcapitypes.Rule{
+				{Record: aws.String("r"), Expr: aws.String("up")},
+			},
+		},
+	}
+	tests := []struct {
+		name      string
+		a         *resource
+		b         *resource
+		wantDiffs []string
+	}{
+		{
+			name:      "reformatted configuration",
+			a:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups:\n- name: test\n  rules: []\n")}),
+			b:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("# comment\ngroups:\n  - rules: []\n    name: test\n")}),
+			wantDiffs: nil,
+		},
+		{
+			name:      "changed configuration",
+			a:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups:\n- name: test\n  rules: []\n")}),
+			b:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups:\n- name: other\n  rules: []\n")}),
+			wantDiffs: []string{"Spec.Configuration"},
+		},
+		{
+			name:      "groups matching the configuration",
+			a:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Groups: groups}),
+			b:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups:\n- name: test\n  rules:\n  - expr: up\n    record: r\n")}),
+			wantDiffs: nil,
+		},
+		{
+			name:      "groups not matching the configuration",
+			a:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Groups: groups}),
+			b:         newResource(svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups: []\n")}),
+			wantDiffs: []string{"Spec.Groups"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := ackcompare.NewDelta()
+			customPreCompare(delta, tt.a, tt.b)
+			if len(delta.Differences) != len(tt.wantDiffs) {
+				t.Fatalf("customPreCompare() got %d differences, want %v", len(delta.Differences), tt.wantDiffs)
+			}
+			for _, path := range tt.wantDiffs {
+				if !delta.DifferentAt(path) {
+					t.Errorf("customPreCompare() expected a difference at %s", path)
+				}
+			}
+		})
+	}
+}
+
+// describeAPI serves a single rule groups namespace from DescribeRuleGroupsNamespace.
+type describeAPI struct {
+	svcsdkapi.PrometheusServiceAPI
+	description *svcsdk.RuleGroupsNamespaceDescription
+}
+
+func (a *describeAPI) DescribeRuleGroupsNamespaceWithContext(
+	context.Context,
+	*svcsdk.DescribeRuleGroupsNamespaceInput,
+	...request.Option,
+) (*svcsdk.DescribeRuleGroupsNamespaceOutput, error) {
+	description := *a.description
+	return &svcsdk.DescribeRuleGroupsNamespaceOutput{RuleGroupsNamespace: &description}, nil
+}
+
+func Test_sdkFind_failedStatus(t *testing.T) {
+	const (
+		validConfiguration   = "groups:\n- name: valid\n  rules: []\n"
+		invalidConfiguration = "groups:\n- name: invalid\n  rules: [{record: r, expr: 'up('}]\n"
+		reason               = "error validating rules"
+	)
+	newDesired := func(statusCode string) *resource {
+		ko := &svcapitypes.RuleGroupsNamespace{}
+		ko.Spec.Name = aws.String("rules")
+		ko.Spec.WorkspaceID = aws.String("ws-1")
+		ko.Spec.Configuration = aws.String(invalidConfiguration)
+		ko.Status.Status = &svcapitypes.RuleGroupsNamespaceStatus_SDK{StatusCode: aws.String(statusCode)}
+		return &resource{ko}
+	}
+	api := &describeAPI{description: &svcsdk.RuleGroupsNamespaceDescription{
+		Name: aws.String("rules"),
+		Data: []byte(validConfiguration),
+		Status: &svcsdk.RuleGroupsNamespaceStatus{
+			StatusCode:   aws.String(svcsdk.RuleGroupsNamespaceStatusCodeUpdateFailed),
+			StatusReason: aws.String(reason),
+		},
+	}}
+	rm := &resourceManager{sdkapi: api, metrics: ackmetrics.NewMetrics("prometheusservice")}
+
+	// The update just failed: the desired configuration is kept and the
+	// reason is reported.
+	latest, err := rm.sdkFind(context.TODO(), newDesired(svcsdk.RuleGroupsNamespaceStatusCodeUpdating))
+	if err != nil {
+		t.Fatalf("sdkFind() error = %v", err)
+	}
+	if latest.ko.Spec.Configuration == nil || *latest.ko.Spec.Configuration != invalidConfiguration {
+		t.Errorf("sdkFind() configuration = %v, want the desired configuration", latest.ko.Spec.Configuration)
+	}
+	wantMsg := "Rule Groups Namespace is in 'UPDATE_FAILED' status: " + reason
+	terminal := ackcondition.Terminal(latest)
+	if terminal == nil || terminal.Status != corev1.ConditionTrue || *terminal.Message != wantMsg {
+		t.Errorf("sdkFind() terminal condition = %v, want message %q", terminal, wantMsg)
+	}
+	if delta := newResourceDelta(newDesired(svcsdk.RuleGroupsNamespaceStatusCodeUpdating), latest); delta.DifferentAt("Spec.Configuration") {
+		t.Errorf("sdkFind() reported a configuration difference right after the failure")
+	}
+
+	// The failure was reported: the configuration is reported as different
+	// so that the rule groups namespace is updated when the spec is fixed.
+	latest, err = rm.sdkFind(context.TODO(), newDesired(svcsdk.RuleGroupsNamespaceStatusCodeUpdateFailed))
+	if err != nil {
+		t.Fatalf("sdkFind() error = %v", err)
+	}
+	if ackcondition.Terminal(latest) != nil {
+		t.Errorf("sdkFind() reported the failure again")
+	}
+	desired := newDesired(svcsdk.RuleGroupsNamespaceStatusCodeUpdateFailed)
+	desired.ko.Spec.Configuration = aws.String(validConfiguration)
+	if delta := newResourceDelta(desired, latest); !delta.DifferentAt("Spec.Configuration") {
+		t.Errorf("sdkFind() reported no configuration difference for a failed rule groups namespace")
+	}
+
+	// Once active again, the configuration AMP returns is observed.
+	api.description.Status.StatusCode = aws.String(svcsdk.RuleGroupsNamespaceStatusCodeActive)
+	api.description.Status.StatusReason = nil
+	latest, err = rm.sdkFind(context.TODO(), newDesired(svcsdk.RuleGroupsNamespaceStatusCodeUpdating))
+	if err != nil {
+		t.Fatalf("sdkFind() error = %v", err)
+	}
+	if latest.ko.Spec.Configuration == nil || *latest.ko.Spec.Configuration != validConfiguration {
+		t.Errorf("sdkFind() configuration = %v, want the configuration returned by AMP", latest.ko.Spec.Configuration)
+	}
+}
+
+func Test_sdkFind_stalled(t *testing.T) {
+	api := &describeAPI{description: &svcsdk.RuleGroupsNamespaceDescription{
+		Name: aws.String("rules"),
+		Data: []byte("groups: []\n"),
+		Status: &svcsdk.RuleGroupsNamespaceStatus{
+			StatusCode: aws.String(svcsdk.RuleGroupsNamespaceStatusCodeUpdating),
+		},
+	}}
+	rm := &resourceManager{sdkapi: api, metrics: ackmetrics.NewMetrics("prometheusservice")}
+	newDesired := func(statusCode string, startTime time.Time) *resource {
+		ko := &svcapitypes.RuleGroupsNamespace{}
+		ko.Spec.Name = aws.String("rules")
+		ko.Spec.WorkspaceID = aws.String("ws-1")
+		ko.Spec.Configuration = aws.String("groups: []\n")
+		ko.Status.Status = &svcapitypes.RuleGroupsNamespaceStatus_SDK{StatusCode: aws.String(statusCode)}
+		ko.Status.OperationStartTime = &metav1.Time{Time: startTime}
+		return &resource{ko}
+	}
+
+	// The update just started: its start time is recorded.
+	before := time.Now()
+	latest, err := rm.sdkFind(context.TODO(), newDesired(svcsdk.RuleGroupsNamespaceStatusCodeActive, before.Add(-time.Hour)))
+	if err != nil {
+		t.Fatalf("sdkFind() error = %v", err)
+	}
+	if start := latest.ko.Status.OperationStartTime; start == nil || start.Time.Before(before) {
+		t.Errorf("sdkFind() operation start time = %v, want the current time", start)
+	}
+
+	// The update has been ongoing for longer than the stalled timeout.
+	startTime := before.Add(-time.Hour)
+	latest, err = rm.sdkFind(context.TODO(), newDesired(svcsdk.RuleGroupsNamespaceStatusCodeUpdating, startTime))
+	var requeue *ackrequeue.RequeueNeededAfter
+	if !errors.As(err, &requeue) || requeue.Duration() != ampstatus.StalledRequeueAfter {
+		t.Fatalf("sdkFind() error = %v, want a requeue after %v", err, ampstatus.StalledRequeueAfter)
+	}
+	if !latest.ko.Status.OperationStartTime.Equal(&metav1.Time{Time: startTime}) {
+		t.Errorf("sdkFind() operation start time = %v, want %v", latest.ko.Status.OperationStartTime, startTime)
+	}
+	stalled := ackcondition.FirstOfType(latest, ampstatus.ConditionTypeStalled)
+	if stalled == nil || stalled.Status != corev1.ConditionTrue {
+		t.Errorf("sdkFind() stalled condition = %v, want True", stalled)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+var (
+	ErrConfigurationSourcesSet     = errors.New("only one of spec.configuration, spec.groups and spec.configurationFrom may be set")
+	ErrConfigurationSourceMissing  = errors.New("one of spec.configuration, spec.groups or spec.configurationFrom must be set")
+	ErrConfigurationFromUnresolved = errors.New("spec.configurationFrom has not been resolved")
+)
+
+// configurationSource returns the name of the spec field the rule groups of
+// the supplied resource are taken from: "configuration", "groups" or
+// "configurationFrom". An error is returned unless exactly one of them is
+// set.
+func configurationSource(ko *svcapitypes.RuleGroupsNamespace) (string, error) {
+	var sources []string
+	if ko.Spec.Configuration != nil {
+		sources = append(sources, "configuration")
+	}
+	if len(ko.Spec.Groups) > 0 {
+		sources = append(sources, "groups")
+	}
+	if len(ko.Spec.ConfigurationFrom) > 0 {
+		sources = append(sources, "configurationFrom")
+	}
+	switch len(sources) {
+	case 0:
+		return "", ErrConfigurationSourceMissing
+	case 1:
+		return sources[0], nil
+	}
+	return "", ErrConfigurationSourcesSet
+}
+
+// ruleGroupsFile is the AMP (Prometheus rules file) YAML representation of a
+// list of rule groups.
+type ruleGroupsFile struct {
+	Groups []ruleGroupYAML `yaml:"groups"`
+}
+
+type ruleGroupYAML struct {
+	Name     string     `yaml:"name"`
+	Interval string     `yaml:"interval,omitempty"`
+	Rules    []ruleYAML `yaml:"rules"`
+}
+
+type ruleYAML struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// ruleGroupsNamespaceData returns the rule groups configuration that is sent
+// to AMP for the supplied rule groups namespace. It is either the raw
+// Spec.Configuration or Spec.Groups rendered to YAML. Spec.ConfigurationFrom
+// is resolved into Spec.Configuration by ResolveReferences before the
+// resource reaches the sdk functions. A terminal error is returned if the
+// spec is invalid, since retrying will not fix it.
+func ruleGroupsNamespaceData(
+	ko *svcapitypes.RuleGroupsNamespace,
+) ([]byte, error) {
+	source, err := configurationSource(ko)
+	if err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+	switch source {
+	case "groups":
+		data, err := renderRuleGroups(ko.Spec.Groups)
+		if err != nil {
+			return nil, ackerr.NewTerminalError(err)
+		}
+		return data, nil
+	case "configurationFrom":
+		return nil, ErrConfigurationFromUnresolved
+	}
+	return []byte(*ko.Spec.Configuration), nil
+}
+
+// renderRuleGroups validates the supplied rule groups and renders them to the
+// AMP rule groups YAML format.
+func renderRuleGroups(groups []*svcapitypes.RuleGroup) ([]byte, error) {
+	file := ruleGroupsFile{Groups: make([]ruleGroupYAML, 0, len(groups))}
+	for i, g := range groups {
+		if g == nil {
+			continue
+		}
+		if stringValue(g.Name) == "" {
+			return nil, fmt.Errorf("spec.groups[%d]: name must be set", i)
+		}
+		group := ruleGroupYAML{
+			Name:     *g.Name,
+			Interval: stringValue(g.Interval),
+			Rules:    make([]ruleYAML, 0, len(g.Rules)),
+		}
+		for j, r := range g.Rules {
+			if r == nil {
+				continue
+			}
+			if err := validateRule(r); err != nil {
+				return nil, fmt.Errorf("spec.groups[%d].rules[%d]: %v", i, j, err)
+			}
+			group.Rules = append(group.Rules, ruleYAML{
+				Record:      stringValue(r.Record),
+				Alert:       stringValue(r.Alert),
+				Expr:        *r.Expr,
+				For:         stringValue(r.For),
+				Labels:      stringMap(r.Labels),
+				Annotations: stringMap(r.Annotations),
+			})
+		}
+		file.Groups = append(file.Groups, group)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&file); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// validateRule checks that the supplied rule is either a recording or an
+// alerting rule and only uses the fields valid for its kind.
+func validateRule(r *svcapitypes.Rule) error {
+	record := stringValue(r.Record)
+	alert := stringValue(r.Alert)
+	if record == "" && alert == "" {
+		return errors.New("one of record or alert must be set")
+	}
+	if record != "" && alert != "" {
+		return errors.New("only one of record or alert may be set")
+	}
+	if stringValue(r.Expr) == "" {
+		return errors.New("expr must be set")
+	}
+	if record != "" {
+		if r.For != nil {
+			return errors.New("for is only valid for alerting rules")
+		}
+		if len(r.Annotations) > 0 {
+			return errors.New("annotations are only valid for alerting rules")
+		}
+	}
+	return nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func stringMap(m map[string]*string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = stringValue(v)
+	}
+	return out
+}
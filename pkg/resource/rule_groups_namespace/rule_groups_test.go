@@ -0,0 +1,179 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func Test_renderRuleGroups(t *testing.T) {
+	groups := []*svcapitypes.RuleGroup{
+		{
+			Name:     aws.String("test"),
+			Interval: aws.String("1m"),
+			Rules: []*svcapitypes.Rule{
+				{
+					Record: aws.String("metric:recording_rule"),
+					Expr:   aws.String("avg(rate(container_cpu_usage_seconds_total[5m]))"),
+				},
+				{
+					Alert: aws.String("HighCPU"),
+					Expr:  aws.String("metric:recording_rule > 0.9"),
+					For:   aws.String("5m"),
+					Labels: map[string]*string{
+						"severity": aws.String("page"),
+						"team":     aws.String("infra"),
+					},
+					Annotations: map[string]*string{
+						"summary": aws.String("High CPU usage"),
+					},
+				},
+			},
+		},
+	}
+	want := `groups:
+  - name: test
+    interval: 1m
+    rules:
+      - record: metric:recording_rule
+        expr: avg(rate(container_cpu_usage_seconds_total[5m]))
+      - alert: HighCPU
+        expr: metric:recording_rule > 0.9
+        for: 5m
+        labels:
+          severity: page
+          team: infra
+        annotations:
+          summary: High CPU usage
+`
+	got, err := renderRuleGroups(groups)
+	if err != nil {
+		t.Fatalf("renderRuleGroups() unexpected error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("renderRuleGroups() = %s, want %s", got, want)
+	}
+}
+
+func Test_renderRuleGroups_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		group *svcapitypes.RuleGroup
+	}{
+		{
+			name: "missing group name",
+			group: &svcapitypes.RuleGroup{
+				Rules: []*svcapitypes.Rule{
+					{Record: aws.String("r"), Expr: aws.String("up")},
+				},
+			},
+		},
+		{
+			name: "missing expr",
+			group: &svcapitypes.RuleGroup{
+				Name: aws.String("test"),
+				Rules: []*svcapitypes.Rule{
+					{Record: aws.String("r")},
+				},
+			},
+		},
+		{
+			name: "neither record nor alert",
+			group: &svcapitypes.RuleGroup{
+				Name: aws.String("test"),
+				Rules: []*svcapitypes.Rule{
+					{Expr: aws.String("up")},
+				},
+			},
+		},
+		{
+			name: "both record and alert",
+			group: &svcapitypes.RuleGroup{
+				Name: aws.String("test"),
+				Rules: []*svcapitypes.Rule{
+					{Record: aws.String("r"), Alert: aws.String("a"), Expr: aws.String("up")},
+				},
+			},
+		},
+		{
+			name: "for on a recording rule",
+			group: &svcapitypes.RuleGroup{
+				Name: aws.String("test"),
+				Rules: []*svcapitypes.Rule{
+					{Record: aws.String("r"), Expr: aws.String("up"), For: aws.String("5m")},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := renderRuleGroups([]*svcapitypes.RuleGroup{tt.group}); err == nil {
+				t.Errorf("renderRuleGroups() expected an error")
+			}
+		})
+	}
+}
+
+func Test_ruleGroupsNamespaceData(t *testing.T) {
+	groups := []*svcapitypes.RuleGroup{
+		{
+			Name: aws.String("test"),
+			Rules: []*svcapitypes.Rule{
+				{Record: aws.String("r"), Expr: aws.String("up")},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		spec    svcapitypes.RuleGroupsNamespaceSpec
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "configuration",
+			spec: svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups: []\n")},
+			want: "groups: []\n",
+		},
+		{
+			name: "groups",
+			spec: svcapitypes.RuleGroupsNamespaceSpec{Groups: groups},
+			want: "groups:\n  - name: test\n    rules:\n      - record: r\n        expr: up\n",
+		},
+		{
+			name:    "configuration and groups",
+			spec:    svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups: []\n"), Groups: groups},
+			wantErr: true,
+		},
+		{
+			name:    "neither configuration nor groups",
+			spec:    svcapitypes.RuleGroupsNamespaceSpec{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ruleGroupsNamespaceData(&svcapitypes.RuleGroupsNamespace{Spec: tt.spec})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ruleGroupsNamespaceData() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ruleGroupsNamespaceData() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -87,19 +87,64 @@ func (rm *resourceManager) sdkFind(
 	// the original Kubernetes object we passed to the function
 	ko := r.ko.DeepCopy()
 
-	// The data field stores the base64 encoding of the rule groups namespace.
-	// However, to make the CR's more user friendly, we convert the base64 encoding to a
-	// string. We store it in a custom created field.
-	if resp.RuleGroupsNamespace.Data != nil {
+	// The status is set below as well, but it is needed here to handle
+	// failed creations and updates.
+	if resp.RuleGroupsNamespace.Status != nil {
+		ko.Status.Status = &svcapitypes.RuleGroupsNamespaceStatus_SDK{
+			StatusCode:   resp.RuleGroupsNamespace.Status.StatusCode,
+			StatusReason: resp.RuleGroupsNamespace.Status.StatusReason,
+		}
+	} else {
+		ko.Status.Status = nil
+	}
+
+	// Status changes that the state machine does not expect are logged.
+	observeRuleGroupsNamespaceTransition(ctx, r, ko)
+	// A rule groups namespace that AMP leaves CREATING, UPDATING or DELETING
+	// for too long is reported as stalled and reconciled less often.
+	setRuleGroupsNamespaceOperationStartTime(r, ko)
+	if err := rm.ensureRuleGroupsNamespaceNotStalled(ko); err != nil {
+		return &resource{ko}, err
+	}
+
+	// Like alert manager definitions, rule groups namespaces are validated
+	// asynchronously: an invalid configuration is accepted by the API, and
+	// the rule groups namespace ends up in the CREATION_FAILED or
+	// UPDATE_FAILED status. AMP then returns the last valid configuration, or
+	// none at all for CREATION_FAILED, instead of the one that failed.
+	//
+	// Right after the rule groups namespace fails, we keep the configuration
+	// the user desires instead of the one AMP returns, and report the reason
+	// AMP gives in a terminal condition, like the validation errors returned
+	// by the API directly.
+	if ruleGroupsNamespaceStatusFailed(&resource{ko}) && !ruleGroupsNamespaceStatusFailed(r) {
+		msg := ruleGroupsNamespaceFailedMessage(&resource{ko})
+		ackcondition.SetTerminal(&resource{ko}, corev1.ConditionTrue, &msg, nil)
+		ackcondition.SetSynced(&resource{ko}, corev1.ConditionTrue, nil, nil)
+	} else if resp.RuleGroupsNamespace.Data != nil {
+		// The data field stores the base64 encoding of the rule groups namespace.
+		// However, to make the CR's more user friendly, we convert the base64 encoding to a
+		// string. We store it in a custom created field.
 		// Convert the base64 byte array to a human-readable string
 		ruleGroupsNamespaceDataString := string(resp.RuleGroupsNamespace.Data)
 		ko.Spec.Configuration = &ruleGroupsNamespaceDataString
-
-		// Remove the data field as it is not user facing
-		resp.RuleGroupsNamespace.Data = nil
+		// The observed state is always represented by the raw configuration,
+		// even when the desired state uses the typed rule groups.
+		ko.Spec.Groups = nil
 	} else {
 		ko.Spec.Configuration = nil
 	}
+	// Remove the data field as it is not user facing
+	resp.RuleGroupsNamespace.Data = nil
+
+	// Once the failure was reported, the configuration is always considered
+	// different from the desired one, so that fixing the spec, or changing it
+	// back to the last valid configuration that AMP still returns, updates
+	// the rule groups namespace and recovers it.
+	if ruleGroupsNamespaceStatusFailed(r) {
+		ko.Spec.Configuration = nil
+		ko.Spec.Groups = nil
+	}
 
 	if ko.Status.ACKResourceMetadata == nil {
 		ko.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{}
@@ -180,16 +225,20 @@ func (rm *resourceManager) sdkCreate(
 	defer func() {
 		exit(err)
 	}()
+	if notReady, err := rm.ensureWorkspaceActive(ctx, desired); err != nil {
+		return notReady, err
+	}
 	input, err := rm.newCreateRequestPayload(ctx, desired)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert the string version of the configuration to a byte slice
-	// because the API expects a base64 encoding. The conversion to base64
-	// is handled automatically by k8s.
-	if desired.ko.Spec.Configuration != nil {
-		input.Data = []byte(*desired.ko.Spec.Configuration)
+	// Convert the configuration, or the rendered rule groups, to a byte
+	// slice because the API expects a base64 encoding. The conversion to
+	// base64 is handled automatically by k8s.
+	input.Data, err = ruleGroupsNamespaceData(desired.ko)
+	if err != nil {
+		return nil, err
 	}
 
 	var resp *svcsdk.CreateRuleGroupsNamespaceOutput
@@ -241,6 +290,9 @@ func (rm *resourceManager) sdkCreate(
 
 	rm.setStatusDefaults(ko)
 
+	// Record when the rule groups namespace started being created, to
+	// report it as stalled if it stays CREATING for too long.
+	setRuleGroupsNamespaceOperationStartTime(desired, ko)
 	// We expect the rule group to be in 'creating' status since we just
 	// issued the call to create it, but I suppose it doesn't hurt to check
 	// here.
@@ -0,0 +1,286 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/promql"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/yamlvalidation"
+)
+
+var (
+	metricNameRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRE  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// validateRuleGroupsNamespace validates the rule groups of the supplied
+// resource locally, the way Prometheus validates a rules file, so that
+// invalid rules are rejected before they reach AMP.
+func validateRuleGroupsNamespace(
+	ko *svcapitypes.RuleGroupsNamespace,
+) field.ErrorList {
+	specPath := field.NewPath("spec")
+	source, err := configurationSource(ko)
+	switch {
+	case err == ErrConfigurationSourceMissing:
+		return field.ErrorList{field.Required(specPath.Child("configuration"), err.Error())}
+	case err != nil:
+		return field.ErrorList{field.Forbidden(specPath, err.Error())}
+	case source == "groups":
+		return validateRuleGroups(specPath.Child("groups"), ko.Spec.Groups)
+	case source == "configurationFrom":
+		return validateConfigurationFrom(specPath.Child("configurationFrom"), ko.Spec.ConfigurationFrom)
+	}
+	return validateRuleGroupsConfiguration(specPath.Child("configuration"), *ko.Spec.Configuration)
+}
+
+// validateConfigurationFrom validates the ConfigMap key references of a rule
+// groups namespace. The referenced configurations are validated when they are
+// resolved, since they may change after admission.
+func validateConfigurationFrom(
+	path *field.Path,
+	refs []*svcapitypes.ConfigMapKeyReference,
+) field.ErrorList {
+	var errs field.ErrorList
+	for i, ref := range refs {
+		refPath := path.Index(i)
+		if ref == nil {
+			errs = append(errs, field.Required(refPath, "ConfigMap key reference must not be empty"))
+			continue
+		}
+		if stringValue(ref.Name) == "" {
+			errs = append(errs, field.Required(refPath.Child("name"), "ConfigMap name must not be empty"))
+		}
+		if stringValue(ref.Key) == "" {
+			errs = append(errs, field.Required(refPath.Child("key"), "ConfigMap key must not be empty"))
+		}
+	}
+	return errs
+}
+
+// validateRuleGroups validates the typed rule groups of a rule groups
+// namespace.
+func validateRuleGroups(
+	path *field.Path,
+	groups []*svcapitypes.RuleGroup,
+) field.ErrorList {
+	var errs field.ErrorList
+	names := map[string]bool{}
+	for i, g := range groups {
+		groupPath := path.Index(i)
+		if g == nil {
+			continue
+		}
+		name := stringValue(g.Name)
+		switch {
+		case name == "":
+			errs = append(errs, field.Required(groupPath.Child("name"), "group name must not be empty"))
+		case names[name]:
+			errs = append(errs, field.Duplicate(groupPath.Child("name"), name))
+		}
+		names[name] = true
+		if g.Interval != nil {
+			if err := validateDuration(*g.Interval); err != nil {
+				errs = append(errs, field.Invalid(groupPath.Child("interval"), *g.Interval, err.Error()))
+			}
+		}
+		for j, r := range g.Rules {
+			if r == nil {
+				continue
+			}
+			errs = append(errs, validateTypedRule(groupPath.Child("rules").Index(j), r)...)
+		}
+	}
+	return errs
+}
+
+func validateTypedRule(
+	path *field.Path,
+	r *svcapitypes.Rule,
+) field.ErrorList {
+	var errs field.ErrorList
+	if err := validateRule(r); err != nil {
+		errs = append(errs, field.Forbidden(path, err.Error()))
+	}
+	if r.Record != nil && !metricNameRE.MatchString(*r.Record) {
+		errs = append(errs, field.Invalid(path.Child("record"), *r.Record, "invalid recording rule name"))
+	}
+	if stringValue(r.Expr) != "" {
+		if err := promql.Validate(*r.Expr); err != nil {
+			errs = append(errs, field.Invalid(path.Child("expr"), *r.Expr, err.Error()))
+		}
+	}
+	if r.For != nil {
+		if err := validateDuration(*r.For); err != nil {
+			errs = append(errs, field.Invalid(path.Child("for"), *r.For, err.Error()))
+		}
+	}
+	for name := range r.Labels {
+		if !labelNameRE.MatchString(name) {
+			errs = append(errs, field.Invalid(path.Child("labels").Key(name), name, "invalid label name"))
+		}
+	}
+	for name := range r.Annotations {
+		if !labelNameRE.MatchString(name) {
+			errs = append(errs, field.Invalid(path.Child("annotations").Key(name), name, "invalid annotation name"))
+		}
+	}
+	return errs
+}
+
+func validateDuration(s string) error {
+	if _, err := model.ParseDuration(s); err != nil {
+		return fmt.Errorf("not a valid duration string: %q", s)
+	}
+	return nil
+}
+
+// validateRuleGroupsConfiguration validates a rule groups configuration in
+// the AMP (Prometheus rules file) YAML format.
+func validateRuleGroupsConfiguration(
+	path *field.Path,
+	configuration string,
+) field.ErrorList {
+	v := yamlvalidation.New(path, configuration)
+	root, ok := v.Parse(configuration, 0)
+	switch {
+	case !ok:
+	case root == nil:
+		v.Errorf(1, "no rule groups found")
+	default:
+		validateRuleGroupsFile(v, root)
+	}
+	return v.Errors()
+}
+
+func validateRuleGroupsFile(v *yamlvalidation.Validator, root *yaml.Node) {
+	fields := v.Mapping(root, "rule groups file", "groups")
+	if fields == nil {
+		return
+	}
+	if fields["groups"] == nil {
+		v.Errorf(root.Line, "field \"groups\" must be set")
+		return
+	}
+	names := map[string]bool{}
+	for i, g := range v.Sequence(fields["groups"], "groups") {
+		validateRuleGroup(v, fmt.Sprintf("groups[%d]", i), g, names)
+	}
+}
+
+func validateRuleGroup(
+	v *yamlvalidation.Validator,
+	where string,
+	n *yaml.Node,
+	names map[string]bool,
+) {
+	fields := v.Mapping(n, where, "name", "interval", "limit", "query_offset", "rules")
+	if fields == nil {
+		return
+	}
+	name, ok := v.Scalar(fields["name"], where+".name")
+	switch {
+	case !ok && fields["name"] == nil, ok && name == "":
+		v.Errorf(n.Line, "%s: group name must not be empty", where)
+	case ok && names[name]:
+		v.Errorf(fields["name"].Line, "%s: group name %q is repeated in the same configuration", where, name)
+	}
+	names[name] = true
+	v.Duration(fields["interval"], where+".interval")
+	v.Duration(fields["query_offset"], where+".query_offset")
+	if limit, ok := v.Scalar(fields["limit"], where+".limit"); ok {
+		if _, err := strconv.Atoi(limit); err != nil {
+			v.Errorf(fields["limit"].Line, "%s.limit: must be an integer", where)
+		}
+	}
+	for j, r := range v.Sequence(fields["rules"], where+".rules") {
+		validateConfigurationRule(v, fmt.Sprintf("%s.rules[%d]", where, j), r)
+	}
+}
+
+func validateConfigurationRule(
+	v *yamlvalidation.Validator,
+	where string,
+	n *yaml.Node,
+) {
+	fields := v.Mapping(n, where, "record", "alert", "expr", "for", "keep_firing_for", "labels", "annotations")
+	if fields == nil {
+		return
+	}
+	record, isRecord := v.Scalar(fields["record"], where+".record")
+	alert, isAlert := v.Scalar(fields["alert"], where+".alert")
+	switch {
+	case isRecord && isAlert:
+		v.Errorf(n.Line, "%s: only one of record and alert may be set", where)
+	case !isRecord && !isAlert:
+		v.Errorf(n.Line, "%s: one of record or alert must be set", where)
+	case isRecord && !metricNameRE.MatchString(record):
+		v.Errorf(fields["record"].Line, "%s.record: invalid recording rule name %q", where, record)
+	case isAlert && alert == "":
+		v.Errorf(fields["alert"].Line, "%s.alert: alert name must not be empty", where)
+	}
+
+	if fields["expr"] == nil {
+		v.Errorf(n.Line, "%s: expr must be set", where)
+	} else if expr, ok := v.Scalar(fields["expr"], where+".expr"); ok {
+		if err := promql.Validate(expr); err != nil {
+			var perr *promql.ParseError
+			if errors.As(err, &perr) {
+				line, column := v.Position(fields["expr"], perr.Line, perr.Column)
+				v.Errorf(line, "%s.expr: column %d: %s", where, column, perr.Msg)
+			} else {
+				v.Errorf(fields["expr"].Line, "%s.expr: %v", where, err)
+			}
+		}
+	}
+
+	for _, key := range []string{"for", "keep_firing_for"} {
+		if fields[key] == nil {
+			continue
+		}
+		if isRecord {
+			v.Errorf(fields[key].Line, "%s.%s: only valid for alerting rules", where, key)
+		} else {
+			v.Duration(fields[key], where+"."+key)
+		}
+	}
+	if isRecord && fields["annotations"] != nil {
+		v.Errorf(yamlvalidation.KeyLine(n, "annotations"), "%s.annotations: only valid for alerting rules", where)
+	}
+	for _, key := range []string{"labels", "annotations"} {
+		if fields[key] == nil {
+			continue
+		}
+		labels := v.Mapping(fields[key], where+"."+key)
+		if labels == nil {
+			continue
+		}
+		for i := 0; i+1 < len(fields[key].Content); i += 2 {
+			name := fields[key].Content[i]
+			if !labelNameRE.MatchString(name.Value) {
+				v.Errorf(name.Line, "%s.%s: invalid name %q", where, key, name.Value)
+			}
+			v.Scalar(labels[name.Value], fmt.Sprintf("%s.%s.%s", where, key, name.Value))
+		}
+	}
+}
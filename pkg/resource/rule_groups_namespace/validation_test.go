@@ -0,0 +1,234 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func Test_validateRuleGroupsConfiguration(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration string
+		wantErrs      []string
+	}{
+		{
+			name: "valid",
+			configuration: `groups:
+- name: test
+  interval: 1m
+  rules:
+  - record: metric:recording_rule
+    expr: avg(rate(container_cpu_usage_seconds_total[5m]))
+  - alert: HighCPU
+    expr: |
+      metric:recording_rule
+        > 0.9
+    for: 2m
+    labels:
+      severity: page
+    annotations:
+      summary: High CPU usage
+`,
+		},
+		{
+			name:          "invalid YAML",
+			configuration: "groups:\n- name: test\n  rules: [\n",
+			wantErrs:      []string{"line 3: invalid YAML"},
+		},
+		{
+			name:          "missing groups",
+			configuration: "rules: []\n",
+			wantErrs:      []string{`line 1: field "rules" is not valid in rule groups file`, `line 1: field "groups" must be set`},
+		},
+		{
+			name: "PromQL error in a plain scalar",
+			configuration: `groups:
+- name: test
+  rules:
+  - record: r
+    expr: rate(foo)
+`,
+			wantErrs: []string{`line 5: groups[0].rules[0].expr: column 16: expected type range vector in call to function "rate", got instant vector`},
+		},
+		{
+			name: "PromQL error in a block scalar",
+			configuration: `groups:
+- name: test
+  rules:
+  - alert: a
+    expr: |
+      sum(
+        rate(foo[5m]) by (job)
+`,
+			wantErrs: []string{"line 7: groups[0].rules[0].expr: column 23: unexpected <by> in aggregation"},
+		},
+		{
+			name: "PromQL error in a quoted scalar",
+			configuration: `groups:
+- name: test
+  rules:
+  - alert: a
+    expr: "foo >"
+`,
+			wantErrs: []string{"line 5: groups[0].rules[0].expr: column 17: unexpected end of input"},
+		},
+		{
+			name: "invalid rules",
+			configuration: `groups:
+- name: test
+  rules:
+  - record: r
+    alert: a
+    expr: up
+  - expr: up
+  - record: "bad name"
+    expr: up
+  - record: r
+    expr: up
+    for: 5m
+    annotations:
+      summary: s
+  - alert: a
+    expr: up
+    for: 5 minutes
+    labels:
+      bad-name: x
+    unknown: x
+- name: test
+  rules: []
+`,
+			wantErrs: []string{
+				"line 4: groups[0].rules[0]: only one of record and alert may be set",
+				"line 7: groups[0].rules[1]: one of record or alert must be set",
+				`line 8: groups[0].rules[2].record: invalid recording rule name "bad name"`,
+				"line 12: groups[0].rules[3].for: only valid for alerting rules",
+				"line 13: groups[0].rules[3].annotations: only valid for alerting rules",
+				`line 20: field "unknown" is not valid in groups[0].rules[4]`,
+				`line 17: groups[0].rules[4].for: not a valid duration string: "5 minutes"`,
+				`line 19: groups[0].rules[4].labels: invalid name "bad-name"`,
+				`line 21: groups[1]: group name "test" is repeated in the same configuration`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateRuleGroupsConfiguration(field.NewPath("spec", "configuration"), tt.configuration)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("validateRuleGroupsConfiguration() = %v, want %d errors", errs, len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if !strings.HasPrefix(errs[i].Detail, want) {
+					t.Errorf("validateRuleGroupsConfiguration() error %d = %q, want %q", i, errs[i].Detail, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_validateRuleGroups(t *testing.T) {
+	groups := []*svcapitypes.RuleGroup{
+		{
+			Name: aws.String("test"),
+			Rules: []*svcapitypes.Rule{
+				{Record: aws.String("r"), Expr: aws.String("sum(rate(foo[5m]))")},
+				{Alert: aws.String("a"), Expr: aws.String("foo >"), For: aws.String("5x")},
+			},
+		},
+		{
+			Name:     aws.String("test"),
+			Interval: aws.String("1m"),
+		},
+	}
+	errs := validateRuleGroups(field.NewPath("spec", "groups"), groups)
+	want := []string{
+		"spec.groups[0].rules[1].expr",
+		"spec.groups[0].rules[1].for",
+		"spec.groups[1].name",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("validateRuleGroups() = %v, want %d errors", errs, len(want))
+	}
+	for i, w := range want {
+		if errs[i].Field != w {
+			t.Errorf("validateRuleGroups() error %d field = %q, want %q", i, errs[i].Field, w)
+		}
+	}
+}
+
+func Test_validateRuleGroupsNamespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    svcapitypes.RuleGroupsNamespaceSpec
+		wantErr bool
+	}{
+		{
+			name: "configuration",
+			spec: svcapitypes.RuleGroupsNamespaceSpec{Configuration: aws.String("groups: []\n")},
+		},
+		{
+			name: "configuration and groups",
+			spec: svcapitypes.RuleGroupsNamespaceSpec{
+				Configuration: aws.String("groups: []\n"),
+				Groups:        []*svcapitypes.RuleGroup{{Name: aws.String("test")}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "configurationFrom",
+			spec: svcapitypes.RuleGroupsNamespaceSpec{
+				ConfigurationFrom: []*svcapitypes.ConfigMapKeyReference{
+					{Name: aws.String("rules"), Key: aws.String("rules.yaml")},
+				},
+			},
+		},
+		{
+			name: "configurationFrom without key",
+			spec: svcapitypes.RuleGroupsNamespaceSpec{
+				ConfigurationFrom: []*svcapitypes.ConfigMapKeyReference{
+					{Name: aws.String("rules")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "configuration and configurationFrom",
+			spec: svcapitypes.RuleGroupsNamespaceSpec{
+				Configuration: aws.String("groups: []\n"),
+				ConfigurationFrom: []*svcapitypes.ConfigMapKeyReference{
+					{Name: aws.String("rules"), Key: aws.String("rules.yaml")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "neither configuration nor groups",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateRuleGroupsNamespace(&svcapitypes.RuleGroupsNamespace{Spec: tt.spec})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("validateRuleGroupsNamespace() = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	ackrtwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-prometheusservice-services-k8s-aws-v1alpha1-rulegroupsnamespace,mutating=false,failurePolicy=fail,sideEffects=None,groups=prometheusservice.services.k8s.aws,resources=rulegroupsnamespaces,verbs=create;update,versions=v1alpha1,name=vrulegroupsnamespace.prometheusservice.services.k8s.aws,admissionReviewVersions=v1
+
+// validator is a validating admission webhook for RuleGroupsNamespace
+// resources. It rejects rule groups that AMP would fail to load, either
+// synchronously or asynchronously with an UPDATE_FAILED status.
+type validator struct{}
+
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return validate(obj)
+}
+
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldKo, ok := oldObj.(*svcapitypes.RuleGroupsNamespace)
+	if !ok {
+		return fmt.Errorf("expected a RuleGroupsNamespace but got %T", oldObj)
+	}
+	newKo, ok := newObj.(*svcapitypes.RuleGroupsNamespace)
+	if !ok {
+		return fmt.Errorf("expected a RuleGroupsNamespace but got %T", newObj)
+	}
+	// Only validate spec changes. Resources that were created before the
+	// webhook was installed must still accept metadata updates, such as
+	// the controller removing its finalizer.
+	if newKo.DeletionTimestamp != nil || reflect.DeepEqual(oldKo.Spec, newKo.Spec) {
+		return nil
+	}
+	return validate(newObj)
+}
+
+func (v *validator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func validate(obj runtime.Object) error {
+	ko, ok := obj.(*svcapitypes.RuleGroupsNamespace)
+	if !ok {
+		return fmt.Errorf("expected a RuleGroupsNamespace but got %T", obj)
+	}
+	if errs := validateRuleGroupsNamespace(ko); len(errs) > 0 {
+		return apierrors.NewInvalid(
+			svcapitypes.GroupVersion.WithKind("RuleGroupsNamespace").GroupKind(),
+			ko.Name,
+			errs,
+		)
+	}
+	return nil
+}
+
+func setupWebhookWithManager(mgr ctrlrt.Manager) error {
+	return ctrlrt.NewWebhookManagedBy(mgr).
+		For(&svcapitypes.RuleGroupsNamespace{}).
+		WithValidator(&validator{}).
+		Complete()
+}
+
+func init() {
+	if err := ackrtwebhook.RegisterWebhook(ackrtwebhook.New(
+		svcapitypes.GroupVersion.Version,
+		"RuleGroupsNamespace",
+		"validating",
+		setupWebhookWithManager,
+	)); err != nil {
+		panic(err)
+	}
+}
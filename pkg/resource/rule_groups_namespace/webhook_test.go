@@ -0,0 +1,61 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rule_groups_namespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func Test_validator(t *testing.T) {
+	valid := &svcapitypes.RuleGroupsNamespace{
+		Spec: svcapitypes.RuleGroupsNamespaceSpec{
+			Configuration: aws.String("groups:\n- name: test\n  rules:\n  - record: r\n    expr: up\n"),
+		},
+	}
+	invalid := &svcapitypes.RuleGroupsNamespace{
+		Spec: svcapitypes.RuleGroupsNamespaceSpec{
+			Configuration: aws.String("groups:\n- name: test\n  rules:\n  - record: r\n    expr: rate(up)\n"),
+		},
+	}
+	deleting := invalid.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{}
+	relabelled := invalid.DeepCopy()
+	relabelled.Labels = map[string]string{"k": "v"}
+
+	ctx := context.TODO()
+	v := &validator{}
+	if err := v.ValidateCreate(ctx, valid); err != nil {
+		t.Errorf("ValidateCreate() unexpected error = %v", err)
+	}
+	if err := v.ValidateCreate(ctx, invalid); err == nil {
+		t.Errorf("ValidateCreate() expected an error")
+	}
+	if err := v.ValidateUpdate(ctx, valid, invalid); err == nil {
+		t.Errorf("ValidateUpdate() expected an error")
+	}
+	// Metadata only updates of invalid resources, such as removing the
+	// finalizer, are accepted.
+	if err := v.ValidateUpdate(ctx, invalid, relabelled); err != nil {
+		t.Errorf("ValidateUpdate() unexpected error = %v", err)
+	}
+	if err := v.ValidateUpdate(ctx, valid, deleting); err != nil {
+		t.Errorf("ValidateUpdate() unexpected error = %v", err)
+	}
+}
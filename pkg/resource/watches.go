@@ -0,0 +1,101 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resource
+
+import (
+	"strings"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// WatchSetupFunc adds watches on objects other than the custom resources of a
+// kind, such as the ConfigMaps they reference, to the supplied builder of the
+// controller enqueueing the affected custom resources of that kind.
+type WatchSetupFunc func(ctrlrt.Manager, *builder.Builder) error
+
+// ControllerSetupFunc sets up a controller of its own, such as a controller
+// maintaining the owner references of custom resources.
+type ControllerSetupFunc func(ctrlrt.Manager) error
+
+var (
+	watchSetupFuncs      = map[string][]WatchSetupFunc{}
+	controllerSetupFuncs []ControllerSetupFunc
+)
+
+// RegisterWatches registers a function that sets up additional watches of the
+// supplied resource kind. It must be called from an init function.
+func RegisterWatches(kind string, f WatchSetupFunc) {
+	watchSetupFuncs[kind] = append(watchSetupFuncs[kind], f)
+}
+
+// RegisterController registers a function that sets up a controller of its
+// own. It must be called from an init function.
+func RegisterController(f ControllerSetupFunc) {
+	controllerSetupFuncs = append(controllerSetupFuncs, f)
+}
+
+// SetupControllers sets up the registered controllers and, for each kind of
+// the reconcilers of the supplied service controller with registered
+// watches, a controller named after the kind with the "-watches" suffix.
+// That controller only watches the objects of the registered watches and
+// reconciles the custom resources they enqueue with the reconciler of the
+// kind, so that changes of these objects are picked up even though the ACK
+// runtime only reconciles a custom resource when its generation changes.
+//
+// The custom resources enqueued by the watches are reconciled from a
+// different workqueue than the one of the ACK runtime, so a custom resource
+// may be reconciled by both controllers at the same time. AMP rejects
+// concurrent changes of a resource with a ConflictException, which is
+// retried like any other conflict.
+//
+// It must be called after the service controller is bound to the manager.
+func SetupControllers(mgr ctrlrt.Manager, sc acktypes.ServiceController) error {
+	factories := sc.GetResourceManagerFactories()
+	for _, rec := range sc.GetReconcilers() {
+		kind := rec.GroupKind().Kind
+		funcs := watchSetupFuncs[kind]
+		rmf, ok := factories[rec.GroupKind().String()]
+		if len(funcs) == 0 || !ok {
+			continue
+		}
+		b := ctrlrt.NewControllerManagedBy(mgr).
+			Named(strings.ToLower(kind)+"-watches").
+			For(
+				rmf.ResourceDescriptor().EmptyRuntimeObject(),
+				// Changes of the custom resources themselves are reconciled
+				// by the controller of the ACK runtime.
+				builder.WithPredicates(predicate.NewPredicateFuncs(func(client.Object) bool {
+					return false
+				})),
+			)
+		for _, f := range funcs {
+			if err := f(mgr, b); err != nil {
+				return err
+			}
+		}
+		if err := b.Complete(rec); err != nil {
+			return err
+		}
+	}
+	for _, f := range controllerSetupFuncs {
+		if err := f(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
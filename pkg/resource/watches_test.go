@@ -0,0 +1,156 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// countingManager is a controller manager counting the runnables, such as
+// controllers, added to it.
+type countingManager struct {
+	ctrlrt.Manager
+	added int
+}
+
+func (m *countingManager) Add(r manager.Runnable) error {
+	m.added++
+	return m.Manager.Add(r)
+}
+
+// fakeReconciler is a reconciler of the supplied kind.
+type fakeReconciler struct {
+	acktypes.AWSResourceReconciler
+	kind string
+}
+
+func (r *fakeReconciler) GroupKind() *metav1.GroupKind {
+	return &metav1.GroupKind{Group: svcapitypes.GroupVersion.Group, Kind: r.kind}
+}
+
+func (r *fakeReconciler) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+// fakeFactory is a resource manager factory of the custom resources of the
+// type of the supplied object.
+type fakeFactory struct {
+	acktypes.AWSResourceManagerFactory
+	acktypes.AWSResourceDescriptor
+	obj client.Object
+}
+
+func (f *fakeFactory) ResourceDescriptor() acktypes.AWSResourceDescriptor {
+	return f
+}
+
+func (f *fakeFactory) EmptyRuntimeObject() client.Object {
+	return f.obj
+}
+
+// fakeServiceController is a service controller with reconcilers of
+// workspaces and rule groups namespaces.
+type fakeServiceController struct {
+	acktypes.ServiceController
+}
+
+func (sc *fakeServiceController) GetReconcilers() []acktypes.AWSResourceReconciler {
+	return []acktypes.AWSResourceReconciler{
+		&fakeReconciler{kind: "Workspace"},
+		&fakeReconciler{kind: "RuleGroupsNamespace"},
+	}
+}
+
+func (sc *fakeServiceController) GetResourceManagerFactories() map[string]acktypes.AWSResourceManagerFactory {
+	group := svcapitypes.GroupVersion.Group
+	return map[string]acktypes.AWSResourceManagerFactory{
+		"Workspace." + group:           &fakeFactory{obj: &svcapitypes.Workspace{}},
+		"RuleGroupsNamespace." + group: &fakeFactory{obj: &svcapitypes.RuleGroupsNamespace{}},
+	}
+}
+
+func newTestManager(t *testing.T) *countingManager {
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mgr, err := ctrlrt.NewManager(&rest.Config{Host: "https://127.0.0.1:0"}, ctrlrt.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		MapperProvider: func(*rest.Config) (meta.RESTMapper, error) {
+			return meta.NewDefaultRESTMapper(nil), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &countingManager{Manager: mgr}
+}
+
+func TestSetupControllers(t *testing.T) {
+	defer func(watches map[string][]WatchSetupFunc, controllers []ControllerSetupFunc) {
+		watchSetupFuncs, controllerSetupFuncs = watches, controllers
+	}(watchSetupFuncs, controllerSetupFuncs)
+	watchSetupFuncs, controllerSetupFuncs = map[string][]WatchSetupFunc{}, nil
+
+	var watched []string
+	watch := func(name string) WatchSetupFunc {
+		return func(_ ctrlrt.Manager, b *builder.Builder) error {
+			watched = append(watched, name)
+			b.Watches(&source.Kind{Type: &svcapitypes.Workspace{}}, &handler.EnqueueRequestForObject{})
+			return nil
+		}
+	}
+	RegisterWatches("RuleGroupsNamespace", watch("first"))
+	RegisterWatches("RuleGroupsNamespace", watch("second"))
+	// Watches of kinds without reconciler are not set up.
+	RegisterWatches("LoggingConfiguration", watch("unbound"))
+	var controllers int
+	RegisterController(func(ctrlrt.Manager) error {
+		controllers++
+		return nil
+	})
+
+	mgr := newTestManager(t)
+	if err := SetupControllers(mgr, &fakeServiceController{}); err != nil {
+		t.Fatalf("SetupControllers() error = %v", err)
+	}
+	if len(watched) != 2 || watched[0] != "first" || watched[1] != "second" {
+		t.Errorf("set up watches %v, want [first second]", watched)
+	}
+	// Only the rule groups namespaces have watches, so only their watch
+	// controller is added to the manager.
+	if mgr.added != 1 {
+		t.Errorf("added %d controllers to the manager, want 1", mgr.added)
+	}
+	if controllers != 1 {
+		t.Errorf("set up %d registered controllers, want 1", controllers)
+	}
+}
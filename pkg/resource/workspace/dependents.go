@@ -0,0 +1,217 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspaceowner"
+)
+
+const (
+	// ConditionTypeDependentsExist is set to True on workspaces whose
+	// deletion waits for the resources created in them to be deleted.
+	ConditionTypeDependentsExist ackv1alpha1.ConditionType = "DependentsExist"
+)
+
+var (
+	// dependentsRequeueAfter is the delay before the deletion of a workspace
+	// with dependents is attempted again.
+	dependentsRequeueAfter = 15 * time.Second
+)
+
+// dependent is a custom resource created in a workspace.
+type dependent struct {
+	kind string
+	obj  client.Object
+}
+
+func (d dependent) String() string {
+	return fmt.Sprintf("%s %s/%s", d.kind, d.obj.GetNamespace(), d.obj.GetName())
+}
+
+// listDependents returns the RuleGroupsNamespace, AlertManagerDefinition and
+// LoggingConfiguration resources, in all namespaces, created in the supplied
+// workspace.
+func listDependents(
+	ctx context.Context,
+	kc client.Reader,
+	ws *svcapitypes.Workspace,
+) ([]dependent, error) {
+	var dependents []dependent
+
+	rgns := &svcapitypes.RuleGroupsNamespaceList{}
+	if err := kc.List(ctx, rgns); err != nil {
+		return nil, err
+	}
+	for i := range rgns.Items {
+		ko := &rgns.Items[i]
+		if workspaceowner.DependsOn(ws, ko.Namespace, ko.Spec.WorkspaceID, ko.Spec.WorkspaceRef) {
+			dependents = append(dependents, dependent{kind: "RuleGroupsNamespace", obj: ko})
+		}
+	}
+
+	amds := &svcapitypes.AlertManagerDefinitionList{}
+	if err := kc.List(ctx, amds); err != nil {
+		return nil, err
+	}
+	for i := range amds.Items {
+		ko := &amds.Items[i]
+		if workspaceowner.DependsOn(ws, ko.Namespace, ko.Spec.WorkspaceID, ko.Spec.WorkspaceRef) {
+			dependents = append(dependents, dependent{kind: "AlertManagerDefinition", obj: ko})
+		}
+	}
+
+	lcs := &svcapitypes.LoggingConfigurationList{}
+	if err := kc.List(ctx, lcs); err != nil {
+		return nil, err
+	}
+	for i := range lcs.Items {
+		ko := &lcs.Items[i]
+		if workspaceowner.DependsOn(ws, ko.Namespace, ko.Spec.WorkspaceID, nil) {
+			dependents = append(dependents, dependent{kind: "LoggingConfiguration", obj: ko})
+		}
+	}
+	return dependents, nil
+}
+
+// ensureNoDependents returns a copy of the supplied workspace with a
+// DependentsExist condition and an error requeueing it if resources created
+// in the workspace still exist. If the workspace has the cascade delete
+// annotation, these resources are deleted first, as are the resources with an
+// owner reference to the workspace. The dependents are read and deleted with
+// the cached client of the resource manager.
+func (rm *resourceManager) ensureNoDependents(
+	ctx context.Context,
+	r *resource,
+) (*resource, error) {
+	kc := svcresource.KubeClient(rm.rr)
+	if kc == nil {
+		return nil, errors.New("no cached client to check the dependents of the workspace with")
+	}
+	dependents, err := listDependents(ctx, kc, r.ko)
+	if err != nil {
+		return nil, err
+	}
+	if len(dependents) == 0 {
+		return nil, nil
+	}
+
+	// Dependents with an owner reference to the workspace are deleted along
+	// with it, like Kubernetes garbage collection would once the workspace
+	// is gone.
+	cascade := r.ko.Annotations[svcapitypes.AnnotationCascadeDelete] == "true"
+	var blocking, deleting []string
+	for _, d := range dependents {
+		if !cascade && !workspaceowner.IsOwnedBy(d.obj, r.ko) {
+			blocking = append(blocking, d.String())
+			continue
+		}
+		deleting = append(deleting, d.String())
+		if !d.obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if err := kc.Delete(ctx, d.obj); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+	}
+	var msg string
+	if len(blocking) > 0 {
+		msg = "cannot delete the workspace while resources are created in it: " +
+			strings.Join(blocking, ", ")
+	} else {
+		msg = "waiting for the deletion of the resources created in the workspace: " +
+			strings.Join(deleting, ", ")
+	}
+
+	ko := r.ko.DeepCopy()
+	setDeletionHeldBack(&resource{ko}, ConditionTypeDependentsExist, msg)
+	return &resource{ko}, ackrequeue.NeededAfter(errors.New(msg), dependentsRequeueAfter)
+}
+
+// dependentRequests returns a function that maps a resource created in a
+// workspace to reconcile requests for the workspaces being deleted that it
+// depends on, so that their deletion resumes as soon as it is deleted.
+func dependentRequests(kc client.Reader) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var workspaceID *string
+		var workspaceRef *svcapitypes.ResourceReferenceWrapper
+		switch ko := obj.(type) {
+		case *svcapitypes.RuleGroupsNamespace:
+			workspaceID, workspaceRef = ko.Spec.WorkspaceID, ko.Spec.WorkspaceRef
+		case *svcapitypes.AlertManagerDefinition:
+			workspaceID, workspaceRef = ko.Spec.WorkspaceID, ko.Spec.WorkspaceRef
+		case *svcapitypes.LoggingConfiguration:
+			workspaceID = ko.Spec.WorkspaceID
+		default:
+			return nil
+		}
+		list := &svcapitypes.WorkspaceList{}
+		if err := kc.List(context.TODO(), list); err != nil {
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range list.Items {
+			ws := &list.Items[i]
+			if ws.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if workspaceowner.DependsOn(ws, obj.GetNamespace(), workspaceID, workspaceRef) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: ws.Namespace,
+						Name:      ws.Name,
+					},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// setupDependentsWatch resumes the deletion of workspaces when their
+// dependents change, by enqueueing them to the controller of the workspaces.
+func setupDependentsWatch(mgr ctrlrt.Manager, b *builder.Builder) error {
+	for _, obj := range []client.Object{
+		&svcapitypes.RuleGroupsNamespace{},
+		&svcapitypes.AlertManagerDefinition{},
+		&svcapitypes.LoggingConfiguration{},
+	} {
+		b.Watches(
+			&source.Kind{Type: obj},
+			handler.EnqueueRequestsFromMapFunc(dependentRequests(mgr.GetClient())),
+		)
+	}
+	return nil
+}
+
+func init() {
+	svcresource.RegisterWatches("Workspace", setupDependentsWatch)
+}
@@ -0,0 +1,183 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+)
+
+func newTestWorkspace() *svcapitypes.Workspace {
+	ws := &svcapitypes.Workspace{}
+	ws.Namespace, ws.Name = "monitoring", "main"
+	ws.Status.WorkspaceID = aws.String("ws-1")
+	return ws
+}
+
+func newTestKubeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(svcapitypes.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func Test_ensureNoDependents(t *testing.T) {
+	rgn := &svcapitypes.RuleGroupsNamespace{}
+	rgn.Namespace, rgn.Name = "apps", "rules"
+	rgn.Spec.WorkspaceID = aws.String("ws-1")
+	amd := &svcapitypes.AlertManagerDefinition{}
+	amd.Namespace, amd.Name = "monitoring", "alerts"
+	amd.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{
+		From: &svcapitypes.ResourceReference{Name: aws.String("main")},
+	}
+	other := &svcapitypes.LoggingConfiguration{}
+	other.Namespace, other.Name = "monitoring", "logs"
+	other.Spec.WorkspaceID = aws.String("ws-2")
+
+	var kubeClient client.Client
+	rm := &resourceManager{}
+	withKubeClient := func(objs ...client.Object) {
+		kubeClient = newTestKubeClient(objs...)
+		rm.rr = svcresource.ReconcilerWithOptions(nil, svcresource.ManagerOptions{KubeClient: kubeClient})
+	}
+
+	t.Run("no client", func(t *testing.T) {
+		blocked, err := rm.ensureNoDependents(context.TODO(), &resource{newTestWorkspace()})
+		if blocked != nil || err == nil || !strings.Contains(err.Error(), "no cached client") {
+			t.Errorf("ensureNoDependents() = %v, %v, want a missing client error", blocked, err)
+		}
+	})
+
+	t.Run("no dependents", func(t *testing.T) {
+		withKubeClient(other)
+		if blocked, err := rm.ensureNoDependents(context.TODO(), &resource{newTestWorkspace()}); blocked != nil || err != nil {
+			t.Errorf("ensureNoDependents() = %v, %v, want nil, nil", blocked, err)
+		}
+	})
+
+	t.Run("dependents", func(t *testing.T) {
+		withKubeClient(rgn, amd, other)
+		blocked, err := rm.ensureNoDependents(context.TODO(), &resource{newTestWorkspace()})
+		var requeue *ackrequeue.RequeueNeededAfter
+		if !errors.As(err, &requeue) {
+			t.Fatalf("ensureNoDependents() error = %v, want a requeue", err)
+		}
+		wantMsg := "cannot delete the workspace while resources are created in it: " +
+			"RuleGroupsNamespace apps/rules, AlertManagerDefinition monitoring/alerts"
+		condition := ackcondition.FirstOfType(blocked, ConditionTypeDependentsExist)
+		if condition == nil || condition.Status != corev1.ConditionTrue || *condition.Message != wantMsg {
+			t.Errorf("ensureNoDependents() condition = %v, want message %q", condition, wantMsg)
+		}
+		if synced := ackcondition.Synced(blocked); synced == nil || synced.Status != corev1.ConditionFalse {
+			t.Errorf("ensureNoDependents() synced condition = %v", synced)
+		}
+		got := &svcapitypes.RuleGroupsNamespace{}
+		if err := kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "apps", Name: "rules"}, got); err != nil {
+			t.Errorf("dependent was deleted without the cascade delete annotation: %v", err)
+		}
+	})
+
+	t.Run("owned dependents", func(t *testing.T) {
+		ws := newTestWorkspace()
+		ws.UID = "ws-uid"
+		owned := rgn.DeepCopy()
+		owned.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: svcapitypes.GroupVersion.String(),
+			Kind:       "Workspace",
+			Name:       ws.Name,
+			UID:        ws.UID,
+		}}
+		withKubeClient(owned, amd)
+		blocked, err := rm.ensureNoDependents(context.TODO(), &resource{ws})
+		if err == nil {
+			t.Fatalf("ensureNoDependents() did not wait for the dependents to be deleted")
+		}
+		wantMsg := "cannot delete the workspace while resources are created in it: " +
+			"AlertManagerDefinition monitoring/alerts"
+		if condition := ackcondition.FirstOfType(blocked, ConditionTypeDependentsExist); condition == nil || *condition.Message != wantMsg {
+			t.Errorf("ensureNoDependents() condition = %v, want message %q", condition, wantMsg)
+		}
+		got := &svcapitypes.RuleGroupsNamespace{}
+		if err := kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "apps", Name: "rules"}, got); !apierrors.IsNotFound(err) {
+			t.Errorf("owned dependent was not deleted: %v", err)
+		}
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		withKubeClient(rgn, amd, other)
+		ws := newTestWorkspace()
+		ws.Annotations = map[string]string{svcapitypes.AnnotationCascadeDelete: "true"}
+		if _, err := rm.ensureNoDependents(context.TODO(), &resource{ws}); err == nil {
+			t.Fatalf("ensureNoDependents() did not wait for the dependents to be deleted")
+		}
+		for _, key := range []types.NamespacedName{
+			{Namespace: "apps", Name: "rules"},
+			{Namespace: "monitoring", Name: "alerts"},
+		} {
+			var obj client.Object = &svcapitypes.RuleGroupsNamespace{}
+			if key.Name == "alerts" {
+				obj = &svcapitypes.AlertManagerDefinition{}
+			}
+			if err := kubeClient.Get(context.TODO(), key, obj); !apierrors.IsNotFound(err) {
+				t.Errorf("dependent %s was not deleted: %v", key, err)
+			}
+		}
+		if err := kubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "monitoring", Name: "logs"}, other.DeepCopy()); err != nil {
+			t.Errorf("resource of another workspace was deleted: %v", err)
+		}
+		if blocked, err := rm.ensureNoDependents(context.TODO(), &resource{ws}); blocked != nil || err != nil {
+			t.Errorf("ensureNoDependents() = %v, %v once the dependents are deleted", blocked, err)
+		}
+	})
+}
+
+func Test_dependentRequests(t *testing.T) {
+	deleting := newTestWorkspace()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	deleting.Finalizers = []string{finalizerString}
+	active := newTestWorkspace()
+	active.Name = "active"
+	active.Status.WorkspaceID = aws.String("ws-2")
+	kc := newTestKubeClient(deleting, active)
+
+	rgn := &svcapitypes.RuleGroupsNamespace{}
+	rgn.Namespace, rgn.Name = "apps", "rules"
+	rgn.Spec.WorkspaceID = aws.String("ws-1")
+	requests := dependentRequests(kc)(rgn)
+	if len(requests) != 1 || requests[0].Namespace != "monitoring" || requests[0].Name != "main" {
+		t.Errorf("dependentRequests() = %v, want monitoring/main", requests)
+	}
+
+	rgn.Spec.WorkspaceID = aws.String("ws-2")
+	if requests := dependentRequests(kc)(rgn); len(requests) != 0 {
+		t.Errorf("dependentRequests() = %v for a workspace that is not being deleted", requests)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+	ampfaults "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/faults"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/faults/faultstest"
+)
+
+func Test_faults(t *testing.T) {
+	faultstest.Run(t, faultstest.Kind{
+		NewAPI: func() *ampfake.API {
+			return ampfake.New(ampfake.TestAccountID, ampfake.TestRegion)
+		},
+		NewResourceManager: func(api svcsdkapi.PrometheusServiceAPI) acktypes.AWSResourceManager {
+			return newTestResourceManager(api)
+		},
+		Descriptor: &resourceDescriptor{},
+		NewResource: func(testing.TB, *ampfake.API) acktypes.AWSResource {
+			ko := &svcapitypes.Workspace{}
+			ko.Spec.Alias = aws.String("alias")
+			ko.Spec.Tags = map[string]*string{"team": aws.String("a")}
+			return &resource{ko}
+		},
+		Update: func(desired acktypes.AWSResource) {
+			ko := desired.(*resource).ko
+			ko.Spec.Alias = aws.String("new-alias")
+			ko.Spec.Tags = map[string]*string{"team": aws.String("b")}
+		},
+		StatusCode: func(res acktypes.AWSResource) *string {
+			return workspaceStatusCode(res.(*resource))
+		},
+	}, []faultstest.Case{
+		{
+			Name:      "creation throttled",
+			Operation: "Create",
+			Faults: []ampfaults.Fault{
+				{Operation: "CreateWorkspace", Probability: 1, ErrorCode: svcsdk.ErrCodeThrottlingException},
+			},
+			WantErrCode:   svcsdk.ErrCodeThrottlingException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "creation over quota",
+			Operation: "Create",
+			Faults: []ampfaults.Fault{
+				{Operation: "CreateWorkspace", Probability: 1, ErrorCode: svcsdk.ErrCodeServiceQuotaExceededException},
+			},
+			WantErrCode:   svcsdk.ErrCodeServiceQuotaExceededException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "creation timed out",
+			Operation: "Create",
+			Faults: []ampfaults.Fault{
+				{Operation: "CreateWorkspace", Probability: 1, Latency: time.Hour},
+			},
+			Timeout:       10 * time.Millisecond,
+			WantErrCode:   request.CanceledErrorCode,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "creation rejected",
+			Operation: "Create",
+			Faults: []ampfaults.Fault{
+				{Operation: "CreateWorkspace", Probability: 1, ErrorCode: svcsdk.ErrCodeValidationException},
+			},
+			WantErrCode:   svcsdk.ErrCodeValidationException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "tagged but alias update failing",
+			Operation: "Update",
+			Faults: []ampfaults.Fault{
+				{Operation: "UpdateWorkspaceAlias", Probability: 1, ErrorCode: svcsdk.ErrCodeInternalServerException},
+			},
+			WantErrCode:   svcsdk.ErrCodeInternalServerException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "tagging denied",
+			Operation: "Update",
+			Faults: []ampfaults.Fault{
+				{Operation: "TagResource", Probability: 1, ErrorCode: svcsdk.ErrCodeAccessDeniedException},
+			},
+			WantErrCode:   svcsdk.ErrCodeAccessDeniedException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+		{
+			Name:      "deletion conflicting",
+			Operation: "Delete",
+			Faults: []ampfaults.Fault{
+				{Operation: "DeleteWorkspace", Probability: 1, ErrorCode: svcsdk.ErrCodeConflictException},
+			},
+			WantErrCode:   svcsdk.ErrCodeConflictException,
+			WantCondition: ackv1alpha1.ConditionTypeRecoverable,
+		},
+	})
+}
@@ -16,34 +16,188 @@ package workspace
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
 	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws"
 	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampstatus "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/status"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspacestatus"
 )
 
+// workspaceStatusCode returns the status code of the supplied workspace, or
+// nil if it is unknown
+func workspaceStatusCode(r *resource) *string {
+	if r.ko.Status.Status == nil {
+		return nil
+	}
+	return r.ko.Status.Status.StatusCode
+}
+
 // workspaceCreating returns true if the supplied workspace is in the process
 // of being created
 func workspaceCreating(r *resource) bool {
-	if r.ko.Status.Status == nil {
-		return false
+	return workspaceHasStatus(r, svcapitypes.WorkspaceStatusCode_CREATING)
+}
+
+// workspaceCreationFailed returns true if the supplied workspace failed to be
+// created
+func workspaceCreationFailed(r *resource) bool {
+	return ampstatus.Workspace.Terminal(workspaceStatusCode(r))
+}
+
+// workspaceHasStatus returns true if the supplied workspace has the given
+// status code
+func workspaceHasStatus(r *resource, code svcapitypes.WorkspaceStatusCode) bool {
+	return ampstatus.Workspace.Is(workspaceStatusCode(r), ampstatus.Code(code))
+}
+
+// setWorkspaceOperationStartTime records in the supplied workspace the time
+// at which it entered its current CREATING, UPDATING or DELETING status,
+// given the resource it was observed from.
+func setWorkspaceOperationStartTime(r *resource, ko *svcapitypes.Workspace) {
+	ko.Status.OperationStartTime = ampstatus.Workspace.OperationStartTime(
+		workspaceStatusCode(r), r.ko.Status.OperationStartTime,
+		workspaceStatusCode(&resource{ko}),
+	)
+}
+
+// observeWorkspaceTransition logs the supplied workspace moving to a status
+// that its state machine does not expect, given the resource it was observed
+// from.
+func observeWorkspaceTransition(ctx context.Context, r *resource, ko *svcapitypes.Workspace) {
+	ampstatus.Workspace.ObserveTransition(ctx, workspaceStatusCode(r), workspaceStatusCode(&resource{ko}))
+}
+
+// ensureWorkspaceNotStalled returns an error requeueing the supplied
+// workspace with a Stalled condition if it has been CREATING, UPDATING or
+// DELETING for longer than the configured stalled timeout of workspaces.
+func (rm *resourceManager) ensureWorkspaceNotStalled(ko *svcapitypes.Workspace) error {
+	return ampstatus.Workspace.EnsureNotStalled(
+		&resource{ko}, workspaceStatusCode(&resource{ko}), ko.Status.OperationStartTime,
+		svcresource.StalledTimeouts(rm.rr).For(ampstatus.Workspace),
+	)
+}
+
+// newWorkspaceCreationFailedError returns a terminal error describing why the
+// supplied workspace could not be created
+func newWorkspaceCreationFailedError(r *resource) error {
+	// The DescribeWorkspace API only exposes a status code for workspaces, so
+	// the status code is the only reason the service gives us.
+	return ackerr.NewTerminalError(fmt.Errorf(
+		"workspace is in '%s' status", *r.ko.Status.Status.StatusCode,
+	))
+}
+
+const (
+	// ConditionTypeDeletionProtected is set to True on workspaces whose
+	// deletion is refused because their deletion protection is enabled.
+	ConditionTypeDeletionProtected ackv1alpha1.ConditionType = "DeletionProtected"
+)
+
+var (
+	// deletionProtectedRequeueAfter is the delay before the deletion of a
+	// protected workspace is attempted again. Disabling the protection
+	// changes the generation of the resource, which triggers the deletion
+	// right away.
+	deletionProtectedRequeueAfter = 5 * time.Minute
+)
+
+// ensureNotDeletionProtected returns a copy of the supplied workspace with a
+// DeletionProtected condition and an error requeueing it if its deletion
+// protection is enabled. The finalizer of the resource is kept until then.
+func ensureNotDeletionProtected(r *resource) (*resource, error) {
+	if r.ko.Spec.DeletionProtection == nil || !*r.ko.Spec.DeletionProtection {
+		return nil, nil
 	}
-	ws := *r.ko.Status.Status.StatusCode
-	return ws == string(svcapitypes.WorkspaceStatusCode_CREATING)
+	msg := "deletion protection is enabled, set spec.deletionProtection to " +
+		"false to delete the workspace"
+	ko := r.ko.DeepCopy()
+	setDeletionHeldBack(&resource{ko}, ConditionTypeDeletionProtected, msg)
+	return &resource{ko}, ackrequeue.NeededAfter(errors.New(msg), deletionProtectedRequeueAfter)
 }
 
-// workspaceCreating returns true if the supplied workspace is in an active state
-func workspaceActive(r *resource) bool {
-	if r.ko.Status.Status == nil {
-		return false
+// setDeletionHeldBack sets the condition of the supplied type to True with
+// the supplied message, explaining why the deletion of the resource is held
+// back, and marks the resource as not synced.
+func setDeletionHeldBack(
+	res acktypes.ConditionManager,
+	conditionType ackv1alpha1.ConditionType,
+	msg string,
+) {
+	reason := string(conditionType)
+	condition := ackcondition.FirstOfType(res, conditionType)
+	if condition == nil {
+		condition = &ackv1alpha1.Condition{Type: conditionType}
+		res.ReplaceConditions(append(res.Conditions(), condition))
 	}
-	ws := *r.ko.Status.Status.StatusCode
-	return ws == string(svcapitypes.WorkspaceStatusCode_ACTIVE)
+	now := metav1.Now()
+	condition.Status = corev1.ConditionTrue
+	condition.LastTransitionTime = &now
+	condition.Message = &msg
+	condition.Reason = &reason
+	ackcondition.SetSynced(res, corev1.ConditionFalse, nil, &reason)
+}
+
+// observeWorkspaceStatus records the latest observed status code of the
+// supplied workspace in the status cache shared with the rule groups
+// namespace and alert manager definition resource managers.
+func (rm *resourceManager) observeWorkspaceStatus(ko *svcapitypes.Workspace) {
+	if ko.Status.WorkspaceID == nil {
+		return
+	}
+	statusCode := ""
+	if ko.Status.Status != nil && ko.Status.Status.StatusCode != nil {
+		statusCode = *ko.Status.Status.StatusCode
+	}
+	workspacestatus.For(rm.awsAccountID, rm.awsRegion).Observe(*ko.Status.WorkspaceID, statusCode)
+}
+
+// invalidateWorkspaceStatus removes the status code of the supplied workspace
+// from the status cache shared with the rule groups namespace and alert
+// manager definition resource managers.
+func (rm *resourceManager) invalidateWorkspaceStatus(ko *svcapitypes.Workspace) {
+	if ko.Status.WorkspaceID == nil {
+		return
+	}
+	workspacestatus.For(rm.awsAccountID, rm.awsRegion).Invalidate(*ko.Status.WorkspaceID)
+}
+
+const (
+	// remoteWritePath is the path, relative to the workspace Prometheus
+	// endpoint, that accepts Prometheus remote write requests.
+	remoteWritePath = "api/v1/remote_write"
+	// queryPath is the path, relative to the workspace Prometheus endpoint,
+	// that serves PromQL queries.
+	queryPath = "api/v1/query"
+)
+
+// setWorkspaceEndpoints populates the remote write and query URLs in the
+// supplied workspace's Status from its Prometheus endpoint
+func setWorkspaceEndpoints(ko *svcapitypes.Workspace) {
+	if ko.Status.PrometheusEndpoint == nil || *ko.Status.PrometheusEndpoint == "" {
+		ko.Status.RemoteWriteURL = nil
+		ko.Status.QueryURL = nil
+		return
+	}
+	base := strings.TrimSuffix(*ko.Status.PrometheusEndpoint, "/")
+	remoteWriteURL := base + "/" + remoteWritePath
+	queryURL := base + "/" + queryPath
+	ko.Status.RemoteWriteURL = &remoteWriteURL
+	ko.Status.QueryURL = &queryURL
 }
 
 // customUpdateWorkspace patches each of the resource properties in the backend AWS
@@ -66,9 +220,15 @@ func (rm *resourceManager) customUpdateWorkspace(
 
 	rm.setStatusDefaults(ko)
 
-	// Check if the state is active before updating
-	if !workspaceActive(latest) {
-		msg := "Cannot update workspace as it is not active, current status=" + string(*latest.ko.Status.Status.StatusCode)
+	// Check if the state is active before updating, waiting for the
+	// operation in progress, if any, to complete.
+	if err = ampstatus.Workspace.RequeueWhileBusy(workspaceStatusCode(latest)); err != nil {
+		msg := err.Error()
+		ackcondition.SetSynced(desired, corev1.ConditionFalse, &msg, nil)
+		return desired, err
+	}
+	if !ampstatus.Workspace.Updatable(workspaceStatusCode(latest)) {
+		msg := "Cannot update workspace as it is not active, current status=" + aws.StringValue(workspaceStatusCode(latest))
 		ackcondition.SetSynced(desired, corev1.ConditionFalse, &msg, nil)
 		return desired, ackrequeue.NeededAfter(
 			errors.New(msg),
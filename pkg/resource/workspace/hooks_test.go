@@ -14,10 +14,16 @@
 package workspace
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
 )
 
 // Test function obtained from:
@@ -91,3 +97,115 @@ func Test_compareMaps(t *testing.T) {
 		})
 	}
 }
+
+func Test_setWorkspaceEndpoints(t *testing.T) {
+	tests := []struct {
+		name               string
+		endpoint           *string
+		wantRemoteWriteURL *string
+		wantQueryURL       *string
+	}{
+		{
+			name:               "nil endpoint",
+			endpoint:           nil,
+			wantRemoteWriteURL: nil,
+			wantQueryURL:       nil,
+		},
+		{
+			name:               "endpoint with trailing slash",
+			endpoint:           aws.String("https://aps-workspaces.us-west-2.amazonaws.com/workspaces/ws-123/"),
+			wantRemoteWriteURL: aws.String("https://aps-workspaces.us-west-2.amazonaws.com/workspaces/ws-123/api/v1/remote_write"),
+			wantQueryURL:       aws.String("https://aps-workspaces.us-west-2.amazonaws.com/workspaces/ws-123/api/v1/query"),
+		},
+		{
+			name:               "endpoint without trailing slash",
+			endpoint:           aws.String("https://aps-workspaces.us-west-2.amazonaws.com/workspaces/ws-123"),
+			wantRemoteWriteURL: aws.String("https://aps-workspaces.us-west-2.amazonaws.com/workspaces/ws-123/api/v1/remote_write"),
+			wantQueryURL:       aws.String("https://aps-workspaces.us-west-2.amazonaws.com/workspaces/ws-123/api/v1/query"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.Workspace{}
+			ko.Status.PrometheusEndpoint = tt.endpoint
+			setWorkspaceEndpoints(ko)
+			if !reflect.DeepEqual(ko.Status.RemoteWriteURL, tt.wantRemoteWriteURL) {
+				t.Errorf("setWorkspaceEndpoints() RemoteWriteURL = %v, want %v", aws.StringValue(ko.Status.RemoteWriteURL), aws.StringValue(tt.wantRemoteWriteURL))
+			}
+			if !reflect.DeepEqual(ko.Status.QueryURL, tt.wantQueryURL) {
+				t.Errorf("setWorkspaceEndpoints() QueryURL = %v, want %v", aws.StringValue(ko.Status.QueryURL), aws.StringValue(tt.wantQueryURL))
+			}
+		})
+	}
+}
+
+func Test_workspaceHasStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *svcapitypes.WorkspaceStatus_SDK
+		code   svcapitypes.WorkspaceStatusCode
+		want   bool
+	}{
+		{
+			name:   "nil status",
+			status: nil,
+			code:   svcapitypes.WorkspaceStatusCode_ACTIVE,
+			want:   false,
+		},
+		{
+			name:   "nil status code",
+			status: &svcapitypes.WorkspaceStatus_SDK{},
+			code:   svcapitypes.WorkspaceStatusCode_ACTIVE,
+			want:   false,
+		},
+		{
+			name:   "matching status code",
+			status: &svcapitypes.WorkspaceStatus_SDK{StatusCode: aws.String("CREATION_FAILED")},
+			code:   svcapitypes.WorkspaceStatusCode_CREATION_FAILED,
+			want:   true,
+		},
+		{
+			name:   "different status code",
+			status: &svcapitypes.WorkspaceStatus_SDK{StatusCode: aws.String("CREATING")},
+			code:   svcapitypes.WorkspaceStatusCode_ACTIVE,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &resource{ko: &svcapitypes.Workspace{}}
+			r.ko.Status.Status = tt.status
+			if got := workspaceHasStatus(r, tt.code); got != tt.want {
+				t.Errorf("workspaceHasStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ensureNotDeletionProtected(t *testing.T) {
+	for _, protection := range []*bool{nil, aws.Bool(false)} {
+		r := &resource{ko: &svcapitypes.Workspace{}}
+		r.ko.Spec.DeletionProtection = protection
+		if protected, err := ensureNotDeletionProtected(r); protected != nil || err != nil {
+			t.Errorf("ensureNotDeletionProtected() = %v, %v for deletion protection %v", protected, err, protection)
+		}
+	}
+
+	r := &resource{ko: &svcapitypes.Workspace{}}
+	r.ko.Spec.DeletionProtection = aws.Bool(true)
+	protected, err := ensureNotDeletionProtected(r)
+	var requeue *ackrequeue.RequeueNeededAfter
+	if !errors.As(err, &requeue) {
+		t.Fatalf("ensureNotDeletionProtected() error = %v, want a requeue", err)
+	}
+	condition := ackcondition.FirstOfType(protected, ConditionTypeDeletionProtected)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		t.Errorf("ensureNotDeletionProtected() condition = %v", condition)
+	}
+	if synced := ackcondition.Synced(protected); synced == nil || synced.Status != corev1.ConditionFalse {
+		t.Errorf("ensureNotDeletionProtected() synced condition = %v", synced)
+	}
+	if len(r.ko.Status.Conditions) != 0 {
+		t.Errorf("ensureNotDeletionProtected() modified the supplied resource")
+	}
+}
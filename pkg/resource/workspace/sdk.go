@@ -75,6 +75,11 @@ func (rm *resourceManager) sdkFind(
 
 	var resp *svcsdk.DescribeWorkspaceOutput
 	resp, err = rm.sdkapi.DescribeWorkspaceWithContext(ctx, input)
+	// A workspace that no longer exists must not stay cached as ACTIVE for
+	// the resources created in it.
+	if awsErr, ok := ackerr.AWSError(err); ok && awsErr.Code() == "ResourceNotFoundException" {
+		rm.invalidateWorkspaceStatus(r.ko)
+	}
 	rm.metrics.RecordAPICall("READ_ONE", "DescribeWorkspace", err)
 	if err != nil {
 		if awsErr, ok := ackerr.AWSError(err); ok && awsErr.Code() == "ResourceNotFoundException" {
@@ -99,6 +104,11 @@ func (rm *resourceManager) sdkFind(
 		arn := ackv1alpha1.AWSResourceName(*resp.Workspace.Arn)
 		ko.Status.ACKResourceMetadata.ARN = &arn
 	}
+	if resp.Workspace.PrometheusEndpoint != nil {
+		ko.Status.PrometheusEndpoint = resp.Workspace.PrometheusEndpoint
+	} else {
+		ko.Status.PrometheusEndpoint = nil
+	}
 	if resp.Workspace.Status != nil {
 		f4 := &svcapitypes.WorkspaceStatus_SDK{}
 		if resp.Workspace.Status.StatusCode != nil {
@@ -126,6 +136,28 @@ func (rm *resourceManager) sdkFind(
 	}
 
 	rm.setStatusDefaults(ko)
+	// Keep the workspace status cached for the resources created in this
+	// workspace in sync with the latest observed status.
+	rm.observeWorkspaceStatus(ko)
+	// The remote write and query URLs are derived from the Prometheus
+	// endpoint so that they are always in sync with the latest observed
+	// value.
+	setWorkspaceEndpoints(ko)
+	// Status changes that the state machine does not expect are logged.
+	observeWorkspaceTransition(ctx, r, ko)
+	// A workspace that AMP leaves CREATING, UPDATING or DELETING for too long
+	// is reported as stalled and reconciled less often.
+	setWorkspaceOperationStartTime(r, ko)
+	if err := rm.ensureWorkspaceNotStalled(ko); err != nil {
+		return &resource{ko}, err
+	}
+	// A workspace that failed to be created will never become ACTIVE without
+	// the CR being deleted and recreated, so we report it as a terminal
+	// error. We skip this while the CR is being deleted so that the deletion
+	// of the failed workspace can still proceed.
+	if workspaceCreationFailed(&resource{ko}) && !r.IsBeingDeleted() {
+		return &resource{ko}, newWorkspaceCreationFailedError(&resource{ko})
+	}
 	return &resource{ko}, nil
 }
 
@@ -215,6 +247,12 @@ func (rm *resourceManager) sdkCreate(
 	}
 
 	rm.setStatusDefaults(ko)
+	// Keep the workspace status cached for the resources created in this
+	// workspace in sync with the latest observed status.
+	rm.observeWorkspaceStatus(ko)
+	// Record when the workspace started being created, to report it as
+	// stalled if it stays CREATING for too long.
+	setWorkspaceOperationStartTime(desired, ko)
 	// We expect the workspace to be in 'creating' status since we just
 	// issued the call to create it, but I suppose it doesn't hurt to check
 	// here.
@@ -273,6 +311,17 @@ func (rm *resourceManager) sdkDelete(
 	defer func() {
 		exit(err)
 	}()
+	// Deleting the workspace also deletes the metrics ingested into it, which
+	// cannot be recovered.
+	if protected, err := ensureNotDeletionProtected(r); err != nil {
+		return protected, err
+	}
+	// The resources created in the workspace would be left orphaned by its
+	// deletion.
+	if blocked, err := rm.ensureNoDependents(ctx, r); err != nil {
+		return blocked, err
+	}
+
 	input, err := rm.newDeleteRequestPayload(r)
 	if err != nil {
 		return nil, err
@@ -281,6 +330,8 @@ func (rm *resourceManager) sdkDelete(
 	_ = resp
 	resp, err = rm.sdkapi.DeleteWorkspaceWithContext(ctx, input)
 	rm.metrics.RecordAPICall("DELETE", "DeleteWorkspace", err)
+	// The workspace is no longer ACTIVE once its deletion is requested.
+	rm.invalidateWorkspaceStatus(r.ko)
 	return nil, err
 }
 
@@ -0,0 +1,387 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspace
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	svcsdkapi "github.com/aws/aws-sdk-go/service/prometheusservice/prometheusserviceiface"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+	svcresource "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/resource"
+	"github.com/aws-controllers-k8s/prometheusservice-controller/pkg/workspacestatus"
+)
+
+// newTestResourceManager returns a resource manager calling the supplied fake
+// AMP API, and reading the dependents of workspaces with a fake client.
+func newTestResourceManager(api svcsdkapi.PrometheusServiceAPI) *resourceManager {
+	rr := svcresource.ReconcilerWithOptions(nil, svcresource.ManagerOptions{KubeClient: newTestKubeClient()})
+	return &resourceManager{rr: rr, sdkapi: api, metrics: ampfake.TestMetrics, awsAccountID: ampfake.TestAccountID, awsRegion: ampfake.TestRegion}
+}
+
+func Test_sdkFind(t *testing.T) {
+	tests := []struct {
+		name string
+		// transitions is the number of descriptions before the creation of
+		// the workspace completes.
+		transitions int
+		// validationErr fails the creation of the workspace.
+		validationErr error
+		// workspaceID replaces the ID of the created workspace.
+		workspaceID *string
+		deleting    bool
+		wantStatus  string
+		wantErr     func(error) bool
+	}{
+		{
+			name:        "not created",
+			workspaceID: aws.String(""),
+			wantErr:     func(err error) bool { return err == ackerr.NotFound },
+		},
+		{
+			name:        "not found",
+			workspaceID: aws.String("ws-missing"),
+			wantErr:     func(err error) bool { return err == ackerr.NotFound },
+		},
+		{
+			name:        "creating",
+			transitions: 1,
+			wantStatus:  svcsdk.WorkspaceStatusCodeCreating,
+		},
+		{
+			name:       "active",
+			wantStatus: svcsdk.WorkspaceStatusCodeActive,
+		},
+		{
+			name:          "creation failed",
+			validationErr: errors.New("quota exceeded"),
+			wantStatus:    svcsdk.WorkspaceStatusCodeCreationFailed,
+			wantErr: func(err error) bool {
+				return errors.As(err, new(*ackerr.TerminalError)) &&
+					err.Error() == "workspace is in 'CREATION_FAILED' status"
+			},
+		},
+		{
+			name:          "creation failed while deleting",
+			validationErr: errors.New("quota exceeded"),
+			deleting:      true,
+			wantStatus:    svcsdk.WorkspaceStatusCodeCreationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := ampfake.New(ampfake.TestAccountID, ampfake.TestRegion)
+			api.Transitions = tt.transitions
+			api.ValidateWorkspace = func(*string) error { return tt.validationErr }
+			rm := newTestResourceManager(api)
+
+			ko := &svcapitypes.Workspace{}
+			ko.Spec.Alias = aws.String("alias")
+			ko.Spec.Tags = map[string]*string{"team": aws.String("a")}
+			created, err := rm.sdkCreate(context.TODO(), &resource{ko})
+			if err != nil {
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if tt.workspaceID != nil {
+				created.ko.Status.WorkspaceID = tt.workspaceID
+				if *tt.workspaceID == "" {
+					created.ko.Status.WorkspaceID = nil
+				}
+			}
+			if tt.deleting {
+				now := metav1.Now()
+				created.ko.DeletionTimestamp = &now
+			}
+
+			latest, err := rm.sdkFind(context.TODO(), created)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("sdkFind() unexpected error = %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("sdkFind() error = %v", err)
+			}
+			if tt.wantStatus == "" {
+				return
+			}
+			if got := *latest.ko.Status.Status.StatusCode; got != tt.wantStatus {
+				t.Errorf("sdkFind() status = %s, want %s", got, tt.wantStatus)
+			}
+			if *latest.ko.Spec.Alias != "alias" || *latest.ko.Spec.Tags["team"] != "a" {
+				t.Errorf("sdkFind() spec = %+v, want the created alias and tags", latest.ko.Spec)
+			}
+			wantURL := "https://aps-workspaces.us-west-2.amazonaws.com/workspaces/" +
+				*latest.ko.Status.WorkspaceID + "/api/v1/remote_write"
+			if latest.ko.Status.RemoteWriteURL == nil || *latest.ko.Status.RemoteWriteURL != wantURL {
+				t.Errorf("sdkFind() remote write URL = %v, want %s", latest.ko.Status.RemoteWriteURL, wantURL)
+			}
+			if busy := tt.wantStatus == svcsdk.WorkspaceStatusCodeCreating; busy != (latest.ko.Status.OperationStartTime != nil) {
+				t.Errorf("sdkFind() operation start time = %v", latest.ko.Status.OperationStartTime)
+			}
+		})
+	}
+}
+
+func Test_sdkFind_notFoundInvalidatesStatus(t *testing.T) {
+	rm := newTestResourceManager(ampfake.New(ampfake.TestAccountID, ampfake.TestRegion))
+	cache := workspacestatus.For(rm.awsAccountID, rm.awsRegion)
+	cache.Observe("ws-deleted", svcsdk.WorkspaceStatusCodeActive)
+
+	ko := &svcapitypes.Workspace{}
+	ko.Status.WorkspaceID = aws.String("ws-deleted")
+	if _, err := rm.sdkFind(context.TODO(), &resource{ko}); err != ackerr.NotFound {
+		t.Fatalf("sdkFind() error = %v, want NotFound", err)
+	}
+	if statusCode, ok := cache.Get("ws-deleted"); ok {
+		t.Errorf("cached status = %s, want the status of the deleted workspace invalidated", statusCode)
+	}
+}
+
+func Test_sdkCreate(t *testing.T) {
+	tests := []struct {
+		name          string
+		alias         *string
+		wantErrCode   string
+		wantNotSynced bool
+	}{
+		{name: "without alias", wantNotSynced: true},
+		{name: "with alias", alias: aws.String("alias"), wantNotSynced: true},
+		{name: "invalid alias", alias: aws.String(""), wantErrCode: svcsdk.ErrCodeValidationException},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := ampfake.New(ampfake.TestAccountID, ampfake.TestRegion)
+			rm := newTestResourceManager(api)
+			ko := &svcapitypes.Workspace{}
+			ko.Spec.Alias = tt.alias
+			ko.Spec.Tags = map[string]*string{"team": aws.String("a")}
+
+			created, err := rm.sdkCreate(context.TODO(), &resource{ko})
+			if tt.wantErrCode != "" {
+				if code := ampfake.ErrorCode(err); code != tt.wantErrCode {
+					t.Errorf("sdkCreate() error = %v, want a %s", err, tt.wantErrCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if created.ko.Status.WorkspaceID == nil || created.ko.Status.ACKResourceMetadata.ARN == nil {
+				t.Errorf("sdkCreate() status = %+v, want the workspace ID and ARN", created.ko.Status)
+			}
+			if *created.ko.Status.Status.StatusCode != svcsdk.WorkspaceStatusCodeCreating {
+				t.Errorf("sdkCreate() status = %s, want CREATING", *created.ko.Status.Status.StatusCode)
+			}
+			if created.ko.Status.OperationStartTime == nil {
+				t.Errorf("sdkCreate() did not record the operation start time")
+			}
+			synced := ackcondition.Synced(created)
+			if tt.wantNotSynced != (synced != nil && synced.Status == corev1.ConditionFalse) {
+				t.Errorf("sdkCreate() synced condition = %v", synced)
+			}
+		})
+	}
+}
+
+func Test_sdkUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		// transitions is the number of descriptions before an operation
+		// completes. The update of the workspace is attempted while its
+		// creation is still ongoing if it is not zero.
+		transitions   int
+		validationErr error
+		alias         *string
+		tags          map[string]*string
+		wantCalls     []string
+		wantRequeue   bool
+		wantAlias     *string
+		wantTags      map[string]*string
+	}{
+		{
+			name:      "alias",
+			alias:     aws.String("new-alias"),
+			tags:      map[string]*string{"team": aws.String("a"), "env": aws.String("prod")},
+			wantCalls: []string{"UpdateWorkspaceAlias"},
+			wantAlias: aws.String("new-alias"),
+			wantTags:  map[string]*string{"team": aws.String("a"), "env": aws.String("prod")},
+		},
+		{
+			name:      "tags",
+			alias:     aws.String("alias"),
+			tags:      map[string]*string{"team": aws.String("b"), "owner": aws.String("me")},
+			wantCalls: []string{"UntagResource", "TagResource"},
+			wantAlias: aws.String("alias"),
+			wantTags:  map[string]*string{"team": aws.String("b"), "owner": aws.String("me")},
+		},
+		{
+			name:        "creating",
+			transitions: 2,
+			alias:       aws.String("new-alias"),
+			wantRequeue: true,
+			wantAlias:   aws.String("alias"),
+			wantTags:    map[string]*string{"team": aws.String("a"), "env": aws.String("prod")},
+		},
+		{
+			name:          "creation failed",
+			validationErr: errors.New("quota exceeded"),
+			alias:         aws.String("new-alias"),
+			wantRequeue:   true,
+			wantAlias:     aws.String("alias"),
+			wantTags:      map[string]*string{"team": aws.String("a"), "env": aws.String("prod")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := ampfake.New(ampfake.TestAccountID, ampfake.TestRegion)
+			api.ValidateWorkspace = func(*string) error { return tt.validationErr }
+			rm := newTestResourceManager(api)
+			ko := &svcapitypes.Workspace{}
+			ko.Spec.Alias = aws.String("alias")
+			ko.Spec.Tags = map[string]*string{"team": aws.String("a"), "env": aws.String("prod")}
+			created, err := rm.sdkCreate(context.TODO(), &resource{ko})
+			if err != nil {
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if tt.transitions == 0 {
+				api.Settle()
+			}
+			api.Transitions = tt.transitions
+			latest, err := rm.sdkFind(context.TODO(), created)
+			if err != nil && tt.validationErr == nil {
+				t.Fatalf("sdkFind() error = %v", err)
+			}
+
+			desired := &resource{latest.ko.DeepCopy()}
+			desired.ko.Spec.Alias = tt.alias
+			desired.ko.Spec.Tags = tt.tags
+			calls := len(api.Calls())
+			_, err = rm.sdkUpdate(context.TODO(), desired, latest, newResourceDelta(desired, latest))
+			var requeue *ackrequeue.RequeueNeededAfter
+			if tt.wantRequeue != errors.As(err, &requeue) {
+				t.Fatalf("sdkUpdate() error = %v, want requeue %v", err, tt.wantRequeue)
+			} else if !tt.wantRequeue && err != nil {
+				t.Fatalf("sdkUpdate() error = %v", err)
+			}
+			if got := api.Calls()[calls:]; strings.Join(got, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("sdkUpdate() calls = %v, want %v", got, tt.wantCalls)
+			}
+
+			api.Settle()
+			resp, err := api.DescribeWorkspaceWithContext(context.TODO(), &svcsdk.DescribeWorkspaceInput{
+				WorkspaceId: latest.ko.Status.WorkspaceID,
+			})
+			if err != nil {
+				t.Fatalf("DescribeWorkspace() error = %v", err)
+			}
+			if !reflect.DeepEqual(resp.Workspace.Alias, tt.wantAlias) {
+				t.Errorf("workspace alias = %v, want %v", aws.StringValue(resp.Workspace.Alias), aws.StringValue(tt.wantAlias))
+			}
+			if !reflect.DeepEqual(resp.Workspace.Tags, tt.wantTags) {
+				t.Errorf("workspace tags = %v, want %v", aws.StringValueMap(resp.Workspace.Tags), aws.StringValueMap(tt.wantTags))
+			}
+		})
+	}
+}
+
+func Test_sdkDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		// transitions is the number of descriptions before an operation
+		// completes. The workspace is deleted while its creation is still
+		// ongoing if it is not zero.
+		transitions        int
+		deletionProtection *bool
+		wantCalls          []string
+		wantErr            func(error) bool
+		wantDeleted        bool
+	}{
+		{
+			name:        "active",
+			wantCalls:   []string{"DeleteWorkspace"},
+			wantDeleted: true,
+		},
+		{
+			name:               "deletion protection disabled",
+			deletionProtection: aws.Bool(false),
+			wantCalls:          []string{"DeleteWorkspace"},
+			wantDeleted:        true,
+		},
+		{
+			name:               "deletion protection enabled",
+			deletionProtection: aws.Bool(true),
+			wantErr: func(err error) bool {
+				return errors.As(err, new(*ackrequeue.RequeueNeededAfter))
+			},
+		},
+		{
+			name:        "creating",
+			transitions: 2,
+			wantCalls:   []string{"DeleteWorkspace"},
+			wantErr: func(err error) bool {
+				return ampfake.ErrorCode(err) == svcsdk.ErrCodeConflictException
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := ampfake.New(ampfake.TestAccountID, ampfake.TestRegion)
+			rm := newTestResourceManager(api)
+			created, err := rm.sdkCreate(context.TODO(), &resource{&svcapitypes.Workspace{}})
+			if err != nil {
+				t.Fatalf("sdkCreate() error = %v", err)
+			}
+			if tt.transitions == 0 {
+				api.Settle()
+			}
+			api.Transitions = tt.transitions
+			latest, err := rm.sdkFind(context.TODO(), created)
+			if err != nil {
+				t.Fatalf("sdkFind() error = %v", err)
+			}
+			latest.ko.Spec.DeletionProtection = tt.deletionProtection
+
+			calls := len(api.Calls())
+			_, err = rm.sdkDelete(context.TODO(), latest)
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("sdkDelete() unexpected error = %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("sdkDelete() error = %v", err)
+			}
+			if got := api.Calls()[calls:]; strings.Join(got, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("sdkDelete() calls = %v, want %v", got, tt.wantCalls)
+			}
+
+			api.Settle()
+			_, err = rm.sdkFind(context.TODO(), latest)
+			if deleted := err == ackerr.NotFound; deleted != tt.wantDeleted {
+				t.Errorf("sdkFind() error = %v after the deletion, want deleted %v", err, tt.wantDeleted)
+			}
+		})
+	}
+}
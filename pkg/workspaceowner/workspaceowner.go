@@ -0,0 +1,318 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package workspaceowner adds owner references to the Workspace custom
+// resources that rule groups namespaces and alert manager definitions are
+// created in, so that Kubernetes garbage collection and tools such as
+// `kubectl tree` know about the hierarchy.
+package workspaceowner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+const (
+	// serviceAlias is the alias of the service prefixing the deletion policy
+	// annotation of namespaces.
+	serviceAlias = "prometheusservice"
+)
+
+var (
+	// DefaultDeletionPolicy is the deletion policy of the workspaces with
+	// no deletion policy annotation in namespaces with none either. It is
+	// set from the --deletion-policy flag before the controllers are
+	// started.
+	DefaultDeletionPolicy = ackv1alpha1.DeletionPolicyDelete
+)
+
+// Kind describes a kind of custom resources created in a workspace.
+type Kind struct {
+	// Name is the name of the kind, such as "RuleGroupsNamespace".
+	Name string
+	// NewObject returns an empty custom resource of the kind.
+	NewObject func() client.Object
+	// NewList returns an empty list of custom resources of the kind.
+	NewList func() client.ObjectList
+	// Workspace returns the workspace ID and reference fields of the
+	// supplied custom resource of the kind.
+	Workspace func(client.Object) (*string, *svcapitypes.ResourceReferenceWrapper)
+}
+
+// DependsOn returns true if a resource in the supplied namespace with the
+// supplied workspace ID and reference fields is created in the supplied
+// workspace.
+func DependsOn(
+	ws *svcapitypes.Workspace,
+	namespace string,
+	workspaceID *string,
+	workspaceRef *svcapitypes.ResourceReferenceWrapper,
+) bool {
+	if workspaceRef != nil && workspaceRef.From != nil && workspaceRef.From.Name != nil {
+		if workspaceRef.From.Namespace != nil && *workspaceRef.From.Namespace != "" {
+			namespace = *workspaceRef.From.Namespace
+		}
+		return namespace == ws.Namespace && *workspaceRef.From.Name == ws.Name
+	}
+	return workspaceID != nil && ws.Status.WorkspaceID != nil &&
+		*workspaceID == *ws.Status.WorkspaceID
+}
+
+// IsOwnedBy returns true if the supplied object has an owner reference to the
+// supplied workspace.
+func IsOwnedBy(obj metav1.Object, ws *svcapitypes.Workspace) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if isWorkspaceReference(ref) && ref.UID == ws.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// isWorkspaceReference returns true if the supplied owner reference is to a
+// Workspace custom resource.
+func isWorkspaceReference(ref metav1.OwnerReference) bool {
+	return ref.APIVersion == svcapitypes.GroupVersion.String() && ref.Kind == "Workspace"
+}
+
+// reconciler keeps the owner reference of the custom resources of a kind to
+// the Workspace custom resource they are created in up to date.
+type reconciler struct {
+	kc        client.Client
+	apiReader client.Reader
+	log       logr.Logger
+	kind      Kind
+}
+
+// Reconcile implements `controller-runtime.Reconciler`
+func (r *reconciler) Reconcile(ctx context.Context, req ctrlrt.Request) (ctrlrt.Result, error) {
+	obj := r.kind.NewObject()
+	if err := r.kc.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrlrt.Result{}, client.IgnoreNotFound(err)
+	}
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return ctrlrt.Result{}, nil
+	}
+
+	var ws *svcapitypes.Workspace
+	if obj.GetAnnotations()[svcapitypes.AnnotationWorkspaceOwnerReference] != "false" {
+		var err error
+		if ws, err = r.workspace(ctx, obj); err != nil {
+			return ctrlrt.Result{}, err
+		}
+	}
+
+	ownerRefs := []metav1.OwnerReference{}
+	for _, ref := range obj.GetOwnerReferences() {
+		if !isWorkspaceReference(ref) {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	if ws != nil {
+		ownerRefs = append(ownerRefs, metav1.OwnerReference{
+			APIVersion: svcapitypes.GroupVersion.String(),
+			Kind:       "Workspace",
+			Name:       ws.Name,
+			UID:        ws.UID,
+		})
+	}
+	if reflect.DeepEqual(ownerRefs, obj.GetOwnerReferences()) ||
+		(len(ownerRefs) == 0 && len(obj.GetOwnerReferences()) == 0) {
+		return ctrlrt.Result{}, nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	obj.SetOwnerReferences(ownerRefs)
+	if err := r.kc.Patch(ctx, obj, patch); err != nil {
+		return ctrlrt.Result{}, client.IgnoreNotFound(err)
+	}
+	r.log.V(1).Info(
+		"updated workspace owner reference",
+		"namespace", req.Namespace, "name", req.Name,
+	)
+	return ctrlrt.Result{}, nil
+}
+
+// workspace returns the Workspace custom resource, in the namespace of the
+// supplied object, that the object is created in. Owner references cannot
+// cross namespaces, so nil is returned for workspaces in other namespaces.
+// Nil is also returned until the ID of the workspace is known, and for
+// workspaces with the retain deletion policy.
+func (r *reconciler) workspace(
+	ctx context.Context,
+	obj client.Object,
+) (*svcapitypes.Workspace, error) {
+	workspaceID, workspaceRef := r.kind.Workspace(obj)
+	list := &svcapitypes.WorkspaceList{}
+	if err := r.kc.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		ws := &list.Items[i]
+		if ws.Status.WorkspaceID == nil || !ws.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if !DependsOn(ws, obj.GetNamespace(), workspaceID, workspaceRef) {
+			continue
+		}
+		// Kubernetes garbage collection would delete the resources created in
+		// a retained workspace, and the AMP resources behind them, when the
+		// Workspace resource is deleted.
+		policy, err := r.deletionPolicy(ctx, ws)
+		if err != nil {
+			return nil, err
+		}
+		if policy == ackv1alpha1.DeletionPolicyRetain {
+			return nil, nil
+		}
+		return ws, nil
+	}
+	return nil, nil
+}
+
+// deletionPolicy returns the deletion policy of the supplied workspace the
+// way the ACK runtime resolves it: from the annotation of the workspace, then
+// from the annotation of its namespace, then from DefaultDeletionPolicy.
+func (r *reconciler) deletionPolicy(
+	ctx context.Context,
+	ws *svcapitypes.Workspace,
+) (ackv1alpha1.DeletionPolicy, error) {
+	if policy, ok := ws.Annotations[ackv1alpha1.AnnotationDeletionPolicy]; ok {
+		return ackv1alpha1.DeletionPolicy(policy), nil
+	}
+	ns := &corev1.Namespace{}
+	if err := r.apiReader.Get(ctx, types.NamespacedName{Name: ws.Namespace}, ns); client.IgnoreNotFound(err) != nil {
+		return "", err
+	}
+	if policy, ok := ns.Annotations[serviceAlias+"."+ackv1alpha1.AnnotationDeletionPolicy]; ok {
+		return ackv1alpha1.DeletionPolicy(policy), nil
+	}
+	return DefaultDeletionPolicy, nil
+}
+
+// workspaceRequests returns a function that maps a Workspace custom resource
+// to reconcile requests for the custom resources of the supplied kind, in its
+// namespace, that are created in it.
+func workspaceRequests(kc client.Reader, kind Kind) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		ws, ok := obj.(*svcapitypes.Workspace)
+		if !ok {
+			return nil
+		}
+		list := kind.NewList()
+		if err := kc.List(context.TODO(), list, client.InNamespace(ws.Namespace)); err != nil {
+			return nil
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, item := range items {
+			o, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			workspaceID, workspaceRef := kind.Workspace(o)
+			if DependsOn(ws, o.GetNamespace(), workspaceID, workspaceRef) || IsOwnedBy(o, ws) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: o.GetNamespace(),
+						Name:      o.GetName(),
+					},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// namespaceRequests returns a function that maps a namespace to reconcile
+// requests for the custom resources of the supplied kind in it, as the
+// deletion policy of the workspaces in the namespace may have changed.
+func namespaceRequests(kc client.Reader, kind Kind) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		list := kind.NewList()
+		if err := kc.List(context.TODO(), list, client.InNamespace(obj.GetName())); err != nil {
+			return nil
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, item := range items {
+			if o, ok := item.(client.Object); ok {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: o.GetNamespace(),
+						Name:      o.GetName(),
+					},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// Setup sets up a controller adding owner references to the custom resources
+// of the kind. It has the signature of the functions registered with
+// `resource.RegisterController`.
+func (k Kind) Setup(mgr ctrlrt.Manager) error {
+	name := strings.ToLower(k.Name) + "-workspace-owner"
+	c, err := controller.New(name, mgr, controller.Options{
+		Reconciler: &reconciler{
+			kc:        mgr.GetClient(),
+			apiReader: mgr.GetAPIReader(),
+			log:       ctrlrt.Log.WithName(name),
+			kind:      k,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(
+		&source.Kind{Type: k.NewObject()},
+		&handler.EnqueueRequestForObject{},
+	); err != nil {
+		return err
+	}
+	if err := c.Watch(
+		&source.Kind{Type: &svcapitypes.Workspace{}},
+		handler.EnqueueRequestsFromMapFunc(workspaceRequests(mgr.GetClient(), k)),
+	); err != nil {
+		return err
+	}
+	namespace := &metav1.PartialObjectMetadata{}
+	namespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	return c.Watch(
+		&source.Kind{Type: namespace},
+		handler.EnqueueRequestsFromMapFunc(namespaceRequests(mgr.GetClient(), k)),
+	)
+}
@@ -0,0 +1,349 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspaceowner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+var testKind = Kind{
+	Name: "RuleGroupsNamespace",
+	NewObject: func() client.Object {
+		return &svcapitypes.RuleGroupsNamespace{}
+	},
+	NewList: func() client.ObjectList {
+		return &svcapitypes.RuleGroupsNamespaceList{}
+	},
+	Workspace: func(obj client.Object) (*string, *svcapitypes.ResourceReferenceWrapper) {
+		ko := obj.(*svcapitypes.RuleGroupsNamespace)
+		return ko.Spec.WorkspaceID, ko.Spec.WorkspaceRef
+	},
+}
+
+func newWorkspace(namespace, name, workspaceID string) *svcapitypes.Workspace {
+	ws := &svcapitypes.Workspace{}
+	ws.Namespace, ws.Name = namespace, name
+	ws.UID = types.UID(name + "-uid")
+	if workspaceID != "" {
+		ws.Status.WorkspaceID = aws.String(workspaceID)
+	}
+	return ws
+}
+
+func newRuleGroupsNamespace(namespace, name string) *svcapitypes.RuleGroupsNamespace {
+	ko := &svcapitypes.RuleGroupsNamespace{}
+	ko.Namespace, ko.Name = namespace, name
+	return ko
+}
+
+func workspaceRef(ws *svcapitypes.Workspace) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: svcapitypes.GroupVersion.String(),
+		Kind:       "Workspace",
+		Name:       ws.Name,
+		UID:        ws.UID,
+	}
+}
+
+func newTestKubeClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestDependsOn(t *testing.T) {
+	ws := newWorkspace("monitoring", "main", "ws-1")
+	tests := []struct {
+		name         string
+		namespace    string
+		workspaceID  *string
+		workspaceRef *svcapitypes.ResourceReferenceWrapper
+		want         bool
+	}{
+		{
+			name:        "same workspace ID",
+			namespace:   "apps",
+			workspaceID: aws.String("ws-1"),
+			want:        true,
+		},
+		{
+			name:        "other workspace ID",
+			namespace:   "monitoring",
+			workspaceID: aws.String("ws-2"),
+			want:        false,
+		},
+		{
+			name:      "reference in the same namespace",
+			namespace: "monitoring",
+			workspaceRef: &svcapitypes.ResourceReferenceWrapper{
+				From: &svcapitypes.ResourceReference{Name: aws.String("main")},
+			},
+			want: true,
+		},
+		{
+			name:      "reference in another namespace",
+			namespace: "apps",
+			workspaceRef: &svcapitypes.ResourceReferenceWrapper{
+				From: &svcapitypes.ResourceReference{Name: aws.String("main")},
+			},
+			want: false,
+		},
+		{
+			name:      "reference with a namespace",
+			namespace: "apps",
+			workspaceRef: &svcapitypes.ResourceReferenceWrapper{
+				From: &svcapitypes.ResourceReference{
+					Name:      aws.String("main"),
+					Namespace: aws.String("monitoring"),
+				},
+			},
+			want: true,
+		},
+		{
+			// The workspace ID resolved from a reference is not persisted,
+			// but is checked against the reference rather than the ID.
+			name:        "reference to another workspace with the same ID",
+			namespace:   "monitoring",
+			workspaceID: aws.String("ws-1"),
+			workspaceRef: &svcapitypes.ResourceReferenceWrapper{
+				From: &svcapitypes.ResourceReference{Name: aws.String("other")},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DependsOn(ws, tt.namespace, tt.workspaceID, tt.workspaceRef); got != tt.want {
+				t.Errorf("DependsOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	main := newWorkspace("monitoring", "main", "ws-1")
+	other := newWorkspace("monitoring", "other", "ws-2")
+	pending := newWorkspace("monitoring", "pending", "")
+	remote := newWorkspace("apps", "remote", "ws-3")
+	retained := newWorkspace("monitoring", "retained", "ws-4")
+	retained.Annotations = map[string]string{
+		ackv1alpha1.AnnotationDeletionPolicy: string(ackv1alpha1.DeletionPolicyRetain),
+	}
+	configMapRef := metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       "rules",
+		UID:        "configmap-uid",
+	}
+
+	tests := []struct {
+		name string
+		ko   func() *svcapitypes.RuleGroupsNamespace
+		want []metav1.OwnerReference
+	}{
+		{
+			name: "workspace ID",
+			ko: func() *svcapitypes.RuleGroupsNamespace {
+				ko := newRuleGroupsNamespace("monitoring", "rules")
+				ko.Spec.WorkspaceID = aws.String("ws-1")
+				return ko
+			},
+			want: []metav1.OwnerReference{workspaceRef(main)},
+		},
+		{
+			name: "workspace reference keeps other owners",
+			ko: func() *svcapitypes.RuleGroupsNamespace {
+				ko := newRuleGroupsNamespace("monitoring", "rules")
+				ko.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{
+					From: &svcapitypes.ResourceReference{Name: aws.String("main")},
+				}
+				ko.OwnerReferences = []metav1.OwnerReference{configMapRef}
+				return ko
+			},
+			want: []metav1.OwnerReference{configMapRef, workspaceRef(main)},
+		},
+		{
+			name: "changed workspace",
+			ko: func() *svcapitypes.RuleGroupsNamespace {
+				ko := newRuleGroupsNamespace("monitoring", "rules")
+				ko.Spec.WorkspaceID = aws.String("ws-2")
+				ko.OwnerReferences = []metav1.OwnerReference{workspaceRef(main)}
+				return ko
+			},
+			want: []metav1.OwnerReference{workspaceRef(other)},
+		},
+		{
+			name: "workspace ID not resolved yet",
+			ko: func() *svcapitypes.RuleGroupsNamespace {
+				ko := newRuleGroupsNamespace("monitoring", "rules")
+				ko.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{
+					From: &svcapitypes.ResourceReference{Name: aws.String("pending")},
+				}
+				return ko
+			},
+			want: nil,
+		},
+		{
+			name: "workspace in another namespace",
+			ko: func() *svcapitypes.RuleGroupsNamespace {
+				ko := newRuleGroupsNamespace("monitoring", "rules")
+				ko.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{
+					From: &svcapitypes.ResourceReference{
+						Name:      aws.String("remote"),
+						Namespace: aws.String("apps"),
+					},
+				}
+				return ko
+			},
+			want: nil,
+		},
+		{
+			name: "retained workspace",
+			ko: func() *svcapitypes.RuleGroupsNamespace {
+				ko := newRuleGroupsNamespace("monitoring", "rules")
+				ko.Spec.WorkspaceID = aws.String("ws-4")
+				ko.OwnerReferences = []metav1.OwnerReference{workspaceRef(retained)}
+				return ko
+			},
+			want: nil,
+		},
+		{
+			name: "opted out",
+			ko: func() *svcapitypes.RuleGroupsNamespace {
+				ko := newRuleGroupsNamespace("monitoring", "rules")
+				ko.Annotations = map[string]string{
+					svcapitypes.AnnotationWorkspaceOwnerReference: "false",
+				}
+				ko.Spec.WorkspaceID = aws.String("ws-1")
+				ko.OwnerReferences = []metav1.OwnerReference{configMapRef, workspaceRef(main)}
+				return ko
+			},
+			want: []metav1.OwnerReference{configMapRef},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := newTestKubeClient(t, main, other, pending, remote, retained, tt.ko())
+			r := &reconciler{kc: kc, apiReader: kc, log: logr.Discard(), kind: testKind}
+			key := types.NamespacedName{Namespace: "monitoring", Name: "rules"}
+			if _, err := r.Reconcile(context.TODO(), ctrlrt.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &svcapitypes.RuleGroupsNamespace{}
+			if err := kc.Get(context.TODO(), key, got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got.OwnerReferences) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got.OwnerReferences, tt.want) {
+					t.Errorf("owner references = %v, want %v", got.OwnerReferences, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileDeletionPolicy(t *testing.T) {
+	const namespaceAnnotation = "prometheusservice.services.k8s.aws/deletion-policy"
+	retain, remove := string(ackv1alpha1.DeletionPolicyRetain), string(ackv1alpha1.DeletionPolicyDelete)
+	tests := []struct {
+		name                string
+		workspacePolicy     string
+		namespacePolicy     string
+		defaultPolicy       ackv1alpha1.DeletionPolicy
+		wantOwnerReferences bool
+	}{
+		{name: "default delete", defaultPolicy: ackv1alpha1.DeletionPolicyDelete, wantOwnerReferences: true},
+		{name: "default retain", defaultPolicy: ackv1alpha1.DeletionPolicyRetain},
+		{name: "namespace retain", namespacePolicy: retain, defaultPolicy: ackv1alpha1.DeletionPolicyDelete},
+		{name: "namespace delete", namespacePolicy: remove, defaultPolicy: ackv1alpha1.DeletionPolicyRetain, wantOwnerReferences: true},
+		{name: "workspace retain", workspacePolicy: retain, namespacePolicy: remove},
+		{name: "workspace delete", workspacePolicy: remove, namespacePolicy: retain, wantOwnerReferences: true},
+	}
+	defer func(policy ackv1alpha1.DeletionPolicy) { DefaultDeletionPolicy = policy }(DefaultDeletionPolicy)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			DefaultDeletionPolicy = tt.defaultPolicy
+			ns := &corev1.Namespace{}
+			ns.Name = "monitoring"
+			if tt.namespacePolicy != "" {
+				ns.Annotations = map[string]string{namespaceAnnotation: tt.namespacePolicy}
+			}
+			ws := newWorkspace("monitoring", "main", "ws-1")
+			if tt.workspacePolicy != "" {
+				ws.Annotations = map[string]string{ackv1alpha1.AnnotationDeletionPolicy: tt.workspacePolicy}
+			}
+			ko := newRuleGroupsNamespace("monitoring", "rules")
+			ko.Spec.WorkspaceID = aws.String("ws-1")
+
+			kc := newTestKubeClient(t, ns, ws, ko)
+			r := &reconciler{kc: kc, apiReader: kc, log: logr.Discard(), kind: testKind}
+			key := types.NamespacedName{Namespace: "monitoring", Name: "rules"}
+			if _, err := r.Reconcile(context.TODO(), ctrlrt.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &svcapitypes.RuleGroupsNamespace{}
+			if err := kc.Get(context.TODO(), key, got); err != nil {
+				t.Fatal(err)
+			}
+			if owned := IsOwnedBy(got, ws); owned != tt.wantOwnerReferences {
+				t.Errorf("owned by the workspace = %v, want %v", owned, tt.wantOwnerReferences)
+			}
+		})
+	}
+}
+
+func TestWorkspaceRequests(t *testing.T) {
+	main := newWorkspace("monitoring", "main", "ws-1")
+	byID := newRuleGroupsNamespace("monitoring", "by-id")
+	byID.Spec.WorkspaceID = aws.String("ws-1")
+	byRef := newRuleGroupsNamespace("monitoring", "by-ref")
+	byRef.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{
+		From: &svcapitypes.ResourceReference{Name: aws.String("main")},
+	}
+	unrelated := newRuleGroupsNamespace("monitoring", "unrelated")
+	unrelated.Spec.WorkspaceID = aws.String("ws-2")
+	otherNamespace := newRuleGroupsNamespace("apps", "other-namespace")
+	otherNamespace.Spec.WorkspaceID = aws.String("ws-1")
+	kc := newTestKubeClient(t, byID, byRef, unrelated, otherNamespace)
+
+	var got []string
+	for _, req := range workspaceRequests(kc, testKind)(main) {
+		got = append(got, req.String())
+	}
+	want := []string{"monitoring/by-id", "monitoring/by-ref"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("workspaceRequests() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package workspaceref resolves the WorkspaceRef fields of the resources
+// created in a workspace. Unlike the references resolved by ack-generate, a
+// WorkspaceRef may name a Workspace in another namespace.
+package workspaceref
+
+import (
+	"context"
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// Validate returns an error unless exactly one of the supplied WorkspaceID
+// and WorkspaceRef fields is set.
+func Validate(workspaceID *string, workspaceRef *svcapitypes.ResourceReferenceWrapper) error {
+	if workspaceRef != nil && workspaceID != nil {
+		return ackerr.ResourceReferenceAndIDNotSupportedFor("WorkspaceID", "WorkspaceRef")
+	}
+	if workspaceRef == nil && workspaceID == nil {
+		return ackerr.ResourceReferenceOrIDRequiredFor("WorkspaceID", "WorkspaceRef")
+	}
+	return nil
+}
+
+// Resolve reads the Workspace referenced by the supplied WorkspaceRef field
+// of a resource in the supplied namespace and returns its workspace ID. It
+// returns nil if the field does not reference a Workspace.
+func Resolve(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	workspaceRef *svcapitypes.ResourceReferenceWrapper,
+) (*string, error) {
+	if workspaceRef == nil || workspaceRef.From == nil {
+		return nil, nil
+	}
+	arr := workspaceRef.From
+	if arr.Name == nil || *arr.Name == "" {
+		return nil, fmt.Errorf("provided resource reference is nil or empty")
+	}
+	if arr.Namespace != nil && *arr.Namespace != "" {
+		namespace = *arr.Namespace
+	}
+	obj := svcapitypes.Workspace{}
+	if err := getReferencedResourceState(ctx, apiReader, &obj, namespace, *arr.Name); err != nil {
+		return nil, err
+	}
+	return obj.Status.WorkspaceID, nil
+}
+
+// getReferencedResourceState looks up whether the referenced Workspace exists
+// and is in a ACK.ResourceSynced=True state. If it does exist and is in a
+// Synced state, returns nil, otherwise returns
+// `ackerr.ResourceReferenceTerminalFor` or `ResourceReferenceNotSyncedFor`
+// depending on if the Workspace is in a Terminal state.
+func getReferencedResourceState(
+	ctx context.Context,
+	apiReader client.Reader,
+	obj *svcapitypes.Workspace,
+	namespace string,
+	name string,
+) error {
+	namespacedName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := apiReader.Get(ctx, namespacedName, obj)
+	if err != nil {
+		return err
+	}
+	var refResourceSynced, refResourceTerminal bool
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == ackv1alpha1.ConditionTypeResourceSynced &&
+			cond.Status == corev1.ConditionTrue {
+			refResourceSynced = true
+		}
+		if cond.Type == ackv1alpha1.ConditionTypeTerminal &&
+			cond.Status == corev1.ConditionTrue {
+			refResourceTerminal = true
+		}
+	}
+	if refResourceTerminal {
+		return ackerr.ResourceReferenceTerminalFor(
+			"Workspace",
+			namespace, name)
+	}
+	if !refResourceSynced {
+		return ackerr.ResourceReferenceNotSyncedFor(
+			"Workspace",
+			namespace, name)
+	}
+	if obj.Status.WorkspaceID == nil {
+		return ackerr.ResourceReferenceMissingTargetFieldFor(
+			"Workspace",
+			namespace, name,
+			"Status.WorkspaceID")
+	}
+	return nil
+}
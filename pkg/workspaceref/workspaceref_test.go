@@ -0,0 +1,188 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspaceref
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+func newWorkspace(
+	namespace, name, workspaceID string,
+	conditions ...ackv1alpha1.ConditionType,
+) *svcapitypes.Workspace {
+	ws := &svcapitypes.Workspace{}
+	ws.Namespace, ws.Name = namespace, name
+	if workspaceID != "" {
+		ws.Status.WorkspaceID = aws.String(workspaceID)
+	}
+	for _, c := range conditions {
+		ws.Status.Conditions = append(ws.Status.Conditions, &ackv1alpha1.Condition{
+			Type:   c,
+			Status: corev1.ConditionTrue,
+		})
+	}
+	return ws
+}
+
+func newReference(namespace, name string) *svcapitypes.ResourceReferenceWrapper {
+	ref := &svcapitypes.ResourceReferenceWrapper{
+		From: &svcapitypes.ResourceReference{Name: aws.String(name)},
+	}
+	if namespace != "" {
+		ref.From.Namespace = aws.String(namespace)
+	}
+	return ref
+}
+
+func newTestReader(t *testing.T, objs ...client.Object) client.Reader {
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		workspaceID  *string
+		workspaceRef *svcapitypes.ResourceReferenceWrapper
+		wantErr      error
+	}{
+		{
+			name:        "workspace ID",
+			workspaceID: aws.String("ws-1"),
+		},
+		{
+			name:         "workspace reference",
+			workspaceRef: newReference("", "main"),
+		},
+		{
+			name:         "both set",
+			workspaceID:  aws.String("ws-1"),
+			workspaceRef: newReference("", "main"),
+			wantErr:      ackerr.ResourceReferenceAndIDNotSupported,
+		},
+		{
+			name:    "neither set",
+			wantErr: ackerr.ResourceReferenceOrIDRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.workspaceID, tt.workspaceRef)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	reader := newTestReader(t,
+		newWorkspace("apps", "synced", "ws-1", ackv1alpha1.ConditionTypeResourceSynced),
+		newWorkspace("monitoring", "synced", "ws-2", ackv1alpha1.ConditionTypeResourceSynced),
+		newWorkspace("apps", "creating", ""),
+		newWorkspace("apps", "terminal", "", ackv1alpha1.ConditionTypeResourceSynced, ackv1alpha1.ConditionTypeTerminal),
+		newWorkspace("apps", "missing-id", "", ackv1alpha1.ConditionTypeResourceSynced),
+	)
+	tests := []struct {
+		name         string
+		workspaceRef *svcapitypes.ResourceReferenceWrapper
+		want         *string
+		wantErr      error
+		wantNotFound bool
+	}{
+		{
+			name: "no reference",
+		},
+		{
+			name:         "no referenced resource",
+			workspaceRef: &svcapitypes.ResourceReferenceWrapper{},
+		},
+		{
+			name:         "same namespace",
+			workspaceRef: newReference("", "synced"),
+			want:         aws.String("ws-1"),
+		},
+		{
+			name:         "cross namespace",
+			workspaceRef: newReference("monitoring", "synced"),
+			want:         aws.String("ws-2"),
+		},
+		{
+			name:         "not synced",
+			workspaceRef: newReference("", "creating"),
+			wantErr:      ackerr.ResourceReferenceNotSynced,
+		},
+		{
+			name:         "terminal",
+			workspaceRef: newReference("", "terminal"),
+			wantErr:      ackerr.ResourceReferenceTerminal,
+		},
+		{
+			name:         "missing workspace ID",
+			workspaceRef: newReference("", "missing-id"),
+			wantErr:      ackerr.ResourceReferenceMissingTargetField,
+		},
+		{
+			name:         "not found",
+			workspaceRef: newReference("monitoring", "creating"),
+			wantNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(context.TODO(), reader, "apps", tt.workspaceRef)
+			switch {
+			case tt.wantNotFound:
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("Resolve() error = %v, want a not found error", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("Resolve() error = %v, want nil", err)
+			}
+			if aws.StringValue(got) != aws.StringValue(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("Resolve() = %v, want %v", aws.StringValue(got), aws.StringValue(tt.want))
+			}
+		})
+	}
+}
+
+func TestResolveEmptyName(t *testing.T) {
+	_, err := Resolve(context.TODO(), newTestReader(t), "apps", newReference("", ""))
+	if err == nil {
+		t.Errorf("Resolve() error = nil, want an error for an empty name")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspacestatus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+)
+
+var (
+	// TTL is the duration for which the status of a workspace is cached.
+	TTL = 30 * time.Second
+
+	cachesMutex sync.Mutex
+	// caches contains the status cache of each AWS account and region, keyed
+	// the same way as the resource manager caches of the resource manager
+	// factories.
+	caches = map[string]*Cache{}
+)
+
+// Cache holds the status codes of the workspaces of an AWS account and
+// region for a limited time, so that the resources created in a workspace do
+// not each describe it on every reconciliation.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	statusCode string
+	expires    time.Time
+}
+
+// NewCache returns an empty Cache whose entries expire after the supplied
+// duration.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+// For returns the shared status cache of the workspaces of the supplied AWS
+// account and region.
+func For(id ackv1alpha1.AWSAccountID, region ackv1alpha1.AWSRegion) *Cache {
+	key := fmt.Sprintf("%s/%s", id, region)
+	cachesMutex.Lock()
+	defer cachesMutex.Unlock()
+	c, ok := caches[key]
+	if !ok {
+		c = NewCache(TTL)
+		caches[key] = c
+	}
+	return c
+}
+
+// Get returns the cached status code of the supplied workspace and whether it
+// was found and has not expired.
+func (c *Cache) Get(workspaceID string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[workspaceID]
+	if !ok {
+		return "", false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, workspaceID)
+		return "", false
+	}
+	return entry.statusCode, true
+}
+
+// Observe caches the supplied status code of a workspace, replacing any
+// previously cached one. An empty status code is not cached, and removes any
+// previously cached one.
+func (c *Cache) Observe(workspaceID string, statusCode string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if statusCode == "" {
+		delete(c.entries, workspaceID)
+		return
+	}
+	c.entries[workspaceID] = cacheEntry{
+		statusCode: statusCode,
+		expires:    c.now().Add(c.ttl),
+	}
+}
+
+// Invalidate removes the cached status code of the supplied workspace.
+func (c *Cache) Invalidate(workspaceID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, workspaceID)
+}
@@ -0,0 +1,112 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspacestatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+)
+
+func newTestCache(now *time.Time) *Cache {
+	c := NewCache(time.Minute)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	c := newTestCache(&now)
+
+	if _, ok := c.Get("ws-1"); ok {
+		t.Fatalf("Get() found a status in an empty cache")
+	}
+	c.Observe("ws-1", svcsdk.WorkspaceStatusCodeCreating)
+	if statusCode, ok := c.Get("ws-1"); !ok || statusCode != svcsdk.WorkspaceStatusCodeCreating {
+		t.Errorf("Get() = %q, %v, want CREATING, true", statusCode, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("ws-1"); ok {
+		t.Errorf("Get() found an expired status")
+	}
+
+	c.Observe("ws-1", svcsdk.WorkspaceStatusCodeActive)
+	c.Invalidate("ws-1")
+	if _, ok := c.Get("ws-1"); ok {
+		t.Errorf("Get() found an invalidated status")
+	}
+
+	c.Observe("ws-1", svcsdk.WorkspaceStatusCodeActive)
+	c.Observe("ws-1", "")
+	if statusCode, ok := c.Get("ws-1"); ok {
+		t.Errorf("Get() = %q, want an empty status not to be cached", statusCode)
+	}
+}
+
+func TestFor(t *testing.T) {
+	if For("111111111111", "us-west-2") != For("111111111111", "us-west-2") {
+		t.Errorf("For() returned different caches for the same account and region")
+	}
+	if For("111111111111", "us-west-2") == For("111111111111", "eu-west-1") {
+		t.Errorf("For() returned the same cache for different regions")
+	}
+	if For("111111111111", "us-west-2") == For("222222222222", "us-west-2") {
+		t.Errorf("For() returned the same cache for different accounts")
+	}
+}
+
+func TestEnsureActiveCached(t *testing.T) {
+	now := time.Now()
+	c := newTestCache(&now)
+	api := &fakeAPI{statusCodes: map[string]string{
+		"ws-1": svcsdk.WorkspaceStatusCodeCreating,
+	}}
+
+	var notReady *NotReadyError
+	for i := 0; i < 3; i++ {
+		if err := EnsureActive(context.TODO(), api, nil, c, "ws-1"); !errors.As(err, &notReady) {
+			t.Fatalf("EnsureActive() error = %v, want a NotReadyError", err)
+		}
+	}
+	if api.calls != 1 {
+		t.Errorf("DescribeWorkspace calls = %d, want 1", api.calls)
+	}
+
+	// The Workspace reconciler observed the workspace becoming ACTIVE.
+	api.statusCodes["ws-1"] = svcsdk.WorkspaceStatusCodeActive
+	c.Invalidate("ws-1")
+	if err := EnsureActive(context.TODO(), api, nil, c, "ws-1"); err != nil {
+		t.Errorf("EnsureActive() unexpected error = %v", err)
+	}
+	if api.calls != 2 {
+		t.Errorf("DescribeWorkspace calls = %d, want 2", api.calls)
+	}
+
+	// Errors are not cached.
+	api.err = errors.New("throttled")
+	if err := EnsureActive(context.TODO(), api, nil, c, "ws-2"); err != api.err {
+		t.Errorf("EnsureActive() error = %v, want %v", err, api.err)
+	}
+	api.err = nil
+	if err := EnsureActive(context.TODO(), api, nil, c, "ws-2"); !errors.As(err, &notReady) {
+		t.Errorf("EnsureActive() error = %v, want a NotReadyError", err)
+	}
+	if api.calls != 4 {
+		t.Errorf("DescribeWorkspace calls = %d, want 4", api.calls)
+	}
+}
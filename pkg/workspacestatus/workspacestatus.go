@@ -0,0 +1,146 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package workspacestatus reads the status of the AMP workspaces that rule
+// groups namespaces and alert manager definitions are created in. AMP
+// rejects the creation of these resources with a ConflictException until
+// their workspace is ACTIVE.
+package workspacestatus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeWorkspaceNotReady is set to True on resources whose
+	// creation waits for their workspace to be ACTIVE.
+	ConditionTypeWorkspaceNotReady ackv1alpha1.ConditionType = "WorkspaceNotReady"
+
+	// statusCodeNotFound is reported for workspaces that do not exist.
+	statusCodeNotFound = "NOT_FOUND"
+)
+
+var (
+	// RequeueAfter is the delay before a resource waiting for its workspace
+	// is reconciled again.
+	RequeueAfter = 15 * time.Second
+)
+
+// API is the part of the AMP API used to read the status of workspaces.
+type API interface {
+	DescribeWorkspaceWithContext(
+		context.Context,
+		*svcsdk.DescribeWorkspaceInput,
+		...request.Option,
+	) (*svcsdk.DescribeWorkspaceOutput, error)
+}
+
+// NotReadyError is returned for workspaces that are not ACTIVE.
+type NotReadyError struct {
+	WorkspaceID string
+	StatusCode  string
+}
+
+func (e *NotReadyError) Error() string {
+	switch e.StatusCode {
+	case statusCodeNotFound:
+		return fmt.Sprintf("workspace %s not found, waiting for it to be ACTIVE", e.WorkspaceID)
+	case "":
+		return fmt.Sprintf("workspace %s has no status, waiting for it to be ACTIVE", e.WorkspaceID)
+	}
+	return fmt.Sprintf("workspace %s is %s, waiting for it to be ACTIVE", e.WorkspaceID, e.StatusCode)
+}
+
+// EnsureActive returns a NotReadyError if the supplied workspace does not
+// exist or is not ACTIVE. The status of the workspace is read from the
+// supplied cache, if any, and only described when it is not cached.
+func EnsureActive(
+	ctx context.Context,
+	api API,
+	metrics *ackmetrics.Metrics,
+	cache *Cache,
+	workspaceID string,
+) error {
+	statusCode, ok := cache.Get(workspaceID)
+	if !ok {
+		var err error
+		statusCode, err = describeStatus(ctx, api, metrics, workspaceID)
+		if err != nil {
+			return err
+		}
+		cache.Observe(workspaceID, statusCode)
+	}
+	if statusCode != svcsdk.WorkspaceStatusCodeActive {
+		return &NotReadyError{WorkspaceID: workspaceID, StatusCode: statusCode}
+	}
+	return nil
+}
+
+// describeStatus returns the status code of the supplied workspace, or
+// NOT_FOUND if it does not exist.
+func describeStatus(
+	ctx context.Context,
+	api API,
+	metrics *ackmetrics.Metrics,
+	workspaceID string,
+) (string, error) {
+	resp, err := api.DescribeWorkspaceWithContext(ctx, &svcsdk.DescribeWorkspaceInput{
+		WorkspaceId: &workspaceID,
+	})
+	if metrics != nil {
+		metrics.RecordAPICall("READ_ONE", "DescribeWorkspace", err)
+	}
+	if err != nil {
+		if awsErr, ok := ackerr.AWSError(err); ok && awsErr.Code() == "ResourceNotFoundException" {
+			return statusCodeNotFound, nil
+		}
+		return "", err
+	}
+	if resp.Workspace != nil && resp.Workspace.Status != nil && resp.Workspace.Status.StatusCode != nil {
+		return *resp.Workspace.Status.StatusCode, nil
+	}
+	return "", nil
+}
+
+// SetNotReady sets the WorkspaceNotReady condition of the supplied resource
+// to True with the message of the supplied error, marks the resource as not
+// synced and returns an error requeueing the resource.
+func SetNotReady(res acktypes.ConditionManager, notReady *NotReadyError) error {
+	message := notReady.Error()
+	reason := string(ConditionTypeWorkspaceNotReady)
+	condition := ackcondition.FirstOfType(res, ConditionTypeWorkspaceNotReady)
+	if condition == nil {
+		condition = &ackv1alpha1.Condition{Type: ConditionTypeWorkspaceNotReady}
+		res.ReplaceConditions(append(res.Conditions(), condition))
+	}
+	now := metav1.Now()
+	condition.Status = corev1.ConditionTrue
+	condition.LastTransitionTime = &now
+	condition.Message = &message
+	condition.Reason = &reason
+	ackcondition.SetSynced(res, corev1.ConditionFalse, nil, &reason)
+	return ackrequeue.NeededAfter(notReady, RequeueAfter)
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workspacestatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fakeAPI serves the status codes of workspaces from a map keyed by
+// workspace ID.
+type fakeAPI struct {
+	statusCodes map[string]string
+	err         error
+	calls       int
+}
+
+func (a *fakeAPI) DescribeWorkspaceWithContext(
+	_ context.Context,
+	input *svcsdk.DescribeWorkspaceInput,
+	_ ...request.Option,
+) (*svcsdk.DescribeWorkspaceOutput, error) {
+	a.calls++
+	if a.err != nil {
+		return nil, a.err
+	}
+	statusCode, ok := a.statusCodes[*input.WorkspaceId]
+	if !ok {
+		return nil, awserr.New("ResourceNotFoundException", "workspace not found", nil)
+	}
+	return &svcsdk.DescribeWorkspaceOutput{
+		Workspace: &svcsdk.WorkspaceDescription{
+			WorkspaceId: input.WorkspaceId,
+			Status:      &svcsdk.WorkspaceStatus{StatusCode: aws.String(statusCode)},
+		},
+	}, nil
+}
+
+// conditions implements acktypes.ConditionManager
+type conditions []*ackv1alpha1.Condition
+
+func (c *conditions) Conditions() []*ackv1alpha1.Condition {
+	return *c
+}
+
+func (c *conditions) ReplaceConditions(conditions []*ackv1alpha1.Condition) {
+	*c = conditions
+}
+
+func TestEnsureActive(t *testing.T) {
+	api := &fakeAPI{statusCodes: map[string]string{
+		"ws-active":   svcsdk.WorkspaceStatusCodeActive,
+		"ws-creating": svcsdk.WorkspaceStatusCodeCreating,
+		"ws-unknown":  "",
+	}}
+	tests := []struct {
+		workspaceID string
+		wantErr     string
+	}{
+		{workspaceID: "ws-active"},
+		{workspaceID: "ws-creating", wantErr: "workspace ws-creating is CREATING, waiting for it to be ACTIVE"},
+		{workspaceID: "ws-missing", wantErr: "workspace ws-missing not found, waiting for it to be ACTIVE"},
+		{workspaceID: "ws-unknown", wantErr: "workspace ws-unknown has no status, waiting for it to be ACTIVE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.workspaceID, func(t *testing.T) {
+			err := EnsureActive(context.TODO(), api, nil, nil, tt.workspaceID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("EnsureActive() unexpected error = %v", err)
+				}
+				return
+			}
+			var notReady *NotReadyError
+			if !errors.As(err, &notReady) || err.Error() != tt.wantErr {
+				t.Errorf("EnsureActive() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	api.err = errors.New("throttled")
+	if err := EnsureActive(context.TODO(), api, nil, nil, "ws-active"); err != api.err {
+		t.Errorf("EnsureActive() error = %v, want %v", err, api.err)
+	}
+}
+
+func TestSetNotReady(t *testing.T) {
+	res := &conditions{}
+	err := SetNotReady(res, &NotReadyError{WorkspaceID: "ws-1", StatusCode: "CREATING"})
+	var requeue *ackrequeue.RequeueNeededAfter
+	if !errors.As(err, &requeue) || requeue.Duration() != RequeueAfter {
+		t.Errorf("SetNotReady() error = %v, want a requeue after %s", err, RequeueAfter)
+	}
+	condition := ackcondition.FirstOfType(res, ConditionTypeWorkspaceNotReady)
+	if condition == nil || condition.Status != corev1.ConditionTrue ||
+		*condition.Message != "workspace ws-1 is CREATING, waiting for it to be ACTIVE" {
+		t.Errorf("SetNotReady() condition = %v", condition)
+	}
+	if synced := ackcondition.Synced(res); synced == nil || synced.Status != corev1.ConditionFalse {
+		t.Errorf("SetNotReady() synced condition = %v", synced)
+	}
+
+	SetNotReady(res, &NotReadyError{WorkspaceID: "ws-1", StatusCode: "UPDATING"})
+	if len(*res) != 2 {
+		t.Errorf("SetNotReady() conditions = %v, want 2", *res)
+	}
+}
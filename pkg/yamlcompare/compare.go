@@ -0,0 +1,65 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package yamlcompare compares YAML documents by their content, ignoring
+// formatting, key order and comments.
+package yamlcompare
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Equal reports whether the supplied YAML documents have the same content.
+// String values of the top-level nestedKeys are compared as YAML documents
+// themselves, such as the alertmanager_config of an alert manager
+// definition. If either document is not valid YAML, the documents are
+// compared as strings.
+func Equal(a, b string, nestedKeys ...string) bool {
+	if a == b {
+		return true
+	}
+	valueA, err := parse(a, nestedKeys)
+	if err != nil {
+		return false
+	}
+	valueB, err := parse(b, nestedKeys)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+func parse(doc string, nestedKeys []string) (interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(doc), &value); err != nil {
+		return nil, err
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value, nil
+	}
+	for _, key := range nestedKeys {
+		nested, ok := m[key].(string)
+		if !ok {
+			continue
+		}
+		var nestedValue interface{}
+		if err := yaml.Unmarshal([]byte(nested), &nestedValue); err != nil {
+			return nil, err
+		}
+		m[key] = nestedValue
+	}
+	return m, nil
+}
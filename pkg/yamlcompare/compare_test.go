@@ -0,0 +1,89 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package yamlcompare
+
+import (
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name       string
+		a          string
+		b          string
+		nestedKeys []string
+		want       bool
+	}{
+		{
+			name: "identical",
+			a:    "groups: []\n",
+			b:    "groups: []\n",
+			want: true,
+		},
+		{
+			name: "formatting, key order and comments",
+			a: `groups:
+- name: test
+  rules:
+  - record: r
+    expr: up
+`,
+			b: `# A comment
+groups:
+  - rules:
+      - expr: "up"   # trailing comment
+        record: r
+    name: test`,
+			want: true,
+		},
+		{
+			name: "different values",
+			a:    "groups:\n- name: a\n",
+			b:    "groups:\n- name: b\n",
+			want: false,
+		},
+		{
+			name: "different list order",
+			a:    "groups:\n- name: a\n- name: b\n",
+			b:    "groups:\n- name: b\n- name: a\n",
+			want: false,
+		},
+		{
+			name:       "nested document",
+			a:          "alertmanager_config: |\n  route:\n    receiver: default\n  receivers:\n  - name: default\n",
+			b:          "alertmanager_config: |\n  receivers:\n    - name: default\n  # comment\n  route: {receiver: default}\n",
+			nestedKeys: []string{"alertmanager_config"},
+			want:       true,
+		},
+		{
+			name: "nested document not compared semantically",
+			a:    "alertmanager_config: |\n  route:\n    receiver: default\n",
+			b:    "alertmanager_config: |\n  route: {receiver: default}\n",
+			want: false,
+		},
+		{
+			name: "invalid YAML",
+			a:    "groups: [\n",
+			b:    "groups: []\n",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b, tt.nestedKeys...); got != tt.want {
+				t.Errorf("Equal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package yamlvalidation helps validating YAML documents that are stored in
+// string fields of a resource spec, such as rule groups and Alertmanager
+// configurations. Errors are reported against the spec field, with the line
+// of the document they refer to.
+package yamlvalidation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var yamlLineRE = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// Validator collects the errors found in a YAML document.
+type Validator struct {
+	path  *field.Path
+	lines []string
+	errs  field.ErrorList
+}
+
+// New returns a Validator for the supplied document, which is the value of
+// the spec field at path.
+func New(path *field.Path, document string) *Validator {
+	return &Validator{
+		path:  path,
+		lines: strings.Split(document, "\n"),
+	}
+}
+
+// Errors returns the errors reported so far.
+func (v *Validator) Errors() field.ErrorList {
+	return v.errs
+}
+
+// Parse parses data, which starts after line lineOffset of the document, and
+// returns its root node. Node lines are relative to the document. Syntax
+// errors are reported and return false. An empty document returns a nil
+// node.
+func (v *Validator) Parse(data string, lineOffset int) (*yaml.Node, bool) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(data), &doc); err != nil {
+		msg := err.Error()
+		line := 0
+		if m := yamlLineRE.FindStringSubmatch(msg); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			line += lineOffset
+			msg = strings.TrimPrefix(msg, m[0])
+		}
+		msg = strings.TrimPrefix(msg, "yaml: ")
+		v.Errorf(line, "invalid YAML: %s", msg)
+		return nil, false
+	}
+	if len(doc.Content) == 0 {
+		return nil, true
+	}
+	shiftLines(doc.Content[0], lineOffset)
+	return doc.Content[0], true
+}
+
+func shiftLines(n *yaml.Node, offset int) {
+	n.Line += offset
+	for _, c := range n.Content {
+		shiftLines(c, offset)
+	}
+}
+
+// Errorf reports an error at the supplied line of the document. A line of
+// zero reports an error about the whole document.
+func (v *Validator) Errorf(line int, format string, args ...interface{}) {
+	detail := fmt.Sprintf(format, args...)
+	value := ""
+	if line > 0 {
+		detail = fmt.Sprintf("line %d: %s", line, detail)
+		if line <= len(v.lines) {
+			value = strings.TrimSpace(v.lines[line-1])
+		}
+	}
+	v.errs = append(v.errs, field.Invalid(v.path, value, detail))
+}
+
+// Mapping returns the value nodes of the supplied mapping node by key,
+// reporting unknown and repeated keys. If no allowed keys are supplied, any
+// key is accepted. It returns nil if the node is not a mapping.
+func (v *Validator) Mapping(
+	n *yaml.Node,
+	what string,
+	allowed ...string,
+) map[string]*yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		v.Errorf(n.Line, "%s must be a mapping", what)
+		return nil
+	}
+	fields := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		known := allowed == nil
+		for _, a := range allowed {
+			known = known || key.Value == a
+		}
+		switch {
+		case !known:
+			v.Errorf(key.Line, "field %q is not valid in %s", key.Value, what)
+		case fields[key.Value] != nil:
+			v.Errorf(key.Line, "field %q is repeated in %s", key.Value, what)
+		default:
+			fields[key.Value] = value
+		}
+	}
+	return fields
+}
+
+// Sequence returns the items of the supplied sequence node, reporting an
+// error if it is not a sequence. A nil node returns no items.
+func (v *Validator) Sequence(n *yaml.Node, what string) []*yaml.Node {
+	if n == nil {
+		return nil
+	}
+	if n.Kind != yaml.SequenceNode {
+		v.Errorf(n.Line, "%s must be a list", what)
+		return nil
+	}
+	return n.Content
+}
+
+// Scalar returns the value of the supplied node, reporting an error if it is
+// not a scalar. A nil node returns false without an error.
+func (v *Validator) Scalar(n *yaml.Node, what string) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	if n.Kind != yaml.ScalarNode {
+		v.Errorf(n.Line, "%s must be a string", what)
+		return "", false
+	}
+	return n.Value, true
+}
+
+// Duration reports an error if the supplied node is not a Prometheus
+// duration, such as "1m" or "1h30m".
+func (v *Validator) Duration(n *yaml.Node, what string) {
+	d, ok := v.Scalar(n, what)
+	if !ok {
+		return
+	}
+	if _, err := model.ParseDuration(d); err != nil {
+		v.Errorf(n.Line, "%s: not a valid duration string: %q", what, d)
+	}
+}
+
+// KeyLine returns the line of the supplied key in a mapping node.
+func KeyLine(n *yaml.Node, key string) int {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i].Line
+		}
+	}
+	return n.Line
+}
+
+// Position returns the line and column within the document of a position
+// within the value of the supplied scalar node. line and column start at 1.
+func (v *Validator) Position(n *yaml.Node, line, column int) (int, int) {
+	switch n.Style {
+	case yaml.LiteralStyle, yaml.FoldedStyle:
+		// Block scalars start on the line after the "|" or ">" indicator,
+		// and their indentation is set by their first line.
+		if n.Line < len(v.lines) {
+			text := v.lines[n.Line]
+			column += len(text) - len(strings.TrimLeft(text, " "))
+		}
+		return n.Line + line, column
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		if line == 1 {
+			column += n.Column
+		}
+	default:
+		if line == 1 {
+			column += n.Column - 1
+		}
+	}
+	return n.Line + line - 1, column
+}
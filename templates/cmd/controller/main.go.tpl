@@ -0,0 +1,171 @@
+{{ template "boilerplate" }}
+
+{{- /*
+This template overrides the cmd/controller/main.go template of ack-generate.
+It calls the hand-written extensions of cmd/controller/extensions.go to bind
+their flags, configure them, wrap the resource manager factories and set up
+their controllers.
+*/}}
+
+package main
+
+import (
+	"os"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackrtutil "github.com/aws-controllers-k8s/runtime/pkg/util"
+	ackrtwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	svcsdk "github.com/aws/aws-sdk-go/service/{{ .ServicePackageName }}"
+	flag "github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	ctrlrtmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	svctypes "github.com/aws-controllers-k8s/{{ .ServicePackageName }}-controller/apis/{{ .APIVersion }}"
+	svcresource "github.com/aws-controllers-k8s/{{ .ServicePackageName }}-controller/pkg/resource"
+{{ range $crdName := .SnakeCasedCRDNames }}
+	_ "github.com/aws-controllers-k8s/{{ $.ServicePackageName }}-controller/pkg/resource/{{ $crdName }}"
+{{- end }}
+
+	"github.com/aws-controllers-k8s/{{ .ServicePackageName }}-controller/pkg/version"
+)
+
+var (
+	awsServiceAPIGroup    = "{{ .APIGroup }}"
+	awsServiceAlias       = "{{ .ServicePackageName }}"
+	awsServiceEndpointsID = svcsdk.EndpointsID
+	scheme                = runtime.NewScheme()
+	setupLog              = ctrlrt.Log.WithName("setup")
+)
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	_ = svctypes.AddToScheme(scheme)
+	_ = ackv1alpha1.AddToScheme(scheme)
+}
+
+func main() {
+	var ackCfg ackcfg.Config
+	var ext extensions
+	ackCfg.BindFlags()
+	ext.bindFlags()
+	flag.Parse()
+	ackCfg.SetupLogger()
+
+	if err := ackCfg.Validate(); err != nil {
+		setupLog.Error(
+			err, "Unable to create controller manager",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+	if err := ext.configure(ackCfg); err != nil {
+		setupLog.Error(
+			err, "Unable to configure the controller",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
+	host, port, err := ackrtutil.GetHostPort(ackCfg.WebhookServerAddr)
+	if err != nil {
+		setupLog.Error(
+			err, "Unable to parse webhook server address.",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
+	mgr, err := ctrlrt.NewManager(ctrlrt.GetConfigOrDie(), ctrlrt.Options{
+		Scheme:             scheme,
+		Port:               port,
+		Host:               host,
+		MetricsBindAddress: ackCfg.MetricsAddr,
+		LeaderElection:     ackCfg.EnableLeaderElection,
+		LeaderElectionID:   awsServiceAPIGroup,
+		Namespace:          ackCfg.WatchNamespace,
+	})
+	if err != nil {
+		setupLog.Error(
+			err, "unable to create controller manager",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
+	managerFactories, err := ext.managerFactories(mgr, svcresource.GetManagerFactories())
+	if err != nil {
+		setupLog.Error(
+			err, "unable to create the resource manager factories",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
+	stopChan := ctrlrt.SetupSignalHandler()
+
+	setupLog.Info(
+		"initializing service controller",
+		"aws.service", awsServiceAlias,
+	)
+	sc := ackrt.NewServiceController(
+		awsServiceAlias, awsServiceAPIGroup, awsServiceEndpointsID,
+		acktypes.VersionInfo{
+			version.GitCommit,
+			version.GitVersion,
+			version.BuildDate,
+		},
+	).WithLogger(
+		ctrlrt.Log,
+	).WithResourceManagerFactories(
+		managerFactories,
+	).WithPrometheusRegistry(
+		ctrlrtmetrics.Registry,
+	)
+
+	if ackCfg.EnableWebhookServer {
+		webhooks := ackrtwebhook.GetWebhooks()
+		for _, webhook := range webhooks {
+			if err := webhook.Setup(mgr); err != nil {
+				setupLog.Error(
+					err, "unable to register webhook "+webhook.UID(),
+					"aws.service", awsServiceAlias,
+				)
+
+			}
+		}
+	}
+
+	if err = sc.BindControllerManager(mgr, ackCfg); err != nil {
+		setupLog.Error(
+			err, "unable bind to controller manager to service controller",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
+	if err = ext.setupWithManager(mgr, sc); err != nil {
+		setupLog.Error(
+			err, "unable to set up the controller extensions",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
+	setupLog.Info(
+		"starting manager",
+		"aws.service", awsServiceAlias,
+	)
+	if err := mgr.Start(stopChan); err != nil {
+		setupLog.Error(
+			err, "unable to start controller manager",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+}
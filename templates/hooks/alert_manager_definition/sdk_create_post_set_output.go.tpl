@@ -1,4 +1,11 @@
 
+	// Only the hash of a configuration read from a Secret is recorded, the
+	// configuration itself is never written back to the resource.
+	setConfigurationHash(ko)
+
+	// Record when the alert manager definition started being created, to
+	// report it as stalled if it stays CREATING for too long.
+	setAlertManagerDefinitionOperationStartTime(desired, ko)
 	// We expect the workspace to be in 'creating' status since we just
 	// issued the call to create it, but I suppose it doesn't hurt to check
 	// here.
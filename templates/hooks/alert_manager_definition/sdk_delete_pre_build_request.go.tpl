@@ -1,10 +1,7 @@
-	// Can't delete alert manager definition in non-(ACTIVE/CREATION_FAILED/UPDATE_FAILED) state
-    // Otherwise, API will return a 409 and ConflictException
-    if !alertManagerDefinitionStatusFailed(r) && !alertManagerDefinitionActive(r){
-		msg := "Cannot delete alert manager definition as the status is not ACTIVE/CREATION_FAILED/UPDATE_FAILED, current status=" + string(*r.ko.Status.StatusCode)
+	// Can't delete an alert manager definition that is being created, updated
+	// or deleted. Otherwise, API will return a 409 and ConflictException
+	if err = requeueWhileAlertManagerDefinitionModifying(r); err != nil {
+		msg := err.Error()
 		ackcondition.SetSynced(r, corev1.ConditionFalse, &msg, nil)
-		return r, ackrequeue.NeededAfter(
-			errors.New(msg),
-			ackrequeue.DefaultRequeueAfterDuration,
-		)
-	}
\ No newline at end of file
+		return r, err
+	}
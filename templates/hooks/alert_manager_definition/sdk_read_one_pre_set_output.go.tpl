@@ -17,6 +17,20 @@
 
 	}
 
+	// Status changes that the state machine does not expect are logged.
+	observeAlertManagerDefinitionTransition(ctx, r, ko)
+	// An alert manager definition that AMP leaves CREATING, UPDATING or
+	// DELETING for too long is reported as stalled and reconciled less often.
+	setAlertManagerDefinitionOperationStartTime(r, ko)
+	if err := rm.ensureAlertManagerDefinitionNotStalled(ko); err != nil {
+		return &resource{ko}, err
+	}
+
+	// The hash is only recorded for configurations read from a Secret.
+	if ko.Spec.ConfigurationSecretRef == nil {
+		ko.Status.ConfigurationHash = nil
+	}
+
     // When adding an invalid alert manager configuration, the AMP API has different behaviour
 	// for different kinds of invalid input. For some invalid input, the API returns an error (e.g. ValidationException) 
 	// instantly in the http response and we set the controller to terminal state. The specified
@@ -36,6 +50,8 @@
 	// Hence, we should treat the asynchronous validation errors similarly to how the regular http validation
 	// exceptions are treated in ACK. So when there is a failed creation/update, we don't change the configuration in the spec
 	// that caused this failed status, and also set to terminal error.
+	// The validating webhook in webhook.go rejects most of these configurations at
+	// admission time, but it is optional, so the failed statuses still need handling here.
 
 	// When a failed status occurs, we skip setting the configuration field to be what the API returns,
 	// and instead keep it to be what the user desires. We only do this once right after a resource becomes
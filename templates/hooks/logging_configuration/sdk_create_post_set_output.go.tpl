@@ -0,0 +1,12 @@
+	// Record when the logging configuration started being created, to
+	// report it as stalled if it stays CREATING for too long.
+	setLoggingConfigurationOperationStartTime(desired, ko)
+	// We expect the logging configuration to be in 'creating' status since we
+	// just issued the call to create it, but I suppose it doesn't hurt to
+	// check here.
+	if loggingConfigurationCreating(&resource{ko}) {
+		// Setting resource synced condition to false will trigger a requeue of
+		// the resource. No need to return a requeue error here.
+		ackcondition.SetSynced(&resource{ko}, corev1.ConditionFalse, nil, nil)
+		return &resource{ko}, nil
+	}
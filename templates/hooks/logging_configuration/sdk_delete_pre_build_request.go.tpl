@@ -0,0 +1,5 @@
+	// Can't delete a logging configuration that is being created, updated or
+	// deleted. Otherwise, API will return a 409 and ConflictException
+	if err = requeueWhileLoggingConfigurationModifying(r); err != nil {
+		return r, err
+	}
@@ -0,0 +1,42 @@
+	// Check the status of the logging configuration
+	if resp.LoggingConfiguration.Status != nil {
+		if resp.LoggingConfiguration.Status.StatusCode != nil {
+			ko.Status.StatusCode = resp.LoggingConfiguration.Status.StatusCode
+		} else {
+			ko.Status.StatusCode = nil
+		}
+		if resp.LoggingConfiguration.Status.StatusReason != nil {
+			ko.Status.StatusReason = resp.LoggingConfiguration.Status.StatusReason
+		} else {
+			ko.Status.StatusReason = nil
+		}
+	} else {
+		ko.Status.StatusCode = nil
+		ko.Status.StatusReason = nil
+	}
+
+	// Status changes that the state machine does not expect are logged.
+	observeLoggingConfigurationTransition(ctx, r, ko)
+	// A logging configuration that AMP leaves CREATING, UPDATING or DELETING
+	// for too long is reported as stalled and reconciled less often.
+	setLoggingConfigurationOperationStartTime(r, ko)
+	if err := rm.ensureLoggingConfigurationNotStalled(ko); err != nil {
+		return &resource{ko}, err
+	}
+
+	// A failed logging configuration stays failed until its log group is
+	// changed, so we report it as a terminal error for as long as the desired
+	// log group is the one that failed. When the user changes the log group,
+	// no error is returned so that the update can proceed. We also skip this
+	// while the CR is being deleted so that the deletion can still proceed.
+	if loggingConfigurationStatusFailed(&resource{ko}) && !r.IsBeingDeleted() &&
+		!loggingConfigurationLogGroupChanged(r, &resource{ko}) {
+		return &resource{ko}, newLoggingConfigurationFailedError(&resource{ko})
+	}
+
+	if loggingConfigurationCreating(&resource{ko}) || loggingConfigurationUpdating(&resource{ko}) {
+		// Setting resource synced condition to false will trigger a requeue of
+		// the resource. No need to return a requeue error here.
+		ackcondition.SetSynced(&resource{ko}, corev1.ConditionFalse, nil, nil)
+		return &resource{ko}, nil
+	}
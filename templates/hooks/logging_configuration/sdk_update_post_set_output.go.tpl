@@ -0,0 +1,13 @@
+	// Record when the logging configuration started being updated, to
+	// report it as stalled if it stays UPDATING for too long.
+	setLoggingConfigurationOperationStartTime(latest, ko)
+	// Some updates might be instant and the resource will remain in an active
+	// state. Others might take a while and the resource will be in an
+	// `UPDATING` state. If this is the case, then we want to requeue until
+	// the resource is done updating.
+	if loggingConfigurationUpdating(&resource{ko}) {
+		// Setting resource synced condition to false will trigger a requeue of
+		// the resource. No need to return a requeue error here.
+		ackcondition.SetSynced(&resource{ko}, corev1.ConditionFalse, nil, nil)
+		return &resource{ko}, nil
+	}
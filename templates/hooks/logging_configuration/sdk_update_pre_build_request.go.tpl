@@ -0,0 +1,7 @@
+	// Check if the logging configuration is being currently created, updated
+	// or deleted. If it is, then requeue because we can't update while it is
+	// in those states. For failed states (create & update) and active states,
+	// the user can still update the logging configuration.
+	if err = requeueWhileLoggingConfigurationModifying(latest); err != nil {
+		return desired, err
+	}
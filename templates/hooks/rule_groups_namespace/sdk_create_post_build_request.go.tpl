@@ -1,7 +1,8 @@
 	
-	// Convert the string version of the configuration to a byte slice
-	// because the API expects a base64 encoding. The conversion to base64
-	// is handled automatically by k8s.
-	if desired.ko.Spec.Configuration != nil {
-		input.Data = []byte(*desired.ko.Spec.Configuration)
+	// Convert the configuration, or the rendered rule groups, to a byte
+	// slice because the API expects a base64 encoding. The conversion to
+	// base64 is handled automatically by k8s.
+	input.Data, err = ruleGroupsNamespaceData(desired.ko)
+	if err != nil {
+		return nil, err
 	}
@@ -1,4 +1,7 @@
 
+	// Record when the rule groups namespace started being created, to
+	// report it as stalled if it stays CREATING for too long.
+	setRuleGroupsNamespaceOperationStartTime(desired, ko)
 	// We expect the rule group to be in 'creating' status since we just
 	// issued the call to create it, but I suppose it doesn't hurt to check
 	// here.
@@ -0,0 +1,3 @@
+	if notReady, err := rm.ensureWorkspaceActive(ctx, desired); err != nil {
+		return notReady, err
+	}
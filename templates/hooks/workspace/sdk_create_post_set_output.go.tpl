@@ -1,3 +1,9 @@
+	// Keep the workspace status cached for the resources created in this
+	// workspace in sync with the latest observed status.
+	rm.observeWorkspaceStatus(ko)
+	// Record when the workspace started being created, to report it as
+	// stalled if it stays CREATING for too long.
+	setWorkspaceOperationStartTime(desired, ko)
 	// We expect the workspace to be in 'creating' status since we just
 	// issued the call to create it, but I suppose it doesn't hurt to check
 	// here.
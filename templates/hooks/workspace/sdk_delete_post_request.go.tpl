@@ -0,0 +1,2 @@
+	// The workspace is no longer ACTIVE once its deletion is requested.
+	rm.invalidateWorkspaceStatus(r.ko)
@@ -0,0 +1,10 @@
+	// Deleting the workspace also deletes the metrics ingested into it, which
+	// cannot be recovered.
+	if protected, err := ensureNotDeletionProtected(r); err != nil {
+		return protected, err
+	}
+	// The resources created in the workspace would be left orphaned by its
+	// deletion.
+	if blocked, err := rm.ensureNoDependents(ctx, r); err != nil {
+		return blocked, err
+	}
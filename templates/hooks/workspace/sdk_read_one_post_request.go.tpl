@@ -0,0 +1,5 @@
+	// A workspace that no longer exists must not stay cached as ACTIVE for
+	// the resources created in it.
+	if awsErr, ok := ackerr.AWSError(err); ok && awsErr.Code() == "ResourceNotFoundException" {
+		rm.invalidateWorkspaceStatus(r.ko)
+	}
@@ -0,0 +1,22 @@
+	// Keep the workspace status cached for the resources created in this
+	// workspace in sync with the latest observed status.
+	rm.observeWorkspaceStatus(ko)
+	// The remote write and query URLs are derived from the Prometheus
+	// endpoint so that they are always in sync with the latest observed
+	// value.
+	setWorkspaceEndpoints(ko)
+	// Status changes that the state machine does not expect are logged.
+	observeWorkspaceTransition(ctx, r, ko)
+	// A workspace that AMP leaves CREATING, UPDATING or DELETING for too long
+	// is reported as stalled and reconciled less often.
+	setWorkspaceOperationStartTime(r, ko)
+	if err := rm.ensureWorkspaceNotStalled(ko); err != nil {
+		return &resource{ko}, err
+	}
+	// A workspace that failed to be created will never become ACTIVE without
+	// the CR being deleted and recreated, so we report it as a terminal
+	// error. We skip this while the CR is being deleted so that the deletion
+	// of the failed workspace can still proceed.
+	if workspaceCreationFailed(&resource{ko}) && !r.IsBeingDeleted() {
+		return &resource{ko}, newWorkspaceCreationFailedError(&resource{ko})
+	}
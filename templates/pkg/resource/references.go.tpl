@@ -0,0 +1,111 @@
+{{ template "boilerplate" }}
+
+package {{ .CRD.Names.Snake }}
+
+import (
+	"context"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+{{ if .CRD.HasReferenceFields }}
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+{{- end }}
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/{{ .ServicePackageName }}-controller/apis/{{ .APIVersion }}"
+{{- if .CRD.HasReferenceFields }}
+	"github.com/aws-controllers-k8s/{{ .ServicePackageName }}-controller/pkg/workspaceref"
+{{- end }}
+)
+
+{{/*
+This template overrides the references.go template of ack-generate. The
+only resources with reference fields, RuleGroupsNamespace and
+AlertManagerDefinition, reference a Workspace, possibly in another namespace,
+which is resolved by the workspaceref package. The references ack-generate
+does not know about, such as ConfigMap and Secret keys, are resolved by the
+resolveExtensionReferences method and detected by the
+hasNonNilExtensionReferences function written by hand for each of them.
+*/ -}}
+// ResolveReferences finds if there are any Reference field(s) present
+// inside AWSResource passed in the parameter and attempts to resolve
+// those reference field(s) into target field(s).
+// It returns an AWSResource with resolved reference(s), and an error if the
+// passed AWSResource's reference field(s) cannot be resolved.
+// This method also adds/updates the ConditionTypeReferencesResolved for the
+// AWSResource.
+func (rm *resourceManager) ResolveReferences(
+	ctx context.Context,
+	apiReader client.Reader,
+	res acktypes.AWSResource,
+) (acktypes.AWSResource, error) {
+{{- if not .CRD.HasReferenceFields }}
+	return res, nil
+{{- else }}
+	namespace := res.MetaObject().GetNamespace()
+	ko := rm.concreteResource(res).ko.DeepCopy()
+	// Resolving the extension references may clear them, so check for
+	// references before resolving them.
+	hasReferences := hasNonNilReferences(ko)
+	err := validateReferenceFields(ko)
+{{- range $fieldName, $field := .CRD.Fields }}
+{{- if $field.HasReference }}
+	if err == nil {
+		err = resolveReferenceFor{{ $field.FieldPathWithUnderscore }}(ctx, apiReader, namespace, ko)
+	}
+{{- end }}
+{{- end }}
+	if err == nil {
+		err = rm.resolveExtensionReferences(ctx, apiReader, namespace, ko)
+	}
+
+	if hasReferences {
+		return ackcondition.WithReferencesResolvedCondition(&resource{ko}, err)
+	}
+	return &resource{ko}, err
+{{- end }}
+}
+
+// validateReferenceFields validates the reference field and corresponding
+// identifier field.
+func validateReferenceFields(ko *svcapitypes.{{ .CRD.Names.Camel }}) error {
+{{- range $fieldName, $field := .CRD.Fields }}
+{{- if $field.HasReference }}
+	if err := workspaceref.Validate(ko.Spec.{{ $field.Names.Camel }}, ko.Spec.{{ $field.GetReferenceFieldName.Camel }}); err != nil {
+		return err
+	}
+{{- end }}
+{{- end }}
+	return nil
+}
+
+// hasNonNilReferences returns true if resource contains a reference to another
+// resource
+func hasNonNilReferences(ko *svcapitypes.{{ .CRD.Names.Camel }}) bool {
+	return false
+{{- range $fieldName, $field := .CRD.Fields }}
+{{- if $field.HasReference }} || (ko.Spec.{{ $field.GetReferenceFieldName.Camel }} != nil)
+{{- end }}
+{{- end }}
+{{- if .CRD.HasReferenceFields }} || hasNonNilExtensionReferences(ko){{ end }}
+}
+{{- range $fieldName, $field := .CRD.Fields }}
+{{- if $field.HasReference }}
+
+// resolveReferenceFor{{ $field.FieldPathWithUnderscore }} reads the resource reference, reads the
+// referenced resource and sets the resource's {{ $field.Names.Camel }} field
+func resolveReferenceFor{{ $field.FieldPathWithUnderscore }}(
+	ctx context.Context,
+	apiReader client.Reader,
+	namespace string,
+	ko *svcapitypes.{{ $.CRD.Names.Camel }},
+) error {
+	workspaceID, err := workspaceref.Resolve(ctx, apiReader, namespace, ko.Spec.{{ $field.GetReferenceFieldName.Camel }})
+	if err != nil {
+		return err
+	}
+	if workspaceID != nil {
+		ko.Spec.{{ $field.Names.Camel }} = workspaceID
+	}
+	return nil
+}
+{{- end }}
+{{- end }}
@@ -15,6 +15,7 @@
 """
 
 from dataclasses import replace
+import hashlib
 import logging
 import time
 import pytest
@@ -552,4 +553,119 @@ class TestAlertManagerDefinition:
         assert deleted
 
         _, deleted = k8s.delete_custom_resource(am_ref_2)
-        assert deleted
\ No newline at end of file
+        assert deleted
+    def test_alert_manager_definition_with_workspace_ref(self, prometheusservice_client, workspace_resource):
+        sns_topic_name = get_bootstrap_resources().AlertManagerSNSTopic.name
+        sns_topic_arn = get_bootstrap_resources().AlertManagerSNSTopic.arn
+        resource_name = random_suffix_name("alert-manager-definition", 30)
+
+        (workspace_ref, workspace_res) = workspace_resource
+        workspace_id = workspace_res['status']['workspaceID']
+
+        config_replacements = REPLACEMENT_VALUES.copy()
+        config_replacements['SNS_TOPIC_NAME'] = sns_topic_name
+        config_replacements['SNS_TOPIC_ARN'] = sns_topic_arn
+        configuration_data = load_prometheusservice_resource(
+            "alert_manager_configuration",
+            additional_replacements=config_replacements,
+        )
+        configuration_str = str(yaml.dump(configuration_data))
+        configuration_str_indented = configuration_str.replace('\n', '\n    ')
+
+        # Reference the workspace CR by name instead of its ID
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_REF_NAME'] = workspace_ref.name
+        replacements['ALERT_MANAGER_DEFINITION_NAME'] = resource_name
+        replacements['CONFIGURATION'] = configuration_str_indented
+
+        resource_data = load_prometheusservice_resource(
+            "alert_manager_definition_ref",
+            additional_replacements=replacements,
+        )
+
+        am_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+
+        k8s.create_custom_resource(am_ref, resource_data)
+        k8s.wait_resource_consumed_by_controller(am_ref)
+        assert k8s.get_resource_exists(am_ref)
+
+        assert k8s.wait_on_condition(am_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+        assert k8s.wait_on_condition(am_ref, "ACK.ReferencesResolved", "True", wait_periods=1)
+
+        # The alert manager definition should be created in the referenced workspace
+        latest = self.get_alert_manager_definition(prometheusservice_client, workspace_id)
+        assert latest is not None
+        assert latest['alertManagerDefinition']['status']['statusCode'] == 'ACTIVE'
+
+        _, deleted = k8s.delete_custom_resource(am_ref)
+        assert deleted
+
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        latest = self.get_alert_manager_definition(prometheusservice_client, workspace_id)
+        assert latest is None
+
+    def test_alert_manager_definition_from_secret(self, prometheusservice_client, workspace_resource):
+        sns_topic_name = get_bootstrap_resources().AlertManagerSNSTopic.name
+        sns_topic_arn = get_bootstrap_resources().AlertManagerSNSTopic.arn
+        resource_name = random_suffix_name("alert-manager-definition", 30)
+        secret_name = random_suffix_name("alert-manager-config", 30)
+        secret_key = "alertmanager.yaml"
+
+        (_, workspace_res) = workspace_resource
+        workspace_id = workspace_res['status']['workspaceID']
+
+        config_replacements = REPLACEMENT_VALUES.copy()
+        config_replacements['SNS_TOPIC_NAME'] = sns_topic_name
+        config_replacements['SNS_TOPIC_ARN'] = sns_topic_arn
+        configuration_data = load_prometheusservice_resource(
+            "alert_manager_configuration",
+            additional_replacements=config_replacements,
+        )
+        configuration_str = str(yaml.dump(configuration_data))
+        k8s.create_opaque_secret("default", secret_name, secret_key, configuration_str)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_ID'] = workspace_id
+        replacements['ALERT_MANAGER_DEFINITION_NAME'] = resource_name
+        replacements['SECRET_NAME'] = secret_name
+        replacements['SECRET_KEY'] = secret_key
+
+        resource_data = load_prometheusservice_resource(
+            "alert_manager_definition_secret",
+            additional_replacements=replacements,
+        )
+
+        am_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+
+        k8s.create_custom_resource(am_ref, resource_data)
+        k8s.wait_resource_consumed_by_controller(am_ref)
+        assert k8s.get_resource_exists(am_ref)
+
+        assert k8s.wait_on_condition(am_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+
+        # The configuration from the Secret is pushed to AMP
+        latest = self.get_alert_manager_definition(prometheusservice_client, workspace_id)
+        assert latest is not None
+        assert latest['alertManagerDefinition']['status']['statusCode'] == 'ACTIVE'
+        assert latest['alertManagerDefinition']['data'].decode('UTF-8') == configuration_str
+
+        # Only its hash is recorded on the resource
+        am_resource = k8s.get_resource(am_ref)
+        assert 'configuration' not in am_resource['spec']
+        expected_hash = "sha256:" + hashlib.sha256(configuration_str.encode('UTF-8')).hexdigest()
+        assert am_resource['status']['configurationHash'] == expected_hash
+
+        _, deleted = k8s.delete_custom_resource(am_ref)
+        assert deleted
+
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        latest = self.get_alert_manager_definition(prometheusservice_client, workspace_id)
+        assert latest is None
+
+        k8s.delete_secret("default", secret_name)
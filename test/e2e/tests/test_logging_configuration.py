@@ -0,0 +1,162 @@
+# Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License"). You may
+# not use this file except in compliance with the License. A copy of the
+# License is located at
+#
+#	 http://aws.amazon.com/apache2.0/
+#
+# or in the "license" file accompanying this file. This file is distributed
+# on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+# express or implied. See the License for the specific language governing
+# permissions and limitations under the License.
+
+"""Integration tests for the Amazon Managed Prometheus (AMP) Logging Configuration resource
+"""
+
+import logging
+import time
+import boto3
+import pytest
+
+from acktest.k8s import resource as k8s
+from acktest.resources import random_suffix_name
+from e2e import service_marker, CRD_GROUP, CRD_VERSION, load_prometheusservice_resource
+from e2e.replacement_values import REPLACEMENT_VALUES
+from e2e import condition
+
+RESOURCE_KIND = "loggingconfiguration"
+RESOURCE_PLURAL = "loggingconfigurations"
+
+MAX_WAIT_FOR_SYNCED_MINUTES = 10
+DELETE_WAIT_AFTER_SECONDS = 60
+
+@pytest.fixture(scope="module")
+def workspace_resource():
+        resource_name = random_suffix_name("amp-workspace", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_ALIAS'] = resource_name
+
+        resource_data = load_prometheusservice_resource(
+            "workspace",
+            additional_replacements=replacements,
+        )
+
+        workspace_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, "workspaces",
+            resource_name, namespace="default",
+        )
+
+        # Create workspace
+        k8s.create_custom_resource(workspace_ref, resource_data)
+        workspace_resource = k8s.wait_resource_consumed_by_controller(workspace_ref)
+
+        assert workspace_resource is not None
+        assert k8s.get_resource_exists(workspace_ref)
+
+        assert k8s.wait_on_condition(workspace_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+        assert 'workspaceID' in workspace_resource['status']
+
+        yield (workspace_ref, workspace_resource)
+
+        _, deleted = k8s.delete_custom_resource(workspace_ref)
+        assert deleted
+
+@pytest.fixture(scope="module")
+def log_group_arns():
+        # Create two CloudWatch log groups so that the logging configuration
+        # can be updated from one to the other.
+        logs_client = boto3.client('logs')
+        names = [random_suffix_name("ack-amp-logs", 24) for _ in range(2)]
+        arns = []
+        for name in names:
+            logs_client.create_log_group(logGroupName=name)
+            resp = logs_client.describe_log_groups(logGroupNamePrefix=name)
+            arns.append(resp['logGroups'][0]['arn'])
+
+        yield arns
+
+        for name in names:
+            logs_client.delete_log_group(logGroupName=name)
+
+@service_marker
+@pytest.mark.canary
+class TestLoggingConfiguration:
+    def get_logging_configuration(self, prometheusservice_client, workspace_id: str) -> dict:
+        try:
+            resp = prometheusservice_client.describe_logging_configuration(
+                workspaceId=workspace_id
+            )
+            return resp
+
+        except Exception as e:
+            logging.debug(e)
+            return None
+
+    def test_crud_logging_configuration(self, prometheusservice_client, workspace_resource, log_group_arns):
+        resource_name = random_suffix_name("logging-configuration", 30)
+
+        (_, workspace_res) = workspace_resource
+        workspace_id = workspace_res['status']['workspaceID']
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_ID'] = workspace_id
+        replacements['LOGGING_CONFIGURATION_NAME'] = resource_name
+        replacements['LOG_GROUP_ARN'] = log_group_arns[0]
+
+        resource_data = load_prometheusservice_resource(
+            "logging_configuration",
+            additional_replacements=replacements,
+        )
+
+        lc_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+
+        # Create the logging configuration
+        k8s.create_custom_resource(lc_ref, resource_data)
+        lc_resource = k8s.wait_resource_consumed_by_controller(lc_ref)
+
+        assert k8s.get_resource_exists(lc_ref)
+        assert lc_resource is not None
+        assert lc_resource['spec']['workspaceID'] == workspace_id
+        assert lc_resource['spec']['logGroupARN'] == log_group_arns[0]
+
+        assert k8s.wait_on_condition(lc_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+
+        # After the resource is synced, assert that logging configuration is active
+        latest = self.get_logging_configuration(prometheusservice_client, workspace_id)
+        assert latest is not None
+        assert latest['loggingConfiguration']['status']['statusCode'] == 'ACTIVE'
+        assert latest['loggingConfiguration']['logGroupArn'] == log_group_arns[0]
+
+        lc_resource = k8s.get_resource(lc_ref)
+        assert lc_resource['status']['statusCode'] == 'ACTIVE'
+        condition.assert_synced(lc_ref)
+
+        # Update the logging configuration to use the second log group
+        updates = {
+            "spec": {"logGroupARN": log_group_arns[1]},
+        }
+        k8s.patch_custom_resource(lc_ref, updates)
+
+        assert k8s.wait_on_condition(lc_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+
+        latest = self.get_logging_configuration(prometheusservice_client, workspace_id)
+        assert latest is not None
+        assert latest['loggingConfiguration']['status']['statusCode'] == 'ACTIVE'
+        assert latest['loggingConfiguration']['logGroupArn'] == log_group_arns[1]
+
+        lc_resource = k8s.get_resource(lc_ref)
+        assert lc_resource['status']['statusCode'] == 'ACTIVE'
+        condition.assert_synced(lc_ref)
+
+        # Delete the logging configuration
+        _, deleted = k8s.delete_custom_resource(lc_ref)
+        assert deleted
+
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        latest = self.get_logging_configuration(prometheusservice_client, workspace_id)
+        assert latest is None
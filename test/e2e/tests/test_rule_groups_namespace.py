@@ -19,6 +19,7 @@ import time
 import pytest
 import yaml
 
+from kubernetes import client as kubernetes_client
 from acktest.k8s import resource as k8s
 from acktest.k8s import condition
 from acktest import tags as tags
@@ -357,4 +358,213 @@ class TestRuleGroupsNamespace:
         assert deleted
 
         _, deleted = k8s.delete_custom_resource(rule_ref_2)
-        assert deleted
\ No newline at end of file
+        assert deleted
+    def test_rule_groups_namespace_with_workspace_ref(self, prometheusservice_client, workspace_resource):
+        resource_name = random_suffix_name("rule-groups-namespace", 30)
+
+        (workspace_ref, workspace_res) = workspace_resource
+        workspace_id = workspace_res['status']['workspaceID']
+
+        config_replacements = REPLACEMENT_VALUES.copy()
+        config_replacements['RULE_NAME'] = "test-rule"
+        configuration_data = load_prometheusservice_resource(
+            "rule_groups_configuration_data",
+            additional_replacements=config_replacements,
+        )
+        configuration_str = str(yaml.dump(configuration_data))
+        configuration_str_indented = configuration_str.replace('\n', '\n    ')
+
+        # Reference the workspace CR by name instead of its ID
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_REF_NAME'] = workspace_ref.name
+        replacements['RESOURCE_NAME'] = resource_name
+        replacements['RULE_GROUPS_NAME'] = resource_name
+        replacements['CONFIGURATION'] = configuration_str_indented
+
+        resource_data = load_prometheusservice_resource(
+            "rule_groups_namespace_ref",
+            additional_replacements=replacements,
+        )
+
+        rule_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+
+        k8s.create_custom_resource(rule_ref, resource_data)
+        k8s.wait_resource_consumed_by_controller(rule_ref)
+        assert k8s.get_resource_exists(rule_ref)
+
+        assert k8s.wait_on_condition(rule_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+        condition.assert_type_status(rule_ref, "ACK.ReferencesResolved", True)
+
+        # The rule groups namespace should be created in the referenced workspace
+        latest = self.get_rule_groups_namespace(prometheusservice_client, workspace_id, resource_name)
+        assert latest is not None
+        assert self.get_server_side_status(latest) == 'ACTIVE'
+
+        # Setting both the workspace ID and reference is rejected
+        updates = {
+            "spec": {"workspaceID": workspace_id},
+        }
+        k8s.patch_custom_resource(rule_ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        resource = k8s.get_resource(rule_ref)
+        refs_resolved = [c for c in resource['status']['conditions'] if c['type'] == "ACK.ReferencesResolved"]
+        assert len(refs_resolved) == 1
+        assert refs_resolved[0]['status'] != "True"
+
+        updates = {
+            "spec": {"workspaceID": None},
+        }
+        k8s.patch_custom_resource(rule_ref, updates)
+
+        _, deleted = k8s.delete_custom_resource(rule_ref)
+        assert deleted
+
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        latest = self.get_rule_groups_namespace(prometheusservice_client, workspace_id, resource_name)
+        assert latest is None
+
+    def test_rule_groups_namespace_with_groups(self, prometheusservice_client, workspace_resource):
+        resource_name = random_suffix_name("rule-groups-namespace", 30)
+
+        (_, workspace_res) = workspace_resource
+        workspace_id = workspace_res['status']['workspaceID']
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_ID'] = workspace_id
+        replacements['RESOURCE_NAME'] = resource_name
+        replacements['RULE_GROUPS_NAME'] = resource_name
+        replacements['RULE_NAME'] = "test-rule"
+
+        resource_data = load_prometheusservice_resource(
+            "rule_groups_namespace_groups",
+            additional_replacements=replacements,
+        )
+
+        rule_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+
+        k8s.create_custom_resource(rule_ref, resource_data)
+        k8s.wait_resource_consumed_by_controller(rule_ref)
+        assert k8s.get_resource_exists(rule_ref)
+
+        assert k8s.wait_on_condition(rule_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+
+        # The typed rule groups are rendered to the AMP YAML format
+        latest = self.get_rule_groups_namespace(prometheusservice_client, workspace_id, resource_name)
+        assert latest is not None
+        server_config = yaml.safe_load(latest['ruleGroupsNamespace']['data'].decode('utf-8'))
+        assert [g['name'] for g in server_config['groups']] == ["test-rule", "alert-test"]
+        alert = server_config['groups'][1]
+        assert alert['interval'] == "1m"
+        assert alert['rules'][0]['for'] == "2m"
+        assert alert['rules'][0]['labels'] == {"severity": "warning"}
+
+        # The CR keeps using the typed groups rather than the raw configuration
+        resource = k8s.get_resource(rule_ref)
+        assert 'groups' in resource['spec']
+        assert 'configuration' not in resource['spec']
+
+        # Updating a rule is propagated to AMP
+        groups = resource['spec']['groups']
+        groups[1]['rules'][0]['for'] = "5m"
+        updates = {
+            "spec": {"groups": groups},
+        }
+        k8s.patch_custom_resource(rule_ref, updates)
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(rule_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+
+        latest = self.get_rule_groups_namespace(prometheusservice_client, workspace_id, resource_name)
+        assert latest is not None
+        server_config = yaml.safe_load(latest['ruleGroupsNamespace']['data'].decode('utf-8'))
+        assert server_config['groups'][1]['rules'][0]['for'] == "5m"
+
+        _, deleted = k8s.delete_custom_resource(rule_ref)
+        assert deleted
+
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        latest = self.get_rule_groups_namespace(prometheusservice_client, workspace_id, resource_name)
+        assert latest is None
+
+    def test_rule_groups_namespace_from_config_map(self, prometheusservice_client, workspace_resource):
+        resource_name = random_suffix_name("rule-groups-namespace", 30)
+        config_map_name = random_suffix_name("rule-groups", 24)
+
+        (_, workspace_res) = workspace_resource
+        workspace_id = workspace_res['status']['workspaceID']
+
+        core_v1 = kubernetes_client.CoreV1Api(k8s._get_k8s_api_client())
+        recording_rules = {
+            "groups": [{
+                "name": "recording",
+                "rules": [{"record": "metric:recording_rule", "expr": "avg(rate(container_cpu_usage_seconds_total[5m]))"}],
+            }],
+        }
+        core_v1.create_namespaced_config_map("default", kubernetes_client.V1ConfigMap(
+            metadata=kubernetes_client.V1ObjectMeta(name=config_map_name),
+            data={"recording.yaml": yaml.safe_dump(recording_rules)},
+        ))
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_ID'] = workspace_id
+        replacements['RESOURCE_NAME'] = resource_name
+        replacements['RULE_GROUPS_NAME'] = resource_name
+        replacements['CONFIG_MAP_NAME'] = config_map_name
+
+        resource_data = load_prometheusservice_resource(
+            "rule_groups_namespace_configmap",
+            additional_replacements=replacements,
+        )
+
+        rule_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+
+        k8s.create_custom_resource(rule_ref, resource_data)
+        k8s.wait_resource_consumed_by_controller(rule_ref)
+        assert k8s.get_resource_exists(rule_ref)
+
+        assert k8s.wait_on_condition(rule_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+        assert k8s.wait_on_condition(rule_ref, "ACK.ReferencesResolved", "True", wait_periods=1)
+
+        # The optional alerting.yaml key does not exist yet
+        latest = self.get_rule_groups_namespace(prometheusservice_client, workspace_id, resource_name)
+        assert latest is not None
+        server_config = yaml.safe_load(latest['ruleGroupsNamespace']['data'].decode('utf-8'))
+        assert [g['name'] for g in server_config['groups']] == ["recording"]
+
+        # The resolved configuration is not written back to the CR
+        resource = k8s.get_resource(rule_ref)
+        assert 'configuration' not in resource['spec']
+
+        # Changing the ConfigMap is propagated to AMP
+        alerting_rules = {
+            "groups": [{
+                "name": "alerting",
+                "rules": [{"alert": "HighCPU", "expr": "metric:recording_rule > 0.9", "for": "5m"}],
+            }],
+        }
+        core_v1.patch_namespaced_config_map(config_map_name, "default", {
+            "data": {"alerting.yaml": yaml.safe_dump(alerting_rules)},
+        })
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(rule_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+
+        latest = self.get_rule_groups_namespace(prometheusservice_client, workspace_id, resource_name)
+        assert latest is not None
+        server_config = yaml.safe_load(latest['ruleGroupsNamespace']['data'].decode('utf-8'))
+        assert [g['name'] for g in server_config['groups']] == ["recording", "alerting"]
+
+        # A missing required ConfigMap is reported as an unresolved reference
+        core_v1.delete_namespaced_config_map(config_map_name, "default")
+        time.sleep(UPDATE_WAIT_AFTER_SECONDS)
+        assert k8s.wait_on_condition(rule_ref, "ACK.ReferencesResolved", "Unknown", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+
+        _, deleted = k8s.delete_custom_resource(rule_ref)
+        assert deleted
@@ -101,6 +101,14 @@ class TestWorkspace:
         assert workspace_resource['status']['status']['statusCode'] == 'ACTIVE'
         condition.assert_synced(workspace_ref)
 
+        # The Prometheus endpoint and the URLs derived from it should be
+        # populated in the CR's status.
+        endpoint = latest['workspace']['prometheusEndpoint']
+        assert workspace_resource['status']['prometheusEndpoint'] == endpoint
+        base = endpoint.rstrip('/')
+        assert workspace_resource['status']['remoteWriteURL'] == base + '/api/v1/remote_write'
+        assert workspace_resource['status']['queryURL'] == base + '/api/v1/query'
+
         # Next, we verify that the AMP server-side workspace values are the same as
         # defined in the CR. Afterwards, we modify the spec and verify that the AMP 
         # server-side resource shows the new value of the field. 
@@ -166,4 +174,42 @@ class TestWorkspace:
         _, deleted = k8s.delete_custom_resource(workspace_ref)
         assert deleted
         workspace = self.get_workspace(prometheusservice_client, workspace_resource['status']['workspaceID'])
-        assert workspace is None
\ No newline at end of file
+        assert workspace is None
+
+
+    def test_retain_workspace(self, prometheusservice_client):
+        resource_name = random_suffix_name("amp-workspace", 24)
+
+        replacements = REPLACEMENT_VALUES.copy()
+        replacements['WORKSPACE_ALIAS'] = resource_name
+
+        resource_data = load_prometheusservice_resource(
+            "workspace",
+            additional_replacements=replacements,
+        )
+        resource_data['metadata']['annotations'] = {
+            "services.k8s.aws/deletion-policy": "retain",
+        }
+
+        workspace_ref = k8s.CustomResourceReference(
+            CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+            resource_name, namespace="default",
+        )
+
+        k8s.create_custom_resource(workspace_ref, resource_data)
+        workspace_resource = k8s.wait_resource_consumed_by_controller(workspace_ref)
+        assert workspace_resource is not None
+        assert k8s.wait_on_condition(workspace_ref, "ACK.ResourceSynced", "True", wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES)
+        workspace_id = k8s.get_resource(workspace_ref)['status']['workspaceID']
+
+        # Deleting the CR removes the finalizer without deleting the AMP
+        # workspace, which can then be adopted again.
+        _, deleted = k8s.delete_custom_resource(workspace_ref)
+        assert deleted
+        assert not k8s.get_resource_exists(workspace_ref)
+
+        latest = self.get_workspace(prometheusservice_client, workspace_id)
+        assert latest is not None
+        assert latest['workspace']['status']['statusCode'] == 'ACTIVE'
+
+        prometheusservice_client.delete_workspace(workspaceId=workspace_id)
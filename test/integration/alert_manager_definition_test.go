@@ -0,0 +1,130 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// newAlertmanagerConfiguration returns an alert manager definition
+// configuration routing all the alerts to the receiver with the supplied
+// name.
+func newAlertmanagerConfiguration(receiver string) string {
+	return "alertmanager_config: |\n" +
+		"  route:\n" +
+		"    receiver: " + receiver + "\n" +
+		"  receivers:\n" +
+		"  - name: " + receiver + "\n"
+}
+
+// describeAlertManagerDefinition returns the alert manager definition of the
+// supplied custom resource in the fake AMP API.
+func describeAlertManagerDefinition(amd *svcapitypes.AlertManagerDefinition) (*svcsdk.AlertManagerDefinitionDescription, error) {
+	resp, err := api.DescribeAlertManagerDefinitionWithContext(context.TODO(), &svcsdk.DescribeAlertManagerDefinitionInput{
+		WorkspaceId: amd.Spec.WorkspaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.AlertManagerDefinition, nil
+}
+
+// alertManagerDefinitionHasReceiver returns nil if the alert manager
+// definition of the supplied custom resource is ACTIVE and routes the alerts
+// to the receiver with the supplied name.
+func alertManagerDefinitionHasReceiver(amd *svcapitypes.AlertManagerDefinition, receiver string) error {
+	desc, err := describeAlertManagerDefinition(amd)
+	if err != nil {
+		return err
+	}
+	if *desc.Status.StatusCode != svcsdk.AlertManagerDefinitionStatusCodeActive {
+		return fmt.Errorf("alert manager definition status = %s, want ACTIVE", *desc.Status.StatusCode)
+	}
+	if !strings.Contains(string(desc.Data), "receiver: "+receiver) {
+		return fmt.Errorf("alert manager definition data = %q, want a %s receiver", desc.Data, receiver)
+	}
+	return nil
+}
+
+func TestAlertManagerDefinition(t *testing.T) {
+	t.Parallel()
+	ws := newActiveWorkspace(t, "amd-lifecycle-workspace")
+	amd := &svcapitypes.AlertManagerDefinition{}
+	amd.Name = "amd-lifecycle"
+	amd.Spec.WorkspaceID = ws.Status.WorkspaceID
+	amd.Spec.Configuration = aws.String(newAlertmanagerConfiguration("created"))
+
+	if !t.Run("create", func(t *testing.T) {
+		create(t, amd)
+		waitForSynced(t, amd, func() error {
+			return alertManagerDefinitionHasReceiver(amd, "created")
+		})
+	}) {
+		t.FailNow()
+	}
+
+	t.Run("update", func(t *testing.T) {
+		update(t, amd, func() { amd.Spec.Configuration = aws.String(newAlertmanagerConfiguration("updated")) })
+		waitForSynced(t, amd, func() error {
+			return alertManagerDefinitionHasReceiver(amd, "updated")
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleteAndWait(t, amd)
+		if _, err := describeAlertManagerDefinition(amd); !isNotFound(err) {
+			t.Errorf("DescribeAlertManagerDefinition() error = %v, want a ResourceNotFoundException", err)
+		}
+	})
+}
+
+func TestAlertManagerDefinitionCreationFailed(t *testing.T) {
+	t.Parallel()
+	ws := newActiveWorkspace(t, "amd-creation-failed-workspace")
+	amd := &svcapitypes.AlertManagerDefinition{}
+	amd.Name = "amd-creation-failed"
+	amd.Spec.WorkspaceID = ws.Status.WorkspaceID
+	amd.Spec.Configuration = aws.String(newAlertmanagerConfiguration(invalidMarker))
+
+	create(t, amd)
+	eventually(t, "waiting for the creation of the alert manager definition to fail", func() error {
+		if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(amd), amd); err != nil {
+			return err
+		}
+		if aws.StringValue(amd.Status.StatusCode) != svcsdk.AlertManagerDefinitionStatusCodeCreationFailed ||
+			!strings.Contains(aws.StringValue(amd.Status.StatusReason), "error validating") {
+			return fmt.Errorf(
+				"alert manager definition status = %s (%s), want CREATION_FAILED",
+				aws.StringValue(amd.Status.StatusCode), aws.StringValue(amd.Status.StatusReason),
+			)
+		}
+		return nil
+	})
+
+	// Fixing the configuration recovers the alert manager definition.
+	update(t, amd, func() { amd.Spec.Configuration = aws.String(newAlertmanagerConfiguration("fixed")) })
+	waitForSynced(t, amd, func() error {
+		return alertManagerDefinitionHasReceiver(amd, "fixed")
+	})
+	deleteAndWait(t, amd)
+}
@@ -0,0 +1,112 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// logGroupARN returns the ARN of the CloudWatch log group with the supplied
+// name.
+func logGroupARN(name string) string {
+	return "arn:aws:logs:" + region + ":" + accountID + ":log-group:" + name + ":*"
+}
+
+// describeLoggingConfiguration returns the logging configuration of the
+// supplied custom resource in the fake AMP API.
+func describeLoggingConfiguration(lc *svcapitypes.LoggingConfiguration) (*svcsdk.LoggingConfigurationMetadata, error) {
+	resp, err := api.DescribeLoggingConfigurationWithContext(context.TODO(), &svcsdk.DescribeLoggingConfigurationInput{
+		WorkspaceId: lc.Spec.WorkspaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.LoggingConfiguration, nil
+}
+
+// loggingConfigurationHasLogGroup returns nil if the logging configuration
+// of the supplied custom resource is ACTIVE with the supplied log group.
+func loggingConfigurationHasLogGroup(lc *svcapitypes.LoggingConfiguration, arn string) error {
+	desc, err := describeLoggingConfiguration(lc)
+	if err != nil {
+		return err
+	}
+	if *desc.Status.StatusCode != svcsdk.LoggingConfigurationStatusCodeActive {
+		return fmt.Errorf("logging configuration status = %s, want ACTIVE", *desc.Status.StatusCode)
+	}
+	if aws.StringValue(desc.LogGroupArn) != arn {
+		return fmt.Errorf("logging configuration log group = %s, want %s", aws.StringValue(desc.LogGroupArn), arn)
+	}
+	return nil
+}
+
+func TestLoggingConfiguration(t *testing.T) {
+	t.Parallel()
+	ws := newActiveWorkspace(t, "lc-lifecycle-workspace")
+	lc := &svcapitypes.LoggingConfiguration{}
+	lc.Name = "lc-lifecycle"
+	lc.Spec.WorkspaceID = ws.Status.WorkspaceID
+	lc.Spec.LogGroupARN = aws.String(logGroupARN("created"))
+
+	if !t.Run("create", func(t *testing.T) {
+		create(t, lc)
+		waitForSynced(t, lc, func() error {
+			return loggingConfigurationHasLogGroup(lc, logGroupARN("created"))
+		})
+	}) {
+		t.FailNow()
+	}
+
+	t.Run("update", func(t *testing.T) {
+		update(t, lc, func() { lc.Spec.LogGroupARN = aws.String(logGroupARN("updated")) })
+		waitForSynced(t, lc, func() error {
+			return loggingConfigurationHasLogGroup(lc, logGroupARN("updated"))
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleteAndWait(t, lc)
+		if _, err := describeLoggingConfiguration(lc); !isNotFound(err) {
+			t.Errorf("DescribeLoggingConfiguration() error = %v, want a ResourceNotFoundException", err)
+		}
+	})
+}
+
+func TestLoggingConfigurationCreationFailed(t *testing.T) {
+	t.Parallel()
+	ws := newActiveWorkspace(t, "lc-creation-failed-workspace")
+	lc := &svcapitypes.LoggingConfiguration{}
+	lc.Name = "lc-creation-failed"
+	lc.Spec.WorkspaceID = ws.Status.WorkspaceID
+	lc.Spec.LogGroupARN = aws.String(logGroupARN(invalidMarker))
+
+	create(t, lc)
+	waitForCondition(t, lc, ackv1alpha1.ConditionTypeTerminal, corev1.ConditionTrue, "log group not found", nil)
+
+	// Fixing the log group recovers the logging configuration.
+	update(t, lc, func() { lc.Spec.LogGroupARN = aws.String(logGroupARN("fixed")) })
+	waitForSynced(t, lc, func() error {
+		return loggingConfigurationHasLogGroup(lc, logGroupARN("fixed"))
+	})
+	deleteAndWait(t, lc)
+}
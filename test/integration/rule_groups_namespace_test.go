@@ -0,0 +1,151 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// newRuleGroupsConfiguration returns a rule groups namespace configuration
+// with a single rule group with the supplied name.
+func newRuleGroupsConfiguration(group string) string {
+	return "groups:\n- name: " + group + "\n  rules:\n  - record: job:up:sum\n    expr: sum by (job) (up)\n"
+}
+
+// ruleGroupsNamespaceHasGroup returns nil if the rule groups namespace of the
+// supplied custom resource is ACTIVE with a rule group with the supplied
+// name.
+func ruleGroupsNamespaceHasGroup(rgn *svcapitypes.RuleGroupsNamespace, group string) error {
+	resp, err := api.DescribeRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.DescribeRuleGroupsNamespaceInput{
+		WorkspaceId: rgn.Spec.WorkspaceID,
+		Name:        rgn.Spec.Name,
+	})
+	if err != nil {
+		return err
+	}
+	desc := resp.RuleGroupsNamespace
+	if *desc.Status.StatusCode != svcsdk.RuleGroupsNamespaceStatusCodeActive {
+		return fmt.Errorf("rule groups namespace status = %s, want ACTIVE", *desc.Status.StatusCode)
+	}
+	if !strings.Contains(string(desc.Data), "name: "+group) {
+		return fmt.Errorf("rule groups namespace data = %q, want a %s rule group", desc.Data, group)
+	}
+	return nil
+}
+
+func TestRuleGroupsNamespace(t *testing.T) {
+	t.Parallel()
+	ws := newActiveWorkspace(t, "rgn-lifecycle-workspace")
+	rgn := &svcapitypes.RuleGroupsNamespace{}
+	rgn.Name = "rgn-lifecycle"
+	rgn.Spec.Name = aws.String("lifecycle")
+	rgn.Spec.WorkspaceID = ws.Status.WorkspaceID
+	rgn.Spec.Configuration = aws.String(newRuleGroupsConfiguration("created"))
+	rgn.Spec.Tags = map[string]*string{"team": aws.String("a")}
+
+	if !t.Run("create", func(t *testing.T) {
+		create(t, rgn)
+		waitForSynced(t, rgn, func() error {
+			if err := ruleGroupsNamespaceHasGroup(rgn, "created"); err != nil {
+				return err
+			}
+			return hasTags(rgn.Status.ACKResourceMetadata.ARN, map[string]string{"team": "a"})
+		})
+	}) {
+		t.FailNow()
+	}
+
+	t.Run("update", func(t *testing.T) {
+		update(t, rgn, func() { rgn.Spec.Configuration = aws.String(newRuleGroupsConfiguration("updated")) })
+		waitForSynced(t, rgn, func() error {
+			return ruleGroupsNamespaceHasGroup(rgn, "updated")
+		})
+	})
+
+	t.Run("tag change", func(t *testing.T) {
+		update(t, rgn, func() {
+			rgn.Spec.Tags = map[string]*string{"team": aws.String("b"), "env": aws.String("prod")}
+		})
+		waitForSynced(t, rgn, func() error {
+			return hasTags(rgn.Status.ACKResourceMetadata.ARN, map[string]string{"team": "b", "env": "prod"})
+		})
+		update(t, rgn, func() { delete(rgn.Spec.Tags, "env") })
+		waitForSynced(t, rgn, func() error {
+			return hasTags(rgn.Status.ACKResourceMetadata.ARN, map[string]string{"team": "b"}, "env")
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleteAndWait(t, rgn)
+		_, err := api.DescribeRuleGroupsNamespaceWithContext(context.TODO(), &svcsdk.DescribeRuleGroupsNamespaceInput{
+			WorkspaceId: rgn.Spec.WorkspaceID,
+			Name:        rgn.Spec.Name,
+		})
+		if !isNotFound(err) {
+			t.Errorf("DescribeRuleGroupsNamespace() error = %v, want a ResourceNotFoundException", err)
+		}
+	})
+}
+
+func TestRuleGroupsNamespaceCreationFailed(t *testing.T) {
+	t.Parallel()
+	ws := newActiveWorkspace(t, "rgn-creation-failed-workspace")
+	rgn := &svcapitypes.RuleGroupsNamespace{}
+	rgn.Name = "rgn-creation-failed"
+	rgn.Spec.Name = aws.String("creation-failed")
+	rgn.Spec.WorkspaceID = ws.Status.WorkspaceID
+	rgn.Spec.Configuration = aws.String(newRuleGroupsConfiguration(invalidMarker))
+
+	create(t, rgn)
+	waitForCondition(t, rgn, ackv1alpha1.ConditionTypeTerminal, corev1.ConditionTrue, "error validating rules", nil)
+
+	// Fixing the configuration recovers the rule groups namespace.
+	update(t, rgn, func() { rgn.Spec.Configuration = aws.String(newRuleGroupsConfiguration("fixed")) })
+	waitForSynced(t, rgn, func() error {
+		return ruleGroupsNamespaceHasGroup(rgn, "fixed")
+	})
+	deleteAndWait(t, rgn)
+}
+
+func TestRuleGroupsNamespaceWorkspaceRef(t *testing.T) {
+	t.Parallel()
+	ws := newActiveWorkspace(t, "rgn-workspace-ref-workspace")
+	rgn := &svcapitypes.RuleGroupsNamespace{}
+	rgn.Name = "rgn-workspace-ref"
+	rgn.Spec.Name = aws.String("workspace-ref")
+	rgn.Spec.WorkspaceRef = &svcapitypes.ResourceReferenceWrapper{
+		From: &svcapitypes.ResourceReference{Name: aws.String(ws.Name)},
+	}
+	rgn.Spec.Configuration = aws.String(newRuleGroupsConfiguration("referenced"))
+
+	create(t, rgn)
+	// The resolved workspace ID is not written back to the custom resource.
+	resolved := &svcapitypes.RuleGroupsNamespace{}
+	resolved.Spec.Name = rgn.Spec.Name
+	resolved.Spec.WorkspaceID = ws.Status.WorkspaceID
+	waitForCondition(t, rgn, ackv1alpha1.ConditionTypeReferencesResolved, corev1.ConditionTrue, "", func() error {
+		return ruleGroupsNamespaceHasGroup(resolved, "referenced")
+	})
+	deleteAndWait(t, rgn)
+}
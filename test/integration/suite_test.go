@@ -0,0 +1,459 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package integration runs the service controller built from cmd/controller
+// against the kube-apiserver of envtest and an in-memory fake of the AMP
+// API, and checks how it reconciles the custom resources. Unlike the e2e
+// tests under test/e2e, the tests need no AWS account.
+//
+// The tests need the envtest binaries (etcd and kube-apiserver), found in
+// the directory set by the KUBEBUILDER_ASSETS environment variable or in
+// /usr/local/kubebuilder/bin. They are skipped without them, unless the
+// -integration flag is set, in which case they fail. Run them with
+// `make test-integration`, which installs the binaries and sets the flag.
+package integration
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+	ampfake "github.com/aws-controllers-k8s/prometheusservice-controller/pkg/amp/fake"
+)
+
+const (
+	accountID = "111111111111"
+	region    = "us-west-2"
+	namespace = "default"
+
+	// timeout bounds the wait for the controller to reconcile a change. The
+	// controller requeues the resources whose creation, update or deletion
+	// is ongoing after up to 30 seconds.
+	timeout = 3 * time.Minute
+	// interval is the delay between two checks of a change.
+	interval = time.Second
+
+	// invalidMarker makes the fake AMP API fail the creation or update of
+	// the resources whose definition contains it.
+	invalidMarker = "invalid"
+)
+
+// integration makes the tests fail rather than being skipped when the envtest
+// binaries are not found.
+var integration = flag.Bool(
+	"integration", false,
+	"Run the integration tests, failing if the envtest binaries are not found",
+)
+
+var (
+	// k8sClient reads and writes the custom resources in the kube-apiserver
+	// of envtest.
+	k8sClient client.Client
+	// api is the fake AMP API the controller calls.
+	api *ampfake.API
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run sets up the kube-apiserver, the fake AMP API and the controller, runs
+// the tests and tears everything down. It returns the exit code of the
+// tests.
+func run(m *testing.M) int {
+	flag.Parse()
+	if !envtestAvailable() {
+		if *integration {
+			fmt.Fprintln(os.Stderr, "unable to run the integration tests: the envtest binaries were not found, set KUBEBUILDER_ASSETS")
+			return 1
+		}
+		fmt.Println("skipping the integration tests: the envtest binaries were not found, run them with `make test-integration`")
+		return 0
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "config", "crd", "bases"),
+			filepath.Join("..", "..", "config", "crd", "common", "bases"),
+		},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start envtest: %v\n", err)
+		return 1
+	}
+	defer testEnv.Stop()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = svcapitypes.AddToScheme(scheme)
+	_ = ackv1alpha1.AddToScheme(scheme)
+	if k8sClient, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create the Kubernetes client: %v\n", err)
+		return 1
+	}
+
+	dir, err := os.MkdirTemp("", "prometheusservice-integration")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create a temporary directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	kubeconfig, err := writeKubeconfig(testEnv, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to write the kubeconfig: %v\n", err)
+		return 1
+	}
+
+	api = newFakeAPI()
+	srv := httptest.NewServer(ampfake.NewHandler(api, accountID))
+	defer srv.Close()
+
+	stop, err := startController(dir, kubeconfig, srv.URL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start the controller: %v\n", err)
+		return 1
+	}
+	defer stop()
+
+	return m.Run()
+}
+
+// envtestAvailable returns true if the envtest binaries can be found.
+func envtestAvailable() bool {
+	dir := os.Getenv("KUBEBUILDER_ASSETS")
+	if dir == "" {
+		dir = "/usr/local/kubebuilder/bin"
+	}
+	_, err := os.Stat(filepath.Join(dir, "kube-apiserver"))
+	return err == nil
+}
+
+// newFakeAPI returns a fake AMP API failing the creation or update of the
+// resources whose definition contains invalidMarker, with the status
+// reasons of AMP.
+func newFakeAPI() *ampfake.API {
+	api := ampfake.New(accountID, region)
+	api.ValidateWorkspace = func(alias *string) error {
+		if strings.Contains(aws.StringValue(alias), invalidMarker) {
+			return errors.New("workspace quota exceeded")
+		}
+		return nil
+	}
+	api.ValidateRuleGroupsNamespace = func(data []byte) error {
+		if strings.Contains(string(data), invalidMarker) {
+			return errors.New("error validating rules: invalid rule group")
+		}
+		return nil
+	}
+	api.ValidateAlertManagerDefinition = func(data []byte) error {
+		if strings.Contains(string(data), invalidMarker) {
+			return errors.New(`error validating Alertmanager config: undefined receiver "invalid" used in route`)
+		}
+		return nil
+	}
+	api.ValidateLogGroup = func(logGroupARN string) error {
+		if strings.Contains(logGroupARN, invalidMarker) {
+			return errors.New("log group not found")
+		}
+		return nil
+	}
+	return api
+}
+
+// writeKubeconfig writes a kubeconfig for an administrator of the envtest
+// cluster in the supplied directory and returns its path.
+func writeKubeconfig(testEnv *envtest.Environment, dir string) (string, error) {
+	user, err := testEnv.AddUser(envtest.User{
+		Name:   "prometheusservice-controller",
+		Groups: []string{"system:masters"},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	kubeconfig, err := user.KubeConfig()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "kubeconfig")
+	return path, os.WriteFile(path, kubeconfig, 0o600)
+}
+
+// startController builds the controller in the supplied directory and starts
+// it with the supplied kubeconfig and AMP endpoint. It returns a function
+// stopping the controller.
+func startController(dir string, kubeconfig string, endpoint string) (func(), error) {
+	bin := filepath.Join(dir, "controller")
+	build := exec.Command("go", "build", "-o", bin, filepath.Join("..", "..", "cmd", "controller"))
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(
+		bin,
+		"--aws-region", region,
+		"--aws-endpoint-url", endpoint,
+		"--aws-identity-endpoint-url", endpoint,
+		"--allow-unsafe-aws-endpoint-urls",
+		"--metrics-addr", "0",
+		"--enable-development-logging",
+		"--log-level", "debug",
+	)
+	cmd.Env = append(
+		os.Environ(),
+		"KUBECONFIG="+kubeconfig,
+		"AWS_ACCESS_KEY_ID=fake",
+		"AWS_SECRET_ACCESS_KEY=fake",
+		"AWS_SESSION_TOKEN=",
+		"AWS_PROFILE=",
+		"AWS_EC2_METADATA_DISABLED=true",
+	)
+	// The logs of the controller are only shown in verbose mode.
+	var logs io.Writer = io.Discard
+	if testing.Verbose() {
+		logs = os.Stderr
+	}
+	cmd.Stdout, cmd.Stderr = logs, logs
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	return func() {
+		_ = cmd.Process.Signal(os.Interrupt)
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			_ = cmd.Process.Kill()
+			<-exited
+		}
+	}, nil
+}
+
+// eventually calls the supplied function until it returns nil, and fails the
+// test with its last error if it still does not after timeout.
+func eventually(t *testing.T, what string, f func() error) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		err := f()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s: %v", what, err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// create creates the supplied custom resource and deletes it, if it still
+// exists, when the test and its subtests complete.
+func create(t *testing.T, obj client.Object) {
+	t.Helper()
+	obj.SetNamespace(namespace)
+	if err := k8sClient.Create(context.TODO(), obj); err != nil {
+		t.Fatalf("unable to create %s: %v", obj.GetName(), err)
+	}
+	t.Cleanup(func() {
+		_ = client.IgnoreNotFound(k8sClient.Delete(context.TODO(), obj))
+	})
+}
+
+// update reads the latest version of the supplied custom resource, applies
+// the supplied mutation to it and updates it, retrying on conflicts with
+// the controller.
+func update(t *testing.T, obj client.Object, mutate func()) {
+	t.Helper()
+	eventually(t, "updating "+obj.GetName(), func() error {
+		if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj); err != nil {
+			return err
+		}
+		mutate()
+		return k8sClient.Update(context.TODO(), obj)
+	})
+}
+
+// deleteAndWait deletes the supplied custom resource and waits until the
+// controller deleted the AMP resource and removed its finalizer.
+func deleteAndWait(t *testing.T, obj client.Object) {
+	t.Helper()
+	if err := k8sClient.Delete(context.TODO(), obj); err != nil {
+		t.Fatalf("unable to delete %s: %v", obj.GetName(), err)
+	}
+	eventually(t, "waiting for the deletion of "+obj.GetName(), func() error {
+		err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%s still exists, conditions: %s", obj.GetName(), conditionsString(conditionsOf(obj)))
+	})
+}
+
+// conditionsOf returns the conditions of the supplied custom resource.
+func conditionsOf(obj client.Object) []*ackv1alpha1.Condition {
+	switch o := obj.(type) {
+	case *svcapitypes.Workspace:
+		return o.Status.Conditions
+	case *svcapitypes.RuleGroupsNamespace:
+		return o.Status.Conditions
+	case *svcapitypes.AlertManagerDefinition:
+		return o.Status.Conditions
+	case *svcapitypes.LoggingConfiguration:
+		return o.Status.Conditions
+	}
+	return nil
+}
+
+// conditionsString formats the supplied conditions for error messages.
+func conditionsString(conditions []*ackv1alpha1.Condition) string {
+	var s []string
+	for _, c := range conditions {
+		s = append(s, fmt.Sprintf("%s=%s (%s)", c.Type, c.Status, aws.StringValue(c.Message)))
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+// hasCondition returns nil if the supplied custom resource has a condition
+// of the supplied type with the supplied status, and with a message
+// containing the supplied message if it is not empty.
+func hasCondition(
+	obj client.Object,
+	conditionType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	message string,
+) error {
+	for _, c := range conditionsOf(obj) {
+		if c.Type == conditionType && c.Status == status &&
+			strings.Contains(aws.StringValue(c.Message), message) {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"%s has no %s=%s condition with message %q, conditions: %s",
+		obj.GetName(), conditionType, status, message, conditionsString(conditionsOf(obj)),
+	)
+}
+
+// waitForCondition waits until the supplied custom resource has a condition
+// of the supplied type with the supplied status, and with a message
+// containing the supplied message if it is not empty. The check function,
+// if any, must also return nil for the latest version of the resource.
+func waitForCondition(
+	t *testing.T,
+	obj client.Object,
+	conditionType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	message string,
+	check func() error,
+) {
+	t.Helper()
+	eventually(t, fmt.Sprintf("waiting for %s to be %s=%s", obj.GetName(), conditionType, status), func() error {
+		if err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj); err != nil {
+			return err
+		}
+		if err := hasCondition(obj, conditionType, status, message); err != nil {
+			return err
+		}
+		if check != nil {
+			return check()
+		}
+		return nil
+	})
+}
+
+// waitForSynced waits until the supplied custom resource is synced and the
+// check function, if any, returns nil for its latest version.
+func waitForSynced(t *testing.T, obj client.Object, check func() error) {
+	t.Helper()
+	waitForCondition(t, obj, ackv1alpha1.ConditionTypeResourceSynced, corev1.ConditionTrue, "", check)
+}
+
+// tagsOf returns the tags of the AMP resource with the supplied ARN.
+func tagsOf(arn *ackv1alpha1.AWSResourceName) (map[string]string, error) {
+	if arn == nil {
+		return nil, errors.New("no ARN")
+	}
+	resp, err := api.ListTagsForResourceWithContext(context.TODO(), &svcsdk.ListTagsForResourceInput{
+		ResourceArn: (*string)(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aws.StringValueMap(resp.Tags), nil
+}
+
+// hasTags returns nil if the AMP resource with the supplied ARN has the
+// supplied tags, and none of the supplied absent tag keys.
+func hasTags(arn *ackv1alpha1.AWSResourceName, tags map[string]string, absent ...string) error {
+	got, err := tagsOf(arn)
+	if err != nil {
+		return err
+	}
+	for k, v := range tags {
+		if got[k] != v {
+			return fmt.Errorf("tag %s = %q, want %q", k, got[k], v)
+		}
+	}
+	for _, k := range absent {
+		if _, ok := got[k]; ok {
+			return fmt.Errorf("tag %s is still set", k)
+		}
+	}
+	return nil
+}
+
+// newActiveWorkspace creates a Workspace custom resource with the supplied
+// name and waits until its workspace is ACTIVE.
+func newActiveWorkspace(t *testing.T, name string) *svcapitypes.Workspace {
+	t.Helper()
+	ws := &svcapitypes.Workspace{}
+	ws.Name = name
+	ws.Spec.Alias = aws.String(name)
+	create(t, ws)
+	waitForSynced(t, ws, func() error {
+		if ws.Status.WorkspaceID == nil {
+			return errors.New("no workspace ID")
+		}
+		return nil
+	})
+	return ws
+}
@@ -0,0 +1,128 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	svcsdk "github.com/aws/aws-sdk-go/service/prometheusservice"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/prometheusservice-controller/apis/v1alpha1"
+)
+
+// describeWorkspace returns the workspace with the supplied ID in the fake
+// AMP API.
+func describeWorkspace(id *string) (*svcsdk.WorkspaceDescription, error) {
+	resp, err := api.DescribeWorkspaceWithContext(context.TODO(), &svcsdk.DescribeWorkspaceInput{
+		WorkspaceId: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Workspace, nil
+}
+
+// workspaceHasAlias returns nil if the workspace of the supplied custom
+// resource is ACTIVE with the supplied alias.
+func workspaceHasAlias(ws *svcapitypes.Workspace, alias string) error {
+	desc, err := describeWorkspace(ws.Status.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if *desc.Status.StatusCode != svcsdk.WorkspaceStatusCodeActive {
+		return fmt.Errorf("workspace status = %s, want ACTIVE", *desc.Status.StatusCode)
+	}
+	if aws.StringValue(desc.Alias) != alias {
+		return fmt.Errorf("workspace alias = %q, want %q", aws.StringValue(desc.Alias), alias)
+	}
+	return nil
+}
+
+func TestWorkspace(t *testing.T) {
+	t.Parallel()
+	ws := &svcapitypes.Workspace{}
+	ws.Name = "workspace-lifecycle"
+	ws.Spec.Alias = aws.String("lifecycle")
+	ws.Spec.Tags = map[string]*string{"team": aws.String("a")}
+
+	if !t.Run("create", func(t *testing.T) {
+		create(t, ws)
+		waitForSynced(t, ws, func() error {
+			if ws.Status.WorkspaceID == nil || ws.Status.RemoteWriteURL == nil {
+				return errors.New("no workspace ID or remote write URL")
+			}
+			if err := workspaceHasAlias(ws, "lifecycle"); err != nil {
+				return err
+			}
+			return hasTags(ws.Status.ACKResourceMetadata.ARN, map[string]string{"team": "a"})
+		})
+	}) {
+		t.FailNow()
+	}
+
+	t.Run("update", func(t *testing.T) {
+		update(t, ws, func() { ws.Spec.Alias = aws.String("lifecycle-updated") })
+		waitForSynced(t, ws, func() error {
+			return workspaceHasAlias(ws, "lifecycle-updated")
+		})
+	})
+
+	t.Run("tag change", func(t *testing.T) {
+		update(t, ws, func() {
+			ws.Spec.Tags = map[string]*string{"team": aws.String("b"), "env": aws.String("prod")}
+		})
+		waitForSynced(t, ws, func() error {
+			return hasTags(ws.Status.ACKResourceMetadata.ARN, map[string]string{"team": "b", "env": "prod"})
+		})
+		update(t, ws, func() { delete(ws.Spec.Tags, "env") })
+		waitForSynced(t, ws, func() error {
+			return hasTags(ws.Status.ACKResourceMetadata.ARN, map[string]string{"team": "b"}, "env")
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		id := ws.Status.WorkspaceID
+		deleteAndWait(t, ws)
+		if _, err := describeWorkspace(id); !isNotFound(err) {
+			t.Errorf("DescribeWorkspace() error = %v, want a ResourceNotFoundException", err)
+		}
+	})
+}
+
+func TestWorkspaceCreationFailed(t *testing.T) {
+	t.Parallel()
+	ws := &svcapitypes.Workspace{}
+	ws.Name = "workspace-creation-failed"
+	ws.Spec.Alias = aws.String(invalidMarker)
+
+	create(t, ws)
+	waitForCondition(t, ws, ackv1alpha1.ConditionTypeTerminal, corev1.ConditionTrue, svcsdk.WorkspaceStatusCodeCreationFailed, nil)
+
+	// A failed workspace can still be deleted.
+	deleteAndWait(t, ws)
+}
+
+// isNotFound returns true if the supplied error is a
+// ResourceNotFoundException of the AMP API.
+func isNotFound(err error) bool {
+	awsErr, ok := ackerr.AWSError(err)
+	return ok && awsErr.Code() == svcsdk.ErrCodeResourceNotFoundException
+}